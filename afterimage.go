@@ -0,0 +1,87 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	afterimageLength     = 6    // 残像に使う過去の位置の数
+	afterimageSpacing    = 2    // 何ステップごとの位置を残像にするか
+	afterimageMoveAlpha  = 0.35 // 最高速度で動いているときの一番新しい残像の不透明度
+	afterimageFocus      = 0.2  // 低速移動中の残像の不透明度
+	afterimageInvincible = 0.3  // 無敵時間中の残像の不透明度
+)
+
+// PlayerTrail は自機の残像を描くための直前の位置の履歴です。新しい位置ほど前に入ります
+type PlayerTrail struct {
+	points [afterimageLength]Position
+	count  int
+	step   int     // 位置を記録するまでの間引きのカウンタ
+	speed  float64 // 直前のステップで動いた距離（自機の最高速度に対する割合）
+}
+
+// recordPlayerTrail は自機が動いた距離を測り、数ステップごとに位置を履歴に加えます。
+// 画面端の回り込みなどで一気に位置が飛んだときは、残像が画面を横切らないよう履歴を捨てます
+func (g *Game) recordPlayerTrail(prevX, prevY float64) {
+	t := &g.playerTrail
+	d := math.Hypot(g.playerX-prevX, g.playerY-prevY)
+	if d > g.ship().Speed*2 {
+		*t = PlayerTrail{}
+		return
+	}
+	t.speed = math.Min(1, d/g.ship().Speed)
+	t.step++
+	if t.step < afterimageSpacing {
+		return
+	}
+	t.step = 0
+	copy(t.points[1:], t.points[:afterimageLength-1])
+	t.points[0] = Position{x: prevX, y: prevY}
+	if t.count < afterimageLength {
+		t.count++
+	}
+}
+
+// afterimageAlpha は今の残像の濃さを返します。速く動いているほど、
+// また低速移動中や無敵時間中は濃くなります
+func (g *Game) afterimageAlpha() float64 {
+	a := g.playerTrail.speed * afterimageMoveAlpha
+	if g.focused {
+		a = math.Max(a, afterimageFocus)
+	}
+	if g.invincibleTimer > 0 {
+		a = math.Max(a, afterimageInvincible)
+	}
+	return a
+}
+
+// drawAfterimages は自機の過去の位置に、古いほど薄くなる自機の形の残像を描きます。
+// 低速移動中は青みがかった色にします
+func (g *Game) drawAfterimages(screen *ebiten.Image) {
+	t := &g.playerTrail
+	base := g.afterimageAlpha()
+	if base <= 0 {
+		return
+	}
+	r, gr, b := 0.0, 255.0, 0.0
+	if g.focused {
+		r, gr, b = 80, 200, 255
+	}
+	for i := t.count - 1; i >= 0; i-- {
+		a := base * float64(afterimageLength-i) / afterimageLength
+		// 色は乗算済みアルファで渡す
+		c := color.RGBA{uint8(r * a), uint8(gr * a), uint8(b * a), uint8(255 * a)}
+		drawShipShape(screen, t.points[i].x, t.points[i].y, c)
+	}
+}
+
+// drawShipShape は自機の形（3本の縦長の四角）を(x, y)に描きます
+func drawShipShape(screen *ebiten.Image, x, y float64, c color.Color) {
+	ebitenutil.DrawRect(screen, x, y, 4, 16, c)
+	ebitenutil.DrawRect(screen, x+8, y-8, 4, 24, c)
+	ebitenutil.DrawRect(screen, x+16, y, 4, 16, c)
+}
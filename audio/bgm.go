@@ -0,0 +1,129 @@
+package audio
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
+)
+
+const bytesPerSample = 4 // 16ビットのステレオの1サンプルあたりのバイト数
+
+// bgmTrack はBGMの定義です。数分ある曲を丸ごとデコードしてメモリに置かないよう、
+// 再生中にファイルから少しずつデコードします
+type bgmTrack struct {
+	path      string
+	volume    float64
+	loopStart float64 // ループの始まり（秒）。ここまでがイントロで、2周目からは飛ばす
+	loopEnd   float64 // ループの終わり（秒）。0なら曲の最後まで
+}
+
+// RegisterBGM はBGMを登録します。ファイルはここでは長さとループ位置を確かめるだけで、
+// 再生するときに開いてデコードしながら鳴らします
+func (sm *SoundManager) RegisterBGM(name, path string, volume, loopStart, loopEnd float64) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	stream, err := mp3.DecodeWithSampleRate(sm.context.SampleRate(), file)
+	if err != nil {
+		return err
+	}
+	track := &bgmTrack{path: path, volume: volume, loopStart: loopStart, loopEnd: loopEnd}
+	intro, loop := sm.loopRange(track, stream.Length())
+	if intro < 0 || loop <= 0 {
+		return fmt.Errorf("BGM(%s)のループ位置が不正です", name)
+	}
+
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	if sm.bgms == nil {
+		sm.bgms = map[string]*bgmTrack{}
+	}
+	sm.bgms[name] = track
+	return nil
+}
+
+// loopRange はイントロとループ部分の長さをバイト数で返します
+func (sm *SoundManager) loopRange(track *bgmTrack, length int64) (intro, loop int64) {
+	toBytes := func(sec float64) int64 {
+		return int64(sec*float64(sm.context.SampleRate())) * bytesPerSample
+	}
+	end := length
+	if track.loopEnd > 0 {
+		end = toBytes(track.loopEnd)
+	}
+	if end > length {
+		return 0, 0
+	}
+	intro = toBytes(track.loopStart)
+	return intro, end - intro
+}
+
+// PlayBGM は指定したBGMをループ再生します。
+// 同じBGMが再生中なら何もせず、未登録の名前なら現在のBGMを止めるだけです。
+func (sm *SoundManager) PlayBGM(name string) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	if sm.bgmName == name && sm.bgmPlayer != nil {
+		return
+	}
+	sm.stopBGM()
+	sm.bgmName = name
+
+	track, exists := sm.bgms[name]
+	if !exists {
+		return
+	}
+
+	file, err := os.Open(track.path)
+	if err != nil {
+		slog.Warn("BGMの再生に失敗", "name", name, "err", err)
+		return
+	}
+	stream, err := mp3.DecodeWithSampleRate(sm.context.SampleRate(), file)
+	if err != nil {
+		file.Close()
+		slog.Warn("BGMの再生に失敗", "name", name, "err", err)
+		return
+	}
+	intro, loop := sm.loopRange(track, stream.Length())
+	player, err := sm.context.NewPlayer(audio.NewInfiniteLoopWithIntro(stream, intro, loop))
+	if err != nil {
+		file.Close()
+		slog.Warn("BGMの再生に失敗", "name", name, "err", err)
+		return
+	}
+	sm.applyBufferSize(player)
+	player.SetVolume(track.volume * sm.gain())
+	if !sm.paused {
+		player.Play()
+	}
+	sm.bgmPlayer = player
+	sm.bgmFile = file
+}
+
+// StopBGM はBGMの再生を停止します
+func (sm *SoundManager) StopBGM() {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sm.stopBGM()
+	sm.bgmName = ""
+}
+
+// stopBGM は再生中のBGMプレーヤーを破棄し、読んでいたファイルを閉じます。呼び出し側でmutexを保持していること。
+func (sm *SoundManager) stopBGM() {
+	if sm.bgmPlayer != nil {
+		sm.bgmPlayer.Close()
+		sm.bgmPlayer = nil
+	}
+	if sm.bgmFile != nil {
+		sm.bgmFile.Close()
+		sm.bgmFile = nil
+	}
+}
@@ -0,0 +1,56 @@
+package audio
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// 受け付ける再生バッファの長さ
+const (
+	MinBufferSize = 10 * time.Millisecond
+	MaxBufferSize = 500 * time.Millisecond
+)
+
+// SampleRates は出力に使えるサンプリング周波数（Hz）です
+var SampleRates = []int{22050, 44100, 48000}
+
+// Config は音声の出力の設定です
+type Config struct {
+	SampleRate int           // サンプリング周波数（Hz）
+	BufferSize time.Duration // 再生バッファの長さ。長いほど音が途切れにくいが、鳴るまでが遅れる（0ならEbitenの既定）
+}
+
+// DefaultConfig は既定の出力の設定を返します
+func DefaultConfig() Config {
+	return Config{SampleRate: 44100}
+}
+
+// Validate は出力の設定が使える値かを確かめます
+func (c Config) Validate() error {
+	if err := validateSampleRate(c.SampleRate); err != nil {
+		return err
+	}
+	if c.BufferSize != 0 && (c.BufferSize < MinBufferSize || c.BufferSize > MaxBufferSize) {
+		return fmt.Errorf("再生バッファの長さが不正です: %v（%v〜%vにしてください）", c.BufferSize, MinBufferSize, MaxBufferSize)
+	}
+	return nil
+}
+
+// validateSampleRate はサンプリング周波数がSampleRatesのいずれかかを確かめます
+func validateSampleRate(rate int) error {
+	for _, r := range SampleRates {
+		if rate == r {
+			return nil
+		}
+	}
+	return fmt.Errorf("サンプリング周波数が不正です: %d（%vのいずれかにしてください）", rate, SampleRates)
+}
+
+// applyBufferSize は設定した再生バッファの長さをプレーヤーに反映します
+func (sm *SoundManager) applyBufferSize(player *audio.Player) {
+	if sm.config.BufferSize > 0 {
+		player.SetBufferSize(sm.config.BufferSize)
+	}
+}
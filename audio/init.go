@@ -1,31 +1,155 @@
 package audio
 
 import (
+	"log/slog"
 	"os"
+	"time"
 )
 
-// Initialize は効果音システムを初期化します
-func Initialize() error {
+// soundDef は起動時に読み込む効果音の定義です
+type soundDef struct {
+	name      string
+	path      string
+	volume    float64
+	priority  int
+	maxVoices int // 同時に鳴らせる数（0なら上限なし）
+	coalesce  int // この間隔（ミリ秒）より短く続けて鳴らしたときは1回にまとめる
+}
+
+var soundDefs = []soundDef{
+	{"shoot", "assets/audio/se/SNES-Shooter02-01(Shoot).mp3", 0.7, PriorityLow, 2, 30},
+	{"enemyShot", "assets/audio/se/SNES-Shooter02-03(Shoot).mp3", 0.5, PriorityLow, 3, 30},
+	{"hit", "assets/audio/se/SNES-Shooter02-12(Damage).mp3", 0.3, PriorityLow, 3, 30},
+	{"explosion", "assets/audio/se/SNES-Shooter02-08(Damage).mp3", 0.8, PriorityNormal, 3, 30},
+	{"beam", "assets/audio/se/SNES-Shooter02-06(Missile).mp3", 0.8, PriorityNormal, 2, 30},
+	{"bomb", "assets/audio/se/SNES-Shooter02-05(Bomb).mp3", 0.9, PriorityHigh, 1, 0},
+	{"bossShot", "assets/audio/se/SNES-Shooter02-07(Special_Weapon).mp3", 0.8, PriorityHigh, 2, 30},
+	{"warning", "assets/audio/se/SNES-Shooter02-13(Select).mp3", 0.9, PriorityHigh, 1, 0},
+	{"graze", "assets/audio/se/SNES-Shooter02-04(Shoot).mp3", 0.25, PriorityLow, 2, 50},
+	// メニューの操作音（カーソル移動・決定・取り消し）
+	{"menuCursor", "assets/audio/se/SNES-Shooter02-14(Select).mp3", 0.5, PriorityHigh, 1, 30},
+	{"menuConfirm", "assets/audio/se/SNES-Shooter02-15(Select).mp3", 0.7, PriorityHigh, 1, 30},
+	{"menuCancel", "assets/audio/se/SNES-Shooter02-11(Damage).mp3", 0.5, PriorityHigh, 1, 30},
+}
+
+// variantDefs は効果音に追加する差分です。激しい戦闘で同じ音ばかりが続かないよう、
+// 同じ名前の効果音を鳴らすたびに元の音と差分の中から選び分けます
+var variantDefs = []struct {
+	name  string
+	paths []string
+	mode  VariantMode
+}{
+	{"explosion", []string{"assets/audio/se/SNES-Shooter02-09(Damage).mp3", "assets/audio/se/SNES-Shooter02-10(Damage).mp3"}, VariantRandom},
+}
+
+// toneDefs は起動時に合成する効果音の定義です
+var toneDefs = []struct {
+	soundDef
+	freq, seconds float64
+}{
+	{soundDef{name: "rumble", volume: 0.6, priority: PriorityLow, maxVoices: 2}, 45, 1.0},        // ボス接近時の低いうなり
+	{soundDef{name: "menuDenied", volume: 0.5, priority: PriorityHigh, maxVoices: 1}, 110, 0.25}, // メニューで今は選べない項目を選んだときのブザー
+	{soundDef{name: "tally", volume: 0.4, priority: PriorityNormal, maxVoices: 1}, 1320, 0.06},   // ステージクリアの集計の行が出たとき
+	{soundDef{name: "tallyTotal", volume: 0.5, priority: PriorityHigh, maxVoices: 1}, 880, 0.3},  // ステージクリアの集計の合計が出たとき
+}
+
+// bgmDefs はBGMの定義です。ファイルが置かれていない曲は無音で進行します。
+// loopStartとloopEnd（秒）でループする区間を指定すると、loopStartまではイントロとして最初の1回だけ鳴らします
+var bgmDefs = []struct {
+	name, path         string
+	volume             float64
+	loopStart, loopEnd float64
+}{
+	{"stage", "assets/audio/bgm/stage.mp3", 0.5, 0, 0},
+	{"boss", "assets/audio/bgm/boss.mp3", 0.5, 0, 0},
+}
+
+// Initialize は設定に従って効果音システムを初期化し、効果音とBGMを読み込みます。
+// 出力の設定は最初の呼び出しのものだけが使われます（変えたときは再起動が必要です）
+func Initialize(config Config) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if instance == nil {
+		instance = newSoundManager(config)
+		slog.Info("音声の出力を開始しました", "sampleRate", config.SampleRate, "bufferSize", config.BufferSize)
+	} else if instance.config != config {
+		slog.Warn("音声の出力の設定は再起動するまで反映されません", "sampleRate", config.SampleRate, "bufferSize", config.BufferSize)
+	}
+
+	for _, def := range soundDefs {
+		if err := loadSoundDef(def); err != nil {
+			return err
+		}
+	}
+
 	soundManager := GetInstance()
+	for _, def := range variantDefs {
+		for _, path := range def.paths {
+			if err := loadVariant(def.name, path); err != nil {
+				return err
+			}
+		}
+		soundManager.SetVariantMode(def.name, def.mode)
+	}
 
-	// 効果音ファイルを読み込む
-	shootSound, err := os.Open("assets/audio/se/SNES-Shooter02-01(Shoot).mp3")
+	for _, def := range toneDefs {
+		soundManager.LoadTone(def.name, def.freq, def.seconds)
+		soundManager.SetVolume(def.name, def.volume)
+		soundManager.SetPriority(def.name, def.priority)
+		soundManager.SetLimit(def.name, def.maxVoices, time.Duration(def.coalesce)*time.Millisecond)
+	}
+
+	for _, def := range bgmDefs {
+		err := soundManager.RegisterBGM(def.name, def.path, def.volume, def.loopStart, def.loopEnd)
+		if os.IsNotExist(err) {
+			slog.Info("BGMのファイルがないため、この曲は無音で進行します", "name", def.name, "path", def.path)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadSoundDef は定義に従って音声ファイルを読み込み、音量などを設定します
+func loadSoundDef(def soundDef) error {
+	soundManager := GetInstance()
+
+	// 音声ファイルを読み込む
+	file, err := os.Open(def.path)
 	if err != nil {
 		return err
 	}
 
 	// 効果音を登録
-	if err := soundManager.LoadSound("shoot", shootSound); err != nil {
-		shootSound.Close()
+	err = soundManager.LoadSound(def.name, file)
+	file.Close()
+	if err != nil {
 		return err
 	}
 
-	// ファイルを閉じる
-	shootSound.Close()
-
-	// デフォルトの音量とパンを設定
-	soundManager.SetVolume("shoot", 0.7)
-	soundManager.SetPan("shoot", 0.0)
+	// デフォルトの音量・パン・優先度を設定
+	soundManager.SetVolume(def.name, def.volume)
+	soundManager.SetPan(def.name, 0.0)
+	soundManager.SetPriority(def.name, def.priority)
+	soundManager.SetLimit(def.name, def.maxVoices, time.Duration(def.coalesce)*time.Millisecond)
+	slog.Debug("音声を読み込みました", "name", def.name, "path", def.path)
+	return nil
+}
 
+// loadVariant は音声ファイルを読み込み、効果音の差分として追加します
+func loadVariant(name, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := GetInstance().AddVariant(name, file); err != nil {
+		return err
+	}
+	slog.Debug("効果音の差分を読み込みました", "name", name, "path", path)
 	return nil
 }
@@ -0,0 +1,39 @@
+package audio
+
+import "time"
+
+// SetLimit は効果音の同時再生数の上限と、続けて鳴らしたときにまとめる間隔を設定します。
+// 一度に大量の敵が爆発したときなどに、同じ音が重なって音割れしないようにします。
+// maxVoicesが0なら上限なし、coalesceが0なら間隔を空けずに鳴らします
+func (sm *SoundManager) SetLimit(name string, maxVoices int, coalesce time.Duration) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sound, exists := sm.sounds[name]
+	if !exists {
+		return
+	}
+
+	sound.maxVoices = maxVoices
+	sound.coalesce = coalesce
+}
+
+// throttled は効果音を今鳴らすと上限を超えるならtrueを返します。
+// 前回鳴らしてからまとめる間隔が経っていないか、同じ音がすでに上限まで鳴っていれば鳴らしません。
+// 呼び出し側でmutexを保持していること。
+func (sm *SoundManager) throttled(name string, sound *SoundEffect) bool {
+	if sound.coalesce > 0 && time.Since(sound.lastPlayed) < sound.coalesce {
+		return true
+	}
+	if sound.maxVoices == 0 {
+		return false
+	}
+	playing := 0
+	for i := range sm.voices {
+		v := &sm.voices[i]
+		if v.player != nil && v.name == name && (v.paused || v.player.IsPlaying()) {
+			playing++
+		}
+	}
+	return playing >= sound.maxVoices
+}
@@ -0,0 +1,27 @@
+package audio
+
+// SetMasterVolume は全体の音量（0〜1）とミュートを設定し、鳴っている効果音とBGMにもすぐ反映します
+func (sm *SoundManager) SetMasterVolume(volume float64, muted bool) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sm.masterVolume = volume
+	sm.muted = muted
+	for i := range sm.voices {
+		v := &sm.voices[i]
+		if v.player != nil {
+			v.player.SetVolume(v.volume * sm.gain())
+		}
+	}
+	if sm.bgmPlayer != nil {
+		sm.bgmPlayer.SetVolume(sm.bgms[sm.bgmName].volume * sm.gain())
+	}
+}
+
+// gain は効果音やBGMの音量に掛ける全体の音量です。呼び出し側でmutexを保持していること。
+func (sm *SoundManager) gain() float64 {
+	if sm.muted {
+		return 0
+	}
+	return sm.masterVolume
+}
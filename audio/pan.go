@@ -0,0 +1,57 @@
+package audio
+
+import (
+	"io"
+	"math"
+)
+
+// panStream は16bitステレオのPCMストリームに左右の音量差をつけるラッパーです
+type panStream struct {
+	io.ReadSeeker
+	pan  float64 // -1.0 (左) から 1.0 (右)
+	rest []byte  // 前回のReadで4バイト（左右1組）にそろわなかった読み残し
+}
+
+// newPanStream はパンを適用したストリームを作成します
+func newPanStream(src io.ReadSeeker, pan float64) *panStream {
+	return &panStream{ReadSeeker: src, pan: math.Max(-1, math.Min(1, pan))}
+}
+
+// Read はPCMデータを読み込み、左右チャンネルの音量を調整します。
+// 元のストリームが4バイトの倍数で返さなくても左右の組がずれないよう、
+// 端数は次のReadに持ち越し、左右がそろった分だけを返します
+func (s *panStream) Read(p []byte) (int, error) {
+	if len(p) < 4 {
+		return 0, io.ErrShortBuffer
+	}
+	p = p[:len(p)&^3]
+	n := copy(p, s.rest)
+	s.rest = s.rest[:0]
+	var err error
+	for n < 4 && err == nil {
+		var m int
+		m, err = s.ReadSeeker.Read(p[n:])
+		n += m
+	}
+	aligned := n &^ 3
+	s.rest = append(s.rest, p[aligned:n]...)
+
+	// 中央に寄せた側はそのまま、反対側を減衰させる
+	ls := math.Min(1-s.pan, 1)
+	rs := math.Min(1+s.pan, 1)
+	for i := 0; i < aligned; i += 4 {
+		lc := int16(float64(int16(p[i])|int16(p[i+1])<<8) * ls)
+		rc := int16(float64(int16(p[i+2])|int16(p[i+3])<<8) * rs)
+		p[i] = byte(lc)
+		p[i+1] = byte(lc >> 8)
+		p[i+2] = byte(rc)
+		p[i+3] = byte(rc >> 8)
+	}
+	return aligned, err
+}
+
+// Seek は読み残しを捨ててから元のストリームの位置を変えます
+func (s *panStream) Seek(offset int64, whence int) (int64, error) {
+	s.rest = s.rest[:0]
+	return s.ReadSeeker.Seek(offset, whence)
+}
@@ -0,0 +1,73 @@
+package audio
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// chunkReader は決まったバイト数ずつしか返さないストリームです
+type chunkReader struct {
+	io.ReadSeeker
+	chunk int
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(p) > r.chunk {
+		p = p[:r.chunk]
+	}
+	return r.ReadSeeker.Read(p)
+}
+
+func TestPanStreamKeepsChannelsAligned(t *testing.T) {
+	// 左が1000、右が-1000のサンプルを8組
+	var pcm []byte
+	for i := 0; i < 8; i++ {
+		l, r := int16(1000), int16(-1000)
+		pcm = append(pcm, byte(l), byte(l>>8), byte(r), byte(r>>8))
+	}
+	tests := []struct {
+		name  string
+		chunk int
+		buf   int
+		pan   float64
+		wantL int16
+		wantR int16
+	}{
+		{"aligned reads", 4, 16, 0, 1000, -1000},
+		{"odd reads", 3, 16, 0, 1000, -1000},
+		{"odd buffer", 5, 7, 0, 1000, -1000},
+		{"odd reads panned left", 7, 10, -0.5, 1000, -500},
+		{"odd reads panned right", 1, 6, 0.5, 500, -1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newPanStream(&chunkReader{ReadSeeker: bytes.NewReader(pcm), chunk: tt.chunk}, tt.pan)
+			var out []byte
+			buf := make([]byte, tt.buf)
+			for {
+				n, err := s.Read(buf)
+				if n%4 != 0 {
+					t.Fatalf("Read returned %d bytes, want a multiple of 4", n)
+				}
+				out = append(out, buf[:n]...)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if len(out) != len(pcm) {
+				t.Fatalf("read %d bytes, want %d", len(out), len(pcm))
+			}
+			for i := 0; i < len(out); i += 4 {
+				l := int16(out[i]) | int16(out[i+1])<<8
+				r := int16(out[i+2]) | int16(out[i+3])<<8
+				if l != tt.wantL || r != tt.wantR {
+					t.Fatalf("frame %d = (%d, %d), want (%d, %d)", i/4, l, r, tt.wantL, tt.wantR)
+				}
+			}
+		})
+	}
+}
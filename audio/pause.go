@@ -0,0 +1,44 @@
+package audio
+
+// PauseAll は再生中の効果音とBGMをその位置で一時停止します。
+// 一時停止中に鳴らした音は、ResumeAllを呼ぶまで再生を始めません
+func (sm *SoundManager) PauseAll() {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	if sm.paused {
+		return
+	}
+	sm.paused = true
+	for i := range sm.voices {
+		v := &sm.voices[i]
+		if v.player != nil && v.player.IsPlaying() {
+			v.player.Pause()
+			v.paused = true
+		}
+	}
+	if sm.bgmPlayer != nil {
+		sm.bgmPlayer.Pause()
+	}
+}
+
+// ResumeAll はPauseAllで止めた効果音とBGMを止めた位置から再開します
+func (sm *SoundManager) ResumeAll() {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	if !sm.paused {
+		return
+	}
+	sm.paused = false
+	for i := range sm.voices {
+		v := &sm.voices[i]
+		if v.player != nil && v.paused {
+			v.player.Play()
+		}
+		v.paused = false
+	}
+	if sm.bgmPlayer != nil {
+		sm.bgmPlayer.Play()
+	}
+}
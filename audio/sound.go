@@ -3,6 +3,7 @@ package audio
 import (
 	"bytes"
 	"io"
+	"math"
 	"sync"
 	"time"
 
@@ -11,38 +12,75 @@ import (
 )
 
 const (
-	MaxChannels   = 8               // 最大チャンネル数
-	SoundDuration = 1 * time.Second // 効果音の再生時間
+	MaxChannels = 8 // 全効果音で共有する最大チャンネル数
+
+	edgeAttenuation = 0.4 // 画面端で再生したときの音量の減衰率
+)
+
+// 効果音の優先度（値が大きいほど他の音に割り込まれにくい）
+const (
+	PriorityLow    = 0  // 連射音など、途切れても問題ない音
+	PriorityNormal = 10 // 通常の効果音
+	PriorityHigh   = 20 // ボスの攻撃など、必ず聞かせたい音
 )
 
 type SoundEffect struct {
-	players    []*audio.Player // 複数のプレーヤーを保持
-	volume     float64
-	pan        float64       // -1.0 (左) から 1.0 (右)
-	isPlaying  []bool        // 各チャンネルの再生状態
-	stopTimers []*time.Timer // 各チャンネルの停止タイマー
-	mutex      sync.Mutex
+	pcm      []byte // デコード済みのPCMデータ（全チャンネルで共有）
+	volume   float64
+	pan      float64 // -1.0 (左) から 1.0 (右)
+	priority int
+
+	maxVoices  int           // 同時に鳴らせる数（0なら上限なし）
+	coalesce   time.Duration // この間隔より短く続けて鳴らしたときは1回にまとめる
+	lastPlayed time.Time     // 最後に鳴らし始めた時刻
+
+	variants    [][]byte    // pcmと鳴らし分ける差分のPCMデータ
+	variantMode VariantMode // 差分の選び方
+	lastVariant int         // 最後に鳴らした差分（0がpcm、1以降がvariants、-1ならまだ鳴らしていない）
+}
+
+// voice はチャンネルプール内の1チャンネルを表します
+type voice struct {
+	player   *audio.Player
+	name     string // 再生中の効果音名
+	priority int
+	serial   uint64  // 再生を開始した順番（古い音ほど小さい）
+	paused   bool    // PauseAllで一時停止している（再生中と同じく扱う）
+	volume   float64 // 全体の音量を掛ける前の音量
 }
 
 type SoundManager struct {
-	context *audio.Context
-	sounds  map[string]*SoundEffect
-	mutex   sync.Mutex
+	context      *audio.Context
+	sounds       map[string]*SoundEffect
+	voices       [MaxChannels]voice
+	serial       uint64
+	bgmName      string               // 再生中のBGM名
+	bgmPlayer    *audio.Player        // BGM専用のプレーヤー（チャンネルプールとは別枠）
+	bgmFile      io.Closer            // 再生中のBGMのファイル（デコードしながら読むので開いたままにする）
+	bgms         map[string]*bgmTrack // 登録したBGM
+	paused       bool                 // PauseAllで一時停止中か
+	masterVolume float64              // 全体の音量（0〜1）
+	muted        bool                 // ミュート中か
+	config       Config               // 作ったときの出力の設定
+	mutex        sync.Mutex
 }
 
-var (
-	instance *SoundManager
-	once     sync.Once
-)
+// instance はInitializeで作ったSoundManagerです。
+// オーディオコンテキストは1つのプロセスで1度しか作れないため、2回目以降のInitializeでも使い回します
+var instance *SoundManager
+
+// newSoundManager は設定に従ってオーディオコンテキストを作り、空のSoundManagerを返します
+func newSoundManager(config Config) *SoundManager {
+	return &SoundManager{
+		context:      audio.NewContext(config.SampleRate),
+		sounds:       make(map[string]*SoundEffect),
+		masterVolume: 1,
+		config:       config,
+	}
+}
 
-// GetInstance はSoundManagerのシングルトンインスタンスを返します
+// GetInstance はInitializeで作ったSoundManagerを返します。Initializeより前はnilです
 func GetInstance() *SoundManager {
-	once.Do(func() {
-		instance = &SoundManager{
-			context: audio.NewContext(44100),
-			sounds:  make(map[string]*SoundEffect),
-		}
-	})
 	return instance
 }
 
@@ -51,162 +89,185 @@ func (sm *SoundManager) LoadSound(name string, reader io.Reader) error {
 	sm.mutex.Lock()
 	defer sm.mutex.Unlock()
 
-	// ファイルの内容をメモリに読み込む
-	buf := new(bytes.Buffer)
-	if _, err := io.Copy(buf, reader); err != nil {
+	pcm, err := sm.decode(reader)
+	if err != nil {
 		return err
 	}
 
-	// 複数のプレーヤーを作成
-	players := make([]*audio.Player, MaxChannels)
-	isPlaying := make([]bool, MaxChannels)
-	stopTimers := make([]*time.Timer, MaxChannels)
-
-	for i := 0; i < MaxChannels; i++ {
-		// MP3ファイルをデコード
-		decoded, err := mp3.Decode(sm.context, bytes.NewReader(buf.Bytes()))
-		if err != nil {
-			return err
-		}
-
-		// プレーヤーを作成（ループなし）
-		player, err := sm.context.NewPlayer(decoded)
-		if err != nil {
-			return err
-		}
-
-		players[i] = player
-		isPlaying[i] = false
-		stopTimers[i] = nil
-	}
-
 	// サウンドエフェクトを作成
 	sound := &SoundEffect{
-		players:    players,
-		volume:     1.0,
-		pan:        0.0,
-		isPlaying:  isPlaying,
-		stopTimers: stopTimers,
+		pcm:         pcm,
+		volume:      1.0,
+		pan:         0.0,
+		priority:    PriorityNormal,
+		lastVariant: -1,
 	}
 
 	sm.sounds[name] = sound
 	return nil
 }
 
+// decode はMP3を一度だけデコードし、メモリに保持するPCMデータにします
+func (sm *SoundManager) decode(reader io.Reader) ([]byte, error) {
+	decoded, err := mp3.DecodeWithSampleRate(sm.context.SampleRate(), reader)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(decoded)
+}
+
 // Play は指定された効果音を再生します
 func (sm *SoundManager) Play(name string) {
 	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
 	sound, exists := sm.sounds[name]
-	sm.mutex.Unlock()
+	if !exists {
+		return
+	}
+
+	sm.playVoice(name, sound, sound.pan, sound.volume)
+}
+
+// PlayAt は画面上のx座標に応じて左右の位置と音量を決めて効果音を再生します。
+// 画面中央では設定どおりに、端に寄るほど片側から小さく聞こえます。
+func (sm *SoundManager) PlayAt(name string, x, screenWidth float64) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
 
+	sound, exists := sm.sounds[name]
 	if !exists {
 		return
 	}
 
-	sound.mutex.Lock()
-	defer sound.mutex.Unlock()
+	pan := math.Max(-1, math.Min(1, x/screenWidth*2-1))
+	volume := sound.volume * (1 - edgeAttenuation*math.Abs(pan))
+	sm.playVoice(name, sound, pan, volume)
+}
 
-	// 使用可能なチャンネルを探す
-	channel := -1
-	for i := 0; i < MaxChannels; i++ {
-		if !sound.isPlaying[i] {
-			channel = i
-			break
-		}
+// playVoice はチャンネルを確保して効果音を再生します。呼び出し側でmutexを保持していること。
+func (sm *SoundManager) playVoice(name string, sound *SoundEffect, pan, volume float64) {
+	if sm.throttled(name, sound) {
+		return
+	}
+	v := sm.allocateVoice(sound.priority)
+	if v == nil {
+		// より優先度の高い音で全チャンネルが埋まっている
+		return
 	}
 
-	// 使用可能なチャンネルがない場合は、最初のチャンネルを使用
-	if channel == -1 {
-		channel = 0
-		// 現在再生中の音を停止
-		if sound.stopTimers[channel] != nil {
-			sound.stopTimers[channel].Stop()
+	// 共有したPCMデータから新しいプレーヤーを作成（ループなし）。差分があればその中から選ぶ
+	pcm := sound.pick()
+	var player *audio.Player
+	if pan == 0 {
+		player = sm.context.NewPlayerFromBytes(pcm)
+	} else {
+		var err error
+		player, err = sm.context.NewPlayer(newPanStream(bytes.NewReader(pcm), pan))
+		if err != nil {
+			return
 		}
-		sound.players[channel].Pause()
-		sound.players[channel].Rewind()
+	}
+	sm.applyBufferSize(player)
+	player.SetVolume(volume * sm.gain())
+	if !sm.paused {
+		player.Play()
 	}
 
-	// 新しい音を再生
-	sound.players[channel].Play()
-	sound.isPlaying[channel] = true
-
-	// 既存のタイマーがあれば停止
-	if sound.stopTimers[channel] != nil {
-		sound.stopTimers[channel].Stop()
+	sound.lastPlayed = time.Now()
+	sm.serial++
+	*v = voice{
+		player:   player,
+		name:     name,
+		priority: sound.priority,
+		serial:   sm.serial,
+		paused:   sm.paused,
+		volume:   volume,
 	}
+}
 
-	// 新しい停止タイマーを設定
-	sound.stopTimers[channel] = time.AfterFunc(SoundDuration, func() {
-		sound.mutex.Lock()
-		defer sound.mutex.Unlock()
+// allocateVoice は再生に使うチャンネルを選びます。
+// 空きがなければ優先度が最も低く最も古い音を止めて奪い、
+// 奪える音がなければnilを返します。呼び出し側でmutexを保持していること。
+func (sm *SoundManager) allocateVoice(priority int) *voice {
+	var victim *voice
+	for i := range sm.voices {
+		v := &sm.voices[i]
+		if v.player == nil || (!v.paused && !v.player.IsPlaying()) {
+			victim = v
+			break
+		}
+		if victim == nil || v.priority < victim.priority ||
+			(v.priority == victim.priority && v.serial < victim.serial) {
+			victim = v
+		}
+	}
 
-		if sound.isPlaying[channel] {
-			sound.players[channel].Pause()
-			sound.players[channel].Rewind()
-			sound.isPlaying[channel] = false
-			sound.stopTimers[channel] = nil
+	if victim.player != nil {
+		if (victim.paused || victim.player.IsPlaying()) && victim.priority > priority {
+			return nil
 		}
-	})
+		victim.player.Close()
+		victim.player = nil
+	}
+	return victim
 }
 
 // SetVolume は効果音の音量を設定します
 func (sm *SoundManager) SetVolume(name string, volume float64) {
 	sm.mutex.Lock()
-	sound, exists := sm.sounds[name]
-	sm.mutex.Unlock()
+	defer sm.mutex.Unlock()
 
+	sound, exists := sm.sounds[name]
 	if !exists {
 		return
 	}
 
-	sound.mutex.Lock()
-	defer sound.mutex.Unlock()
-
 	sound.volume = volume
-	for _, player := range sound.players {
-		player.SetVolume(volume)
+	for i := range sm.voices {
+		if sm.voices[i].player != nil && sm.voices[i].name == name {
+			sm.voices[i].volume = volume
+			sm.voices[i].player.SetVolume(volume * sm.gain())
+		}
 	}
 }
 
 // SetPan は効果音の左右位置を設定します
 func (sm *SoundManager) SetPan(name string, pan float64) {
 	sm.mutex.Lock()
-	sound, exists := sm.sounds[name]
-	sm.mutex.Unlock()
+	defer sm.mutex.Unlock()
 
+	sound, exists := sm.sounds[name]
 	if !exists {
 		return
 	}
 
-	sound.mutex.Lock()
-	defer sound.mutex.Unlock()
-
 	sound.pan = pan
-	// TODO: パンニングの実装
 }
 
-// Stop は効果音の再生を停止します
-func (sm *SoundManager) Stop(name string) {
+// SetPriority は効果音の優先度を設定します
+func (sm *SoundManager) SetPriority(name string, priority int) {
 	sm.mutex.Lock()
-	sound, exists := sm.sounds[name]
-	sm.mutex.Unlock()
+	defer sm.mutex.Unlock()
 
+	sound, exists := sm.sounds[name]
 	if !exists {
 		return
 	}
 
-	sound.mutex.Lock()
-	defer sound.mutex.Unlock()
+	sound.priority = priority
+}
+
+// Stop は効果音の再生を停止します
+func (sm *SoundManager) Stop(name string) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
 
-	for i := 0; i < MaxChannels; i++ {
-		if sound.isPlaying[i] {
-			if sound.stopTimers[i] != nil {
-				sound.stopTimers[i].Stop()
-				sound.stopTimers[i] = nil
-			}
-			sound.players[i].Pause()
-			sound.players[i].Rewind()
-			sound.isPlaying[i] = false
+	for i := range sm.voices {
+		v := &sm.voices[i]
+		if v.player != nil && v.name == name {
+			v.player.Close()
+			v.player = nil
 		}
 	}
 }
@@ -0,0 +1,34 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// LoadTone は指定した周波数の正弦波を合成して効果音として登録します。
+// 音声ファイルを用意しなくても鳴らせる、低いうなりなどの単純な音に使います。
+// 出だしと終わりは短くフェードさせ、続けて鳴らしてもぷつぷつ言わないようにします
+func (sm *SoundManager) LoadTone(name string, freq, seconds float64) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	rate := sm.context.SampleRate()
+	samples := int(float64(rate) * seconds)
+	fade := rate / 10
+	pcm := make([]byte, samples*4) // 16ビットのステレオ
+	for i := 0; i < samples; i++ {
+		env := math.Min(1, math.Min(float64(i), float64(samples-i))/float64(fade))
+		// 周波数をわずかに揺らしてうなりにする
+		t := float64(i) / float64(rate)
+		v := math.Sin(2*math.Pi*freq*t+0.6*math.Sin(2*math.Pi*3*t)) * env
+		s := uint16(int16(v * math.MaxInt16 * 0.8))
+		binary.LittleEndian.PutUint16(pcm[i*4:], s)
+		binary.LittleEndian.PutUint16(pcm[i*4+2:], s)
+	}
+	sm.sounds[name] = &SoundEffect{
+		pcm:         pcm,
+		volume:      1.0,
+		priority:    PriorityNormal,
+		lastVariant: -1,
+	}
+}
@@ -0,0 +1,76 @@
+package audio
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+)
+
+// VariantMode は効果音の差分の選び方です
+type VariantMode int
+
+const (
+	VariantRandom     VariantMode = iota // 毎回ランダムに選ぶ（直前と同じ差分は続けない）
+	VariantRoundRobin                    // 登録した順に繰り返す
+)
+
+// AddVariant は登録済みの効果音に、同じ名前で鳴らし分ける差分を追加します。
+// 音量・優先度・同時再生数の上限は元の効果音の設定を差分全体で共有します
+func (sm *SoundManager) AddVariant(name string, reader io.Reader) error {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sound, exists := sm.sounds[name]
+	if !exists {
+		return fmt.Errorf("差分を追加する効果音がありません: %s", name)
+	}
+	pcm, err := sm.decode(reader)
+	if err != nil {
+		return err
+	}
+	sound.variants = append(sound.variants, pcm)
+	return nil
+}
+
+// SetVariantMode は効果音の差分の選び方を設定します
+func (sm *SoundManager) SetVariantMode(name string, mode VariantMode) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sound, exists := sm.sounds[name]
+	if !exists {
+		return
+	}
+
+	sound.variantMode = mode
+}
+
+// pick は次に鳴らすPCMデータを選びます。差分がなければいつも同じデータです。
+// 呼び出し側でmutexを保持していること。
+func (s *SoundEffect) pick() []byte {
+	count := len(s.variants) + 1
+	if count == 1 {
+		return s.pcm
+	}
+	var i int
+	switch s.variantMode {
+	case VariantRoundRobin:
+		i = (s.lastVariant + 1) % count
+	default:
+		if s.lastVariant < 0 {
+			// 最初はすべての差分から選ぶ
+			i = rand.Intn(count)
+			break
+		}
+		// 直前の差分を除いた中から選び、同じ音が続かないようにする
+		i = rand.Intn(count - 1)
+		if i >= s.lastVariant {
+			i++
+		}
+	}
+	s.lastVariant = i
+	if i == 0 {
+		return s.pcm
+	}
+	return s.variants[i-1]
+}
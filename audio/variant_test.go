@@ -0,0 +1,53 @@
+package audio
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// newTestVariants は元の音と2つの差分を持つ効果音を作ります。選ばれた差分はPCMの先頭のバイトで分かります
+func newTestVariants(mode VariantMode) *SoundEffect {
+	return &SoundEffect{
+		pcm:         []byte{0},
+		variants:    [][]byte{{1}, {2}},
+		variantMode: mode,
+		lastVariant: -1,
+	}
+}
+
+func TestPickRoundRobinStartsWithBaseSound(t *testing.T) {
+	s := newTestVariants(VariantRoundRobin)
+	var got []byte
+	for i := 0; i < 5; i++ {
+		got = append(got, s.pick()[0])
+	}
+	if want := []byte{0, 1, 2, 0, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("picks = %v, want %v", got, want)
+	}
+}
+
+func TestPickRandomFirstPlayCanUseEverySound(t *testing.T) {
+	rand.Seed(1)
+	seen := map[byte]bool{}
+	for i := 0; i < 100; i++ {
+		seen[newTestVariants(VariantRandom).pick()[0]] = true
+	}
+	for _, v := range []byte{0, 1, 2} {
+		if !seen[v] {
+			t.Errorf("first pick never chose sound %d", v)
+		}
+	}
+}
+
+func TestPickRandomDoesNotRepeat(t *testing.T) {
+	s := newTestVariants(VariantRandom)
+	prev := s.pick()[0]
+	for i := 0; i < 100; i++ {
+		cur := s.pick()[0]
+		if cur == prev {
+			t.Fatalf("pick %d repeated sound %d", i, cur)
+		}
+		prev = cur
+	}
+}
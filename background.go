@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	_ "image/png" // タイル画像のデコード用
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const defaultStarCount = 60 // 背景の星の既定の数
+
+// 背景の星の既定の色バリエーション
+var defaultStarColors = []color.RGBA{
+	{180, 180, 255, 100}, // 白
+	{140, 180, 255, 100}, // 青白
+	{100, 140, 255, 100}, // 青
+	{200, 200, 255, 80},  // 明るい白
+	{80, 120, 255, 80},   // 暗い青
+}
+
+// Background はステージごとの背景の設定です。stages.jsonのbackgroundから読み込み、
+// 省略した項目は既定の星空になります
+type Background struct {
+	SkyColor    string   `json:"skyColor"`    // 背景の塗りつぶし色（#RRGGBB）
+	StarColors  []string `json:"starColors"`  // 星の色（#RRGGBB または #RRGGBBAA）
+	StarCount   int      `json:"starCount"`   // 星の数
+	StarSpeed   float64  `json:"starSpeed"`   // 星の流れる速さの倍率
+	Image       string   `json:"image"`       // 縦にスクロールする地形のタイル画像
+	ScrollSpeed float64  `json:"scrollSpeed"` // タイル画像のスクロール速度（ピクセル/フレーム）
+
+	sky        color.RGBA
+	starColors []color.RGBA
+	tile       *ebiten.Image
+}
+
+// prepare は色の文字列を解釈し、タイル画像を読み込み、省略された項目に既定値を入れます
+func (b *Background) prepare() error {
+	if b.SkyColor != "" {
+		c, err := parseHexColor(b.SkyColor)
+		if err != nil {
+			return err
+		}
+		b.sky = c
+	}
+
+	b.starColors = defaultStarColors
+	if len(b.StarColors) > 0 {
+		b.starColors = make([]color.RGBA, len(b.StarColors))
+		for i, s := range b.StarColors {
+			c, err := parseHexColor(s)
+			if err != nil {
+				return err
+			}
+			b.starColors[i] = c
+		}
+	}
+	if b.StarCount == 0 {
+		b.StarCount = defaultStarCount
+	}
+	if b.StarSpeed == 0 {
+		b.StarSpeed = 1
+	}
+
+	if b.Image != "" {
+		img, _, err := ebitenutil.NewImageFromFile(b.Image)
+		if err != nil {
+			return fmt.Errorf("背景画像(%s)の読み込みに失敗: %v", b.Image, err)
+		}
+		b.tile = img
+	}
+	return nil
+}
+
+// parseHexColor は#RRGGBBまたは#RRGGBBAA形式の色を解釈します
+func parseHexColor(s string) (color.RGBA, error) {
+	c := color.RGBA{A: 255}
+	var err error
+	switch len(s) {
+	case 7:
+		_, err = fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B)
+	case 9:
+		_, err = fmt.Sscanf(s, "#%02x%02x%02x%02x", &c.R, &c.G, &c.B, &c.A)
+	default:
+		err = fmt.Errorf("長さが不正です")
+	}
+	if err != nil {
+		return c, fmt.Errorf("色の指定が不正です: %q", s)
+	}
+	return c, nil
+}
+
+// resetStar は星を画面上端の外側に置き直します
+func (b *Background) resetStar(s *Star) {
+	s.x = rand.Float64() * playArea.width
+	s.speed = (2 + rand.Float64()*3) * b.StarSpeed
+	s.length = 8 + rand.Float64()*8
+	s.y = -s.length
+}
+
+// background は現在のステージの背景を返します
+func (g *Game) background() *Background {
+	if g.backgroundOverride != nil {
+		return g.backgroundOverride
+	}
+	return &g.stage().Background
+}
+
+// applyBackground は現在のステージの背景に合わせて星を作り直します
+func (g *Game) applyBackground() {
+	b := g.background()
+	g.stars = make([]Star, b.StarCount)
+	for i := range g.stars {
+		s := &g.stars[i]
+		b.resetStar(s)
+		s.y = rand.Float64() * playArea.height
+		s.color = b.starColors[rand.Intn(len(b.starColors))]
+	}
+	g.scrollY = 0
+}
+
+// updateBackground は星とタイル画像をスクロールさせます
+func (g *Game) updateBackground() {
+	b := g.background()
+	mood := g.backgroundMood()
+	for i := range g.stars {
+		g.stars[i].y += g.stars[i].speed * mood.starSpeed
+		if g.stars[i].y > playArea.height {
+			b.resetStar(&g.stars[i])
+		}
+	}
+	if b.tile != nil {
+		g.scrollY += b.ScrollSpeed
+		if h := float64(b.tile.Bounds().Dy()); g.scrollY >= h {
+			g.scrollY -= h
+		}
+	}
+}
+
+// drawBackground は背景色・タイル画像・星を描画します
+func (g *Game) drawBackground(field *ebiten.Image) {
+	b := g.background()
+	if b.sky.A > 0 {
+		field.Fill(b.sky)
+	}
+	if b.tile != nil {
+		// タイル画像をプレイエリア全体に敷き詰め、下方向へずらしていく
+		w, h := b.tile.Bounds().Dx(), b.tile.Bounds().Dy()
+		for y := g.scrollY - float64(h); y < playArea.height; y += float64(h) {
+			for x := 0.0; x < playArea.width; x += float64(w) {
+				op := &ebiten.DrawImageOptions{}
+				op.GeoM.Translate(x, y)
+				field.DrawImage(b.tile, op)
+			}
+		}
+	}
+	// ボスの接近などの演出で、背景を暗くし星の色を変える
+	mood := g.backgroundMood()
+	mood.drawShade(field)
+	for _, s := range g.stars {
+		ebitenutil.DrawLine(field, s.x, s.y, s.x, s.y+s.length*mood.starSpeed, mood.tintStar(s.color))
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	beamWarnTime = 60  // 予告線を表示するフレーム数
+	beamFireTime = 45  // ビームを照射し続けるフレーム数
+	beamWidth    = 16  // ビームの太さ
+	beamCooldown = 120 // ビームを撃った敵が次に撃てるまでの追加フレーム数
+)
+
+// Beam は発射位置から真下へ伸びるレーザー攻撃を表す構造体
+type Beam struct {
+	id        int
+	x, y      float64 // 発射位置
+	warnTimer int     // 予告線の残りフレーム数（0になると照射開始）
+	fireTimer int     // 照射の残りフレーム数
+}
+
+// fireBeam は指定位置からビーム攻撃を予告します
+func (g *Game) fireBeam(x, y float64) {
+	g.beams = append(g.beams, Beam{
+		id:        g.newEntityID(),
+		x:         x,
+		y:         y,
+		warnTimer: beamWarnTime,
+		fireTimer: beamFireTime,
+	})
+}
+
+// updateBeams はビームの予告・照射を進め、照射中のビームと自機の当たり判定を行います
+func (g *Game) updateBeams() {
+	hx, hy, hw, hh := g.playerHitbox()
+	newBeams := g.beams[:0]
+	for _, b := range g.beams {
+		if b.warnTimer > 0 {
+			b.warnTimer--
+			if b.warnTimer == 0 {
+				g.sound.PlayAt("beam", b.x, playArea.width)
+			}
+			newBeams = append(newBeams, b)
+			continue
+		}
+
+		// 照射中は毎フレーム当たり判定を行う
+		if g.gameState == GameStatePlaying &&
+			hx < b.x+beamWidth/2 && hx+hw > b.x-beamWidth/2 && hy+hh > b.y {
+			g.killPlayer()
+		}
+
+		b.fireTimer--
+		if b.fireTimer > 0 {
+			newBeams = append(newBeams, b)
+		}
+	}
+	g.beams = newBeams
+}
+
+// drawBeams はビームの予告線と照射中のビームを描画します
+func (g *Game) drawBeams(screen *ebiten.Image) {
+	for _, b := range g.beams {
+		if b.warnTimer > 0 {
+			// 予告線は細く点滅させる
+			if b.warnTimer%8 < 4 {
+				ebitenutil.DrawRect(screen, b.x-1, b.y, 2, playArea.height-b.y, color.RGBA{255, 80, 80, 160})
+			}
+			continue
+		}
+		// 照射の終わり際は細くなっていく
+		w := float64(beamWidth)
+		if b.fireTimer < 10 {
+			w = w * float64(b.fireTimer) / 10
+		}
+		ebitenutil.DrawRect(screen, b.x-w/2, b.y, w, playArea.height-b.y, color.RGBA{255, 60, 200, 200})
+		ebitenutil.DrawRect(screen, b.x-w/4, b.y, w/2, playArea.height-b.y, color.RGBA{255, 255, 255, 230})
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"log/slog"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	benchTierFrames   = 300 // 1段階あたりに測るフレーム数
+	benchWarmupFrames = 30  // 段階が変わった直後に数を増やしている間は測らないフレーム数
+)
+
+// benchTiers は段階ごとに画面に保つ敵弾とパーティクルの数です。敵はその1/10だけ出します
+var benchTiers = []int{250, 500, 1000, 2000, 4000}
+
+// Bench はベンチマークの状態です。自機を操作せずに敵・敵弾・パーティクルを段階的に増やし、
+// 段階ごとに更新と描画にかかった時間を測ります
+type Bench struct {
+	tier     int       // 測っている段階
+	frame    int       // 段階が始まってからのフレーム数
+	lastDraw time.Time // 前回の描画の時刻（フレームの間隔を測る）
+	results  []BenchResult
+	current  BenchResult
+}
+
+// BenchResult は1段階分の測定結果です
+type BenchResult struct {
+	Count      int           // 敵弾とパーティクルの数
+	Frames     int           // 測ったフレーム数
+	Update     time.Duration // 更新にかかった時間の合計
+	Draw       time.Duration // 描画にかかった時間の合計
+	Frame      time.Duration // フレームの間隔の合計
+	WorstFrame time.Duration // 最も長かったフレームの間隔
+}
+
+// startBench はベンチマークを始めます。自機は描かずに無敵にします
+func (g *Game) startBench() {
+	g.bench = &Bench{current: BenchResult{Count: benchTiers[0]}}
+	// 設定の上限にかかわらず、最後の段階の数まで入れられるようにする
+	g.particles = newParticlePool(benchTiers[len(benchTiers)-1])
+	g.demo = true
+	g.input = newSimInput(rand.New(rand.NewSource(1)), false)
+	g.cheatInvincible = true
+	g.gameState = GameStatePlaying
+	g.setWaves(nil)
+	g.nextStageEvent = len(g.stage().Events)
+}
+
+// updateBench は数を補充してからゲームを1ステップ進め、かかった時間を測ります。
+// すべての段階を測り終えたら結果を出力して終了します
+func (g *Game) updateBench() error {
+	b := g.bench
+	g.fillBench(benchTiers[b.tier])
+
+	start := time.Now()
+	if err := g.tick(); err != nil {
+		return err
+	}
+	if b.frame >= benchWarmupFrames {
+		b.current.Update += time.Since(start)
+	}
+
+	b.frame++
+	if b.frame < benchWarmupFrames+benchTierFrames {
+		return nil
+	}
+	slog.Info("ベンチマークの段階を測り終えました", "count", b.current.Count,
+		"update", b.current.avg(b.current.Update), "draw", b.current.avg(b.current.Draw),
+		"frame", b.current.avg(b.current.Frame), "worstFrame", b.current.WorstFrame)
+	b.results = append(b.results, b.current)
+	b.tier++
+	b.frame = 0
+	if b.tier >= len(benchTiers) {
+		fmt.Println(b.report())
+		stopCPUProfile()
+		return ebiten.Termination
+	}
+	b.current = BenchResult{Count: benchTiers[b.tier]}
+	return nil
+}
+
+// fillBench は敵・敵弾・パーティクルが目標の数になるまで補充します
+func (g *Game) fillBench(count int) {
+	types := []int{EnemyTypeStraight, EnemyTypeSine, EnemyTypeSpecial}
+	for len(g.enemies) < count/10 {
+		e := g.newEnemy(types[rand.Intn(len(types))], rand.Float64()*(playArea.width-20), rand.Float64()*playArea.height/2, 0.5+rand.Float64())
+		e.shooter = newShooter(rand.Intn(4))
+		g.addEnemy(e)
+	}
+	for len(g.enemyBullets) < count {
+		angle := rand.Float64() * math.Pi * 2
+		speed := 1 + rand.Float64()*3
+		g.enemyBullets = append(g.enemyBullets, EnemyBullet{
+			x:  rand.Float64() * playArea.width,
+			y:  rand.Float64() * playArea.height,
+			vx: math.Cos(angle) * speed,
+			vy: math.Sin(angle) * speed,
+		})
+	}
+	for g.particles.len() < count {
+		clr := color.RGBA{uint8(rand.Intn(256)), uint8(rand.Intn(256)), 255, 255}
+		g.createExplosion(rand.Float64()*playArea.width, rand.Float64()*playArea.height, clr, ExplosionSmall)
+	}
+}
+
+// measureDraw は描画にかかった時間と前回の描画からの間隔を記録します
+func (b *Bench) measureDraw(start time.Time) {
+	if b.frame >= benchWarmupFrames && !b.lastDraw.IsZero() {
+		b.current.Draw += time.Since(start)
+		interval := start.Sub(b.lastDraw)
+		b.current.Frame += interval
+		b.current.Frames++
+		b.current.WorstFrame = max(b.current.WorstFrame, interval)
+	}
+	b.lastDraw = start
+}
+
+// avg は1フレームあたりの平均の時間を返します
+func (r BenchResult) avg(total time.Duration) time.Duration {
+	if r.Frames == 0 {
+		return 0
+	}
+	return total / time.Duration(r.Frames)
+}
+
+// report は段階ごとの結果を表にします
+func (b *Bench) report() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "%8s %10s %10s %10s %10s %8s\n", "count", "update", "draw", "frame", "worst", "fps")
+	for _, r := range b.results {
+		fps := 0.0
+		if avg := r.avg(r.Frame); avg > 0 {
+			fps = float64(time.Second) / float64(avg)
+		}
+		fmt.Fprintf(&s, "%8d %10v %10v %10v %10v %8.1f\n", r.Count,
+			r.avg(r.Update).Round(time.Microsecond), r.avg(r.Draw).Round(time.Microsecond),
+			r.avg(r.Frame).Round(time.Microsecond), r.WorstFrame.Round(time.Microsecond), fps)
+	}
+	return strings.TrimRight(s.String(), "\n")
+}
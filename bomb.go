@@ -0,0 +1,114 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	bombDamage      = 10 // ボムが画面内の敵に与えるダメージ
+	bombInvincible  = 60 // ボム使用後の無敵フレーム数
+	bombFlashFrames = 20 // 画面が白く光るフレーム数
+
+	bulletTimeFrames = 180  // バレットタイムの持続フレーム数
+	bulletTimeScale  = 0.25 // バレットタイム中の敵弾の速さの倍率
+)
+
+// updateBombs はボムの効果の残り時間を進め、Xキーでボム、Cキーでバレットタイムを使います
+func (g *Game) updateBombs() {
+	if g.bombFlashTimer > 0 {
+		g.bombFlashTimer--
+	}
+	if g.bulletTimeTimer > 0 {
+		g.bulletTimeTimer--
+	}
+	if g.input.JustPressed(ebiten.KeyX) {
+		g.useBomb()
+	}
+	if g.input.JustPressed(ebiten.KeyC) {
+		g.useBulletTime()
+	}
+}
+
+// useBomb はボムを1つ使い、敵弾を消して画面内の敵にダメージを与えます
+func (g *Game) useBomb() {
+	if g.bombs <= 0 {
+		return
+	}
+	g.bombs--
+	g.emit(Event{Kind: EventBombUsed})
+	g.bombFlashTimer = bombFlashFrames
+	if g.invincibleTimer < bombInvincible {
+		g.invincibleTimer = bombInvincible
+	}
+
+	// 敵弾は小さな光になって消える
+	for _, eb := range g.enemyBullets {
+		g.emitParticles("bulletFade", eb.x, eb.y)
+	}
+	g.enemyBullets = g.enemyBullets[:0]
+
+	// 画面内の敵にダメージ（砲台に守られた本体には通らない）
+	for i := range g.enemies {
+		e := &g.enemies[i]
+		if e.dead {
+			continue
+		}
+		if e.y >= 0 && !e.isShielded() {
+			e.hp -= bombDamage
+			g.addDamageNumber(e, bombDamage)
+			e.flashTimer = hitFlashFrames
+			e.hpBarTimer = hpBarFrames
+		}
+		if e.hp <= 0 {
+			g.markDead(e, false)
+		}
+	}
+}
+
+// useBulletTime はボムを1つ使い、敵弾を消す代わりにしばらくの間すべての敵弾を遅くします
+func (g *Game) useBulletTime() {
+	if g.bombs <= 0 || g.bulletTimeTimer > 0 {
+		return
+	}
+	g.bombs--
+	g.emit(Event{Kind: EventBombUsed})
+	g.bulletTimeTimer = bulletTimeFrames
+}
+
+// enemyBulletTimeScale は敵弾の移動にかける速さの倍率を返します
+func (g *Game) enemyBulletTimeScale() float64 {
+	if g.bulletTimeTimer > 0 {
+		return bulletTimeScale
+	}
+	return 1
+}
+
+// drawBombEffects はバレットタイムの青みとボムの光をプレイエリアに描画します
+func (g *Game) drawBombEffects(field *ebiten.Image) {
+	g.drawBulletTimeTint(field)
+	g.drawBombFlash(field)
+}
+
+// drawBulletTimeTint はバレットタイム中の画面を青く染めます。終わり際は色が薄れていきます
+func (g *Game) drawBulletTimeTint(field *ebiten.Image) {
+	if g.bulletTimeTimer <= 0 {
+		return
+	}
+	alpha := 70
+	if g.bulletTimeTimer < 30 {
+		alpha = alpha * g.bulletTimeTimer / 30
+	}
+	ebitenutil.DrawRect(field, 0, 0, playArea.width, playArea.height, color.RGBA{40, 80, 200, uint8(alpha)})
+}
+
+// drawBombFlash はボム使用直後の画面の白い光を描画します
+func (g *Game) drawBombFlash(field *ebiten.Image) {
+	if g.bombFlashTimer <= 0 {
+		return
+	}
+	alpha := uint8(180 * g.bombFlashTimer / bombFlashFrames)
+	ebitenutil.DrawRect(field, 0, 0, playArea.width, playArea.height, color.RGBA{255, 255, 255, alpha})
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	bossApproachLead      = 5 * baseTPS // ボス出現の何フレーム前から接近の演出を始めるか
+	bossApproachFade      = 90          // 演出が最大になるまで（消えるまで）のフレーム数
+	bossApproachStarBoost = 1.5         // 演出が最大のときに星の速さに足す倍率
+	bossApproachShade     = 110         // 演出が最大のときに背景を暗くする黒の濃さ
+	rumbleInterval        = 55          // 低いうなりを鳴らし直す間隔（フレーム数）
+)
+
+// bossApproachStarColor は演出が最大のときの星の色です
+var bossApproachStarColor = color.RGBA{255, 60, 40, 160}
+
+// BossApproach はボス接近の演出の状態です
+type BossApproach struct {
+	intensity   float64 // 演出の強さ（0〜1）
+	active      bool    // 演出を強めている途中か（falseなら弱めていく）
+	wave        int     // 接近を知らせたボスのウェーブの番号+1（0ならまだ知らせていない）
+	rumbleTimer int     // 次に低いうなりを鳴らすまでのフレーム数
+}
+
+// BackgroundMood は背景の描画を変える演出の値です。
+// ステージ中の出来事に応じて背景の見た目を変えるときは、backgroundMoodで返す値を変えます
+type BackgroundMood struct {
+	starSpeed float64    // 星の速さの倍率
+	starTint  color.RGBA // 星に混ぜる色
+	tint      float64    // 星に色を混ぜる割合（0〜1）
+	shade     uint8      // 背景に重ねる黒の濃さ
+}
+
+func init() {
+	subscribe(EventBossApproaching, func(g *Game, _ Event) {
+		g.bossApproach.active = true
+		g.bossApproach.rumbleTimer = 0
+	})
+	// ボスを倒したら、ほかにボスが残っていなければ元の背景に戻す
+	subscribe(EventEnemyKilled, func(g *Game, e Event) {
+		if e.EnemyType != EnemyTypeBoss {
+			return
+		}
+		for _, other := range g.enemies {
+			if other.boss != nil && other.hp > 0 {
+				return
+			}
+		}
+		g.bossApproach.active = false
+	})
+}
+
+// checkBossApproach は次のボスの出現までが5秒を切ったら接近を知らせます。
+// 警告の間はウェーブのタイマーが止まるので、その長さも出現までの時間に含めます
+func (g *Game) checkBossApproach() {
+	for i, w := range g.waves {
+		if i < g.currentSpawn || w.EnemyType != EnemyTypeBoss {
+			continue
+		}
+		if g.bossApproach.wave == i+1 {
+			return
+		}
+		remaining := max(g.spawnFrames[i]-g.waveTimer, 0) + tuning.Boss.WarningFrames
+		if g.bossWarned {
+			remaining = g.bossWarningTimer
+		}
+		if remaining <= bossApproachLead {
+			g.bossApproach.wave = i + 1
+			g.emit(Event{Kind: EventBossApproaching})
+		}
+		return
+	}
+}
+
+// updateBossApproach は演出の強さを少しずつ変え、接近中は低いうなりを鳴らし続けます
+func (g *Game) updateBossApproach() {
+	a := &g.bossApproach
+	if a.active {
+		a.intensity = min(a.intensity+1.0/bossApproachFade, 1)
+		if g.gameState == GameStatePlaying {
+			a.rumbleTimer--
+			if a.rumbleTimer <= 0 {
+				g.sound.Play("rumble")
+				a.rumbleTimer = rumbleInterval
+			}
+		}
+	} else {
+		a.intensity = max(a.intensity-1.0/bossApproachFade, 0)
+	}
+}
+
+// backgroundMood は今の背景の演出の値を返します
+func (g *Game) backgroundMood() BackgroundMood {
+	i := g.bossApproach.intensity
+	return BackgroundMood{
+		starSpeed: 1 + bossApproachStarBoost*i,
+		starTint:  bossApproachStarColor,
+		tint:      i,
+		shade:     uint8(bossApproachShade * i),
+	}
+}
+
+// tintStar は星の色に演出の色を混ぜます
+func (m BackgroundMood) tintStar(c color.RGBA) color.RGBA {
+	if m.tint == 0 {
+		return c
+	}
+	lerp := func(x, y uint8) uint8 { return uint8(float64(x) + (float64(y)-float64(x))*m.tint) }
+	return color.RGBA{lerp(c.R, m.starTint.R), lerp(c.G, m.starTint.G), lerp(c.B, m.starTint.B), lerp(c.A, m.starTint.A)}
+}
+
+// drawShade は背景に黒を重ねて暗くします
+func (m BackgroundMood) drawShade(field *ebiten.Image) {
+	if m.shade == 0 {
+		return
+	}
+	ebitenutil.DrawRect(field, 0, 0, playArea.width, playArea.height, color.RGBA{0, 0, 0, m.shade})
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// ボスの攻撃の命令
+const (
+	BossOpWait  = "wait"  // framesフレーム待つ
+	BossOpRing  = "ring"  // count発の弾を全方向に等間隔で撃つ（fromで向きをずらせる）
+	BossOpAim   = "aim"   // 自機を中心にcount発の弾をspread度ずつ広げて撃つ
+	BossOpSweep = "sweep" // fromからtoの角度へcount発の弾をintervalフレームおきに順に撃つ（0なら一度に撃つ）
+)
+
+// BossStep はボスの攻撃の1命令です。stages.jsonのボスのウェーブに"attack"として並べると、
+// 攻撃状態になるたびに先頭から順に実行します。角度は度で、0が真下、正の値が右回りです
+type BossStep struct {
+	Op       string  `json:"op"`
+	Frames   int     `json:"frames"`   // wait: 待つフレーム数
+	Count    int     `json:"count"`    // ring・aim・sweep: 弾の数
+	Speed    float64 `json:"speed"`    // ring・aim・sweep: 弾の速さ（省略時はtuning.jsonのboss.bulletSpeed）
+	Spread   float64 `json:"spread"`   // aim: 隣り合う弾の角度の差
+	From     float64 `json:"from"`     // ring: 最初の弾の角度, sweep: 撃ち始めの角度
+	To       float64 `json:"to"`       // sweep: 撃ち終わりの角度
+	Interval int     `json:"interval"` // sweep: 弾を撃つ間隔（フレーム数）
+}
+
+// validateBossAttack はウェーブのボスの攻撃の命令を確かめます
+func validateBossAttack(w Wave) error {
+	for i, s := range w.Attack {
+		var err error
+		switch s.Op {
+		case BossOpWait:
+			if s.Frames < 1 {
+				err = fmt.Errorf("framesは1以上にしてください")
+			}
+		case BossOpRing, BossOpAim, BossOpSweep:
+			if s.Count < 1 || s.Speed < 0 || s.Interval < 0 {
+				err = fmt.Errorf("countは1以上、speedとintervalは0以上にしてください")
+			}
+		default:
+			err = fmt.Errorf("opの値が不正です: %q", s.Op)
+		}
+		if err != nil {
+			return fmt.Errorf("attackの%d番目の命令: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+// defaultBossAttack はattackを省略したボスの攻撃です。
+// tuning.jsonのshotIntervalおきに真下へ5way弾を撃ち、attackFramesが過ぎたら攻撃を終えます
+func defaultBossAttack() []BossStep {
+	bt := tuning.Boss
+	spread := bt.SpreadAngle * 180 / math.Pi * 2
+	fan := BossStep{Op: BossOpSweep, Count: 5, From: -spread, To: spread}
+	volleys := (bt.AttackFrames - 1) / bt.ShotInterval
+	var steps []BossStep
+	for i := 0; i < volleys; i++ {
+		steps = append(steps, BossStep{Op: BossOpWait, Frames: bt.ShotInterval}, fan)
+	}
+	return append(steps, BossStep{Op: BossOpWait, Frames: bt.AttackFrames + 1 - volleys*bt.ShotInterval})
+}
+
+// runBossAttack はボスの攻撃の命令を1フレーム分進めます。最後の命令まで終えたらtrueを返します。
+// 待つ命令と、間隔を空けて撃つsweepの途中で次のフレームへ持ち越します
+func (g *Game) runBossAttack(e *Enemy) bool {
+	b := e.boss
+	for b.step < len(b.script) {
+		s := b.script[b.step]
+		switch s.Op {
+		case BossOpWait:
+			b.stepTimer++
+			if b.stepTimer < s.Frames {
+				return false
+			}
+		case BossOpRing:
+			for i := 0; i < s.Count; i++ {
+				g.fireBossBullet(e, s, s.From+360*float64(i)/float64(s.Count))
+			}
+		case BossOpAim:
+			aim := g.bossAimAngle(e)
+			g.bossMuzzleLine(e, aim)
+			for i := 0; i < s.Count; i++ {
+				g.fireBossBullet(e, s, aim+(float64(i)-float64(s.Count-1)/2)*s.Spread)
+			}
+		case BossOpSweep:
+			if b.stepTimer > 0 {
+				b.stepTimer--
+				return false
+			}
+			if s.Interval == 0 {
+				g.bossMuzzleLine(e, (s.From+s.To)/2)
+			}
+			for {
+				angle := s.From
+				if s.Count > 1 {
+					angle += (s.To - s.From) * float64(b.fired) / float64(s.Count-1)
+				}
+				if s.Interval > 0 {
+					g.bossMuzzleLine(e, angle)
+				}
+				g.fireBossBullet(e, s, angle)
+				b.fired++
+				if b.fired >= s.Count || s.Interval > 0 {
+					break
+				}
+			}
+			if b.fired < s.Count {
+				b.stepTimer = s.Interval - 1
+				return false
+			}
+		}
+		b.step++
+		b.stepTimer = 0
+		b.fired = 0
+	}
+	return true
+}
+
+// bossAimAngle はボスの砲口から自機の中心への角度（度。0が真下）を返します
+func (g *Game) bossAimAngle(e *Enemy) float64 {
+	dx := g.playerX + 10 - (e.x + 20)
+	dy := g.playerY + 12 - (e.y + 30)
+	return math.Atan2(dx, dy) * 180 / math.Pi
+}
+
+// fireBossBullet はボスの砲口から指定した角度（度。0が真下）に弾を1発撃ちます。
+// 復活直後で敵が撃たない間は撃ちません。攻撃の最初の弾で効果音を鳴らします
+func (g *Game) fireBossBullet(e *Enemy, s BossStep, deg float64) {
+	if g.ceaseFireTimer > 0 {
+		return
+	}
+	b := e.boss
+	if !b.shotSounded {
+		g.sound.Play("bossShot")
+		b.shotSounded = true
+	}
+	speed := s.Speed
+	if speed == 0 {
+		speed = tuning.Boss.BulletSpeed
+	}
+	speed *= g.rankMultiplier() * g.loopSpeedScale()
+	angle := deg * math.Pi / 180
+	vx, vy := math.Sin(angle)*speed, math.Cos(angle)*speed
+	g.enemyBullets = append(g.enemyBullets, EnemyBullet{
+		x: e.x + 20, y: e.y + 30, vx: vx, vy: vy, ownerID: e.id,
+	})
+}
+
+// bossMuzzleLine はボスの砲口から指定した角度（度。0が真下）に発射ラインのエフェクトを出します
+func (g *Game) bossMuzzleLine(e *Enemy, deg float64) {
+	if g.ceaseFireTimer > 0 {
+		return
+	}
+	angle := deg * math.Pi / 180
+	g.particles.add(Particle{
+		x: e.x + 20, y: e.y + 30, vx: math.Sin(angle) * 4, vy: math.Cos(angle) * 4,
+		size: 100, alpha: 1.0, lifetime: 8, ptype: 1,
+	})
+}
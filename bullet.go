@@ -0,0 +1,169 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"sort"
+)
+
+// updateBullets は自機弾の移動と当たり判定を行います。
+// 貫通する弾は当たった敵を覚えておき、同じ敵には二度当たりません
+func (g *Game) updateBullets() {
+	newBullets := g.bullets[:0]
+	for _, b := range g.bullets {
+		hit, consumed := g.hitEnemies(&b)
+		if consumed {
+			continue
+		}
+		if !hit && g.hitMine(b) {
+			// 機雷は貫通できない
+			continue
+		}
+
+		b.x += b.vx
+		b.y += b.vy
+		b.bounce()
+		if b.y > -8 && b.y < playArea.height+8 && b.x > -8 && b.x < playArea.width+8 {
+			newBullets = append(newBullets, b)
+		}
+	}
+	g.bullets = newBullets
+}
+
+// hitEnemies は自機弾が重なっている敵すべてに、弾が先に届いた順（弾の進む向きで手前の敵から。
+// 同じなら先に出現した敵から）にダメージを与えます。貫通する弾は貫通できる数だけ続けて当たり、
+// 爆風のある弾は最初に当たった場所の周りの敵にもダメージを与えて消えます。
+// 敵に当たったか（hit）と、弾が消えたか（consumed）を返します
+func (g *Game) hitEnemies(b *Bullet) (hit, consumed bool) {
+	for _, i := range g.bulletContacts(b) {
+		e := &g.enemies[i]
+		hit = true
+		if b.pierce > 0 {
+			b.hitIDs = append(b.hitIDs, e.id)
+		}
+		switch {
+		case e.isShielded():
+			// 砲台が残っている間は本体にダメージが通らない
+			g.emitParticles("armorPing", b.x, b.y)
+		case e.frontShield != nil && e.frontShield.blocks(b):
+			// 盾を構えた正面から当たった弾は火花を散らして弾かれる
+			g.deflectBullet(b)
+		default:
+			g.damageEnemy(e, e.bulletDamage(b.damage), b.x+2, b.y)
+			if b.blast > 0 {
+				g.blastEnemies(b, e.id)
+				return true, true
+			}
+		}
+		if b.pierce == 0 {
+			return true, true
+		}
+		b.pierce--
+	}
+	return hit, false
+}
+
+// bulletContacts は自機弾と重なっている、まだ当たっていない敵の番号（g.enemiesの添字）を、
+// 弾が先に届いた順に並べて返します
+func (g *Game) bulletContacts(b *Bullet) []int {
+	var contacts []int
+	for i := range g.enemies {
+		e := &g.enemies[i]
+		if e.dead || b.hasHit(e.id) {
+			continue
+		}
+		// 敵のサイズを考慮した当たり判定
+		enemyWidth, enemyHeight := enemySize(e.enemyType)
+		if !overlaps(b.x, b.y, 4, 8, e.x, e.y, enemyWidth, enemyHeight) {
+			continue
+		}
+		contacts = append(contacts, i)
+	}
+	// 敵の中心を弾の進む向きに射影した値が小さいほど手前
+	reach := func(i int) float64 {
+		w, h := enemySize(g.enemies[i].enemyType)
+		return (g.enemies[i].x+w/2)*b.vx + (g.enemies[i].y+h/2)*b.vy
+	}
+	sort.SliceStable(contacts, func(a, c int) bool {
+		ra, rc := reach(contacts[a]), reach(contacts[c])
+		if ra != rc {
+			return ra < rc
+		}
+		return g.enemies[contacts[a]].id < g.enemies[contacts[c]].id
+	})
+	return contacts
+}
+
+// blastEnemies は爆風のある弾が当たった場所の周りの敵に、近い順（同じなら先に出現した敵から）に
+// ダメージを与えます。直撃した敵と、砲台に守られた本体には与えません
+func (g *Game) blastEnemies(b *Bullet, directID int) {
+	cx, cy := b.x+2, b.y+4
+	var targets []int
+	distance := func(i int) float64 {
+		w, h := enemySize(g.enemies[i].enemyType)
+		return math.Hypot(g.enemies[i].x+w/2-cx, g.enemies[i].y+h/2-cy)
+	}
+	for i := range g.enemies {
+		e := &g.enemies[i]
+		if e.dead || e.id == directID || e.isShielded() || distance(i) > b.blast {
+			continue
+		}
+		targets = append(targets, i)
+	}
+	sort.SliceStable(targets, func(a, c int) bool {
+		da, dc := distance(targets[a]), distance(targets[c])
+		if da != dc {
+			return da < dc
+		}
+		return g.enemies[targets[a]].id < g.enemies[targets[c]].id
+	})
+	for _, i := range targets {
+		e := &g.enemies[i]
+		g.damageEnemy(e, e.bulletDamage(b.damage), e.x, e.y)
+	}
+	g.particles.add(Particle{
+		x: cx, y: cy, size: 4, growth: b.blast / 8, alpha: 1.0, lifetime: 8,
+		shape: ParticleRing, color: color.RGBA{255, 200, 80, 255},
+	})
+}
+
+// damageEnemy は自機弾のダメージを敵に与えます。倒しきれなければ白く光らせ、
+// 倒したら印を付けて当たり判定のあとの掃除に回します（撃ち返し弾を出す）
+func (g *Game) damageEnemy(e *Enemy, damage int, sparkX, sparkY float64) {
+	e.hp -= damage
+	e.hpBarTimer = hpBarFrames
+	g.addDamageNumber(e, damage)
+	if e.hp <= 0 {
+		g.markDead(e, true)
+		return
+	}
+	// 倒しきれなかった敵は白く光らせて手応えを出す
+	e.flashTimer = hitFlashFrames
+	g.emitParticles("hitSpark", sparkX, sparkY)
+	g.sound.PlayAt("hit", sparkX, playArea.width)
+}
+
+// hasHit は貫通中の弾がすでにその敵に当たったかを返します
+func (b *Bullet) hasHit(id int) bool {
+	for _, hitID := range b.hitIDs {
+		if hitID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// bounce は跳ね返りの回数が残っていれば、画面の左右と上の端で弾の向きを反転させます
+func (b *Bullet) bounce() {
+	if b.bounces <= 0 {
+		return
+	}
+	switch {
+	case b.x < 0 && b.vx < 0, b.x > playArea.width-4 && b.vx > 0:
+		b.vx = -b.vx
+		b.bounces--
+	case b.y < 0 && b.vy < 0:
+		b.vy = -b.vy
+		b.bounces--
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testEnemy は当たり判定のテストに使う、耐久度1の敵の置き場所です（20x20の敵）
+type testEnemy struct {
+	id   int
+	x, y float64
+}
+
+// placeEnemies は番号と位置を決めた耐久度1の敵をゲームに並べます
+func placeEnemies(g *Game, enemies []testEnemy) {
+	for _, te := range enemies {
+		e := g.newEnemy(EnemyTypeStraight, te.x, te.y, 0)
+		e.id = te.id
+		e.hp = 1
+		g.enemies = append(g.enemies, e)
+	}
+}
+
+// killedIDs は倒された順に敵の番号を返します
+func killedIDs(g *Game) []int {
+	ids := []int{}
+	for _, k := range g.deathQueue {
+		ids = append(ids, k.id)
+	}
+	return ids
+}
+
+func TestBulletContactsOrder(t *testing.T) {
+	// 弾は(100, 100)から幅4・高さ8
+	tests := []struct {
+		name    string
+		vx, vy  float64
+		enemies []testEnemy
+		hitIDs  []int
+		want    []int
+	}{
+		{
+			name: "upward shot reaches lower enemy first",
+			vy:   -8,
+			// 中心のyは95と105
+			enemies: []testEnemy{{1, 92, 85}, {2, 92, 95}},
+			want:    []int{2, 1},
+		},
+		{
+			name: "rightward shot reaches left enemy first",
+			vx:   8,
+			// 中心のxは100と95
+			enemies: []testEnemy{{1, 90, 95}, {2, 85, 95}},
+			want:    []int{2, 1},
+		},
+		{
+			name: "same projection falls back to id",
+			vy:   -8,
+			// どちらも中心のyは105
+			enemies: []testEnemy{{3, 90, 95}, {1, 94, 95}},
+			want:    []int{1, 3},
+		},
+		{
+			name:    "skips enemies already pierced",
+			vy:      -8,
+			enemies: []testEnemy{{1, 92, 85}, {2, 92, 95}},
+			hitIDs:  []int{2},
+			want:    []int{1},
+		},
+		{
+			name:    "ignores enemies out of reach",
+			vy:      -8,
+			enemies: []testEnemy{{1, 92, 85}, {2, 200, 95}},
+			want:    []int{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(t)
+			placeEnemies(g, tt.enemies)
+			b := Bullet{x: 100, y: 100, vx: tt.vx, vy: tt.vy, damage: 1, hitIDs: tt.hitIDs}
+			got := []int{}
+			for _, i := range g.bulletContacts(&b) {
+				got = append(got, g.enemies[i].id)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("contacts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPiercingShotStopsAfterPierceCount(t *testing.T) {
+	// 上へ進む弾に、下（手前）から番号1〜4の順で重なる敵
+	enemies := []testEnemy{{4, 92, 90}, {3, 92, 92}, {2, 92, 94}, {1, 92, 96}}
+	tests := []struct {
+		name         string
+		pierce       int
+		wantKilled   []int
+		wantConsumed bool
+	}{
+		{"no pierce", 0, []int{1}, true},
+		{"pierce 1", 1, []int{1, 2}, true},
+		{"pierce 3", 3, []int{1, 2, 3, 4}, true},
+		{"pierce left over", 5, []int{1, 2, 3, 4}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(t)
+			placeEnemies(g, enemies)
+			b := Bullet{x: 100, y: 100, vy: -8, damage: 1, pierce: tt.pierce}
+			hit, consumed := g.hitEnemies(&b)
+			if !hit {
+				t.Errorf("hit = false, want true")
+			}
+			if consumed != tt.wantConsumed {
+				t.Errorf("consumed = %v, want %v", consumed, tt.wantConsumed)
+			}
+			if got := killedIDs(g); !reflect.DeepEqual(got, tt.wantKilled) {
+				t.Errorf("killed = %v, want %v", got, tt.wantKilled)
+			}
+		})
+	}
+}
+
+func TestBlastDamagesNearestFirst(t *testing.T) {
+	// 爆風の中心は(102, 104)。直撃した番号1の敵は爆風を受けない
+	enemies := []testEnemy{
+		{5, 192, 94}, // 距離100
+		{2, 92, 134}, // 距離40
+		{4, 72, 94},  // 距離20
+		{3, 112, 94}, // 距離20
+		{1, 92, 94},  // 直撃
+	}
+	tests := []struct {
+		name  string
+		blast float64
+		want  []int
+	}{
+		{"wide blast", 60, []int{3, 4, 2}},
+		{"ties fall back to id", 30, []int{3, 4}},
+		{"too small to reach", 10, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(t)
+			placeEnemies(g, enemies)
+			b := Bullet{x: 100, y: 100, vy: -8, damage: 1, blast: tt.blast}
+			g.blastEnemies(&b, 1)
+			if got := killedIDs(g); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("blast order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	cancelRadius       = 160 // 倒した敵の中心からこの距離以内の弾を消す
+	scoreItemValue     = 10  // 得点アイテム1つのスコア
+	scoreItemSize      = 4   // 得点アイテムの大きさ
+	scoreItemWait      = 20  // 自機へ飛び始めるまでのフレーム数
+	scoreItemMaxSpeed  = 12  // 自機へ飛んでいく最高速度
+	scoreItemAccel     = 0.6 // 自機へ向かう加速度
+	scoreItemPickRange = 16  // 自機の中心からこの距離以内で回収する
+)
+
+// ScoreItem は消した敵弾から出る得点アイテムです。少し漂ったあと自機へ飛んでいきます
+type ScoreItem struct {
+	x, y   float64
+	vx, vy float64
+	wait   int // 自機へ飛び始めるまでの残りフレーム数
+}
+
+// cancelBullets は倒した敵（ボスなら取り付けられた砲台も含む）が撃った弾のうち、
+// 近くにあるものを得点アイテムに変えます
+func (g *Game) cancelBullets(e Enemy) {
+	owners := map[int]bool{e.id: true}
+	for _, child := range g.enemies {
+		if child.parentID == e.id {
+			owners[child.id] = true
+		}
+	}
+
+	w, h := enemySize(e.enemyType)
+	cx, cy := e.x+w/2, e.y+h/2
+	newEnemyBullets := g.enemyBullets[:0]
+	for _, eb := range g.enemyBullets {
+		if owners[eb.ownerID] && math.Hypot(eb.x-cx, eb.y-cy) < cancelRadius {
+			g.scoreItems = append(g.scoreItems, ScoreItem{
+				x:    eb.x,
+				y:    eb.y,
+				vx:   eb.vx * 0.3,
+				vy:   eb.vy * 0.3,
+				wait: scoreItemWait,
+			})
+			continue
+		}
+		newEnemyBullets = append(newEnemyBullets, eb)
+	}
+	g.enemyBullets = newEnemyBullets
+}
+
+// updateScoreItems は得点アイテムを自機へ向けて飛ばし、届いたものを回収します
+func (g *Game) updateScoreItems() {
+	px, py := g.playerX+10, g.playerY+12
+	newItems := g.scoreItems[:0]
+	for _, it := range g.scoreItems {
+		if it.wait > 0 {
+			// 消えた弾の勢いで少し流れてから止まる
+			it.wait--
+			it.vx *= 0.9
+			it.vy *= 0.9
+		} else {
+			dx, dy := px-it.x, py-it.y
+			dist := math.Hypot(dx, dy)
+			if dist < scoreItemPickRange {
+				g.score += scoreItemValue
+				continue
+			}
+			it.vx += dx / dist * scoreItemAccel
+			it.vy += dy / dist * scoreItemAccel
+			if speed := math.Hypot(it.vx, it.vy); speed > scoreItemMaxSpeed {
+				it.vx *= scoreItemMaxSpeed / speed
+				it.vy *= scoreItemMaxSpeed / speed
+			}
+		}
+		it.x += it.vx
+		it.y += it.vy
+		newItems = append(newItems, it)
+	}
+	g.scoreItems = newItems
+}
+
+// drawScoreItems は得点アイテムを小さな四角として描画します
+func (g *Game) drawScoreItems(field *ebiten.Image) {
+	c := color.RGBA{120, 255, 180, 255}
+	for _, it := range g.scoreItems {
+		ebitenutil.DrawRect(field, it.x-scoreItemSize/2, it.y-scoreItemSize/2, scoreItemSize, scoreItemSize, c)
+	}
+}
@@ -0,0 +1,133 @@
+package capture
+
+import (
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Recorder は直近の画面を縮小して一定数だけ保持し、求めに応じてGIFアニメとして書き出します
+type Recorder struct {
+	interval int     // 何フレームごとに取り込むか
+	scale    float64 // 取り込むときの縮小率
+	delay    int     // GIFの1コマの表示時間（1/100秒単位）
+
+	frames []*image.RGBA // リングバッファ
+	next   int           // 次に書き込む位置
+	count  int           // 保持しているコマ数
+	tick   int
+
+	small *ebiten.Image // 縮小用の画像
+	done  chan result   // 書き出しの結果
+	busy  bool          // 書き出し中か
+}
+
+type result struct {
+	path string
+	err  error
+}
+
+// NewRecorder は直近seconds秒をfpsコマ/秒で保持するレコーダーを作成します。
+// 画面はscale倍に縮小して保持します
+func NewRecorder(seconds, fps int, scale float64) *Recorder {
+	return &Recorder{
+		interval: 60 / fps,
+		scale:    scale,
+		delay:    100 / fps,
+		frames:   make([]*image.RGBA, seconds*fps),
+		done:     make(chan result, 1),
+	}
+}
+
+// Capture は画面を取り込みます。Drawの最後に毎フレーム呼び出します
+func (r *Recorder) Capture(screen *ebiten.Image) {
+	r.tick++
+	if r.tick%r.interval != 0 {
+		return
+	}
+
+	b := screen.Bounds()
+	w, h := int(float64(b.Dx())*r.scale), int(float64(b.Dy())*r.scale)
+	if r.small == nil {
+		r.small = ebiten.NewImage(w, h)
+	}
+	r.small.Clear()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(r.scale, r.scale)
+	op.Filter = ebiten.FilterLinear
+	r.small.DrawImage(screen, op)
+
+	// 書き出し中のコマを上書きしないよう、毎回新しいバッファに読み込む
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	r.small.ReadPixels(img.Pix)
+	r.frames[r.next] = img
+	r.next = (r.next + 1) % len(r.frames)
+	if r.count < len(r.frames) {
+		r.count++
+	}
+}
+
+// Export は保持しているコマをdir以下にGIFアニメとして書き出します。
+// 書き出しは別のゴルーチンで行い、結果はPollで受け取ります。
+// すでに書き出し中か、コマがなければfalseを返します
+func (r *Recorder) Export(dir string) bool {
+	if r.busy || r.count == 0 {
+		return false
+	}
+
+	// 古い順に並べ直す
+	frames := make([]*image.RGBA, 0, r.count)
+	start := (r.next - r.count + len(r.frames)) % len(r.frames)
+	for i := 0; i < r.count; i++ {
+		frames = append(frames, r.frames[(start+i)%len(r.frames)])
+	}
+
+	r.busy = true
+	path := filepath.Join(dir, time.Now().Format("clip-20060102-150405.gif"))
+	go func() {
+		r.done <- result{path: path, err: encode(path, frames, r.delay)}
+	}()
+	return true
+}
+
+// Poll は書き出しが終わっていれば保存先とエラーを返します。終わっていなければfinishedはfalseです
+func (r *Recorder) Poll() (path string, finished bool, err error) {
+	select {
+	case res := <-r.done:
+		r.busy = false
+		return res.path, true, res.err
+	default:
+		return "", false, nil
+	}
+}
+
+// encode はコマを256色に減色してGIFアニメとして保存します
+func encode(path string, frames []*image.RGBA, delay int) error {
+	anim := &gif.GIF{}
+	for _, f := range frames {
+		p := image.NewPaletted(f.Bounds(), palette.Plan9)
+		draw.Draw(p, p.Bounds(), f, image.Point{}, draw.Src)
+		anim.Image = append(anim.Image, p)
+		anim.Delay = append(anim.Delay, delay)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("保存先フォルダの作成に失敗: %v", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("GIFファイルの作成に失敗: %v", err)
+	}
+	defer file.Close()
+	if err := gif.EncodeAll(file, anim); err != nil {
+		return fmt.Errorf("GIFの書き出しに失敗: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"os"
+	"sort"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+	"SimpleShootingStar/i18n"
+	"SimpleShootingStar/stagedef"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	caravanFile            = "stage/caravan.json" // キャラバン専用のステージ
+	caravanFrames          = 2 * 60 * baseTPS     // キャラバンの制限時間（2分）
+	caravanLeaderboardSize = 10                   // ランキングに残すスコアの数
+)
+
+// caravanStage はキャラバン専用のステージです
+var caravanStage Stage
+
+// caravanHash はキャラバンのステージデータのハッシュです。ランキングはこのハッシュごとに分けて残します
+var caravanHash string
+
+// Caravan はキャラバンの状態です
+type Caravan struct {
+	timer int // 残りフレーム数
+	place int // 終了時のランキングの順位（1始まり、ランク外なら0）
+}
+
+// loadCaravan はキャラバン専用のステージを読み込みます
+func loadCaravan() error {
+	file, err := os.ReadFile(caravanFile)
+	if err != nil {
+		return fmt.Errorf("キャラバンのステージファイルの読み込みに失敗: %v", err)
+	}
+	if err := json.Unmarshal(file, &caravanStage); err != nil {
+		return fmt.Errorf("JSONのパースに失敗: %v", err)
+	}
+	caravanHash = stageHash(file)
+	if len(caravanStage.Waves) == 0 {
+		return fmt.Errorf("キャラバンのウェーブが1つも定義されていません")
+	}
+	for _, w := range caravanStage.Waves {
+		if err := stagedef.ValidateDelay(w.Delay); err != nil {
+			return fmt.Errorf("キャラバンのウェーブの設定が不正です: %v", err)
+		}
+		if err := validateEnemyType(w); err != nil {
+			return fmt.Errorf("キャラバンのウェーブの設定が不正です: %v", err)
+		}
+		if err := validateBehavior(w); err != nil {
+			return fmt.Errorf("キャラバンのウェーブの設定が不正です: %v", err)
+		}
+		if err := validateBossAttack(w); err != nil {
+			return fmt.Errorf("キャラバンのウェーブの設定が不正です: %v", err)
+		}
+		if err := validateDrop(w); err != nil {
+			return fmt.Errorf("キャラバンのウェーブの設定が不正です: %v", err)
+		}
+	}
+	if err := validateShuffle(caravanStage.Shuffle); err != nil {
+		return fmt.Errorf("キャラバンの設定が不正です: %v", err)
+	}
+	if err := caravanStage.Background.prepare(); err != nil {
+		return fmt.Errorf("キャラバンの背景の設定に失敗: %v", err)
+	}
+	if err := prepareStageEvents(&caravanStage, ""); err != nil {
+		return fmt.Errorf("キャラバンのイベントの設定が不正です: %v", err)
+	}
+	return nil
+}
+
+// startCaravan はキャラバンを始めます。残機は減らず、2分間のスコアだけを競います
+func (g *Game) startCaravan() {
+	g.score = 0
+	g.lives = tuning.Player.InitialLives
+	g.bombs = tuning.Player.InitialBombs
+	g.resetMultiplier()
+	g.rank = 0
+	g.caravan = &Caravan{timer: caravanFrames}
+	g.startStage(0)
+}
+
+// updateCaravan は残り時間を進めます。ウェーブを出し切ったら最初から繰り返し、
+// 時間切れになったら結果画面へ移ります。Escキーでは記録を残さずにタイトルへ戻ります
+func (g *Game) updateCaravan() {
+	c := g.caravan
+	if c == nil {
+		return
+	}
+	if g.gameState == GameStatePlaying && g.input.JustPressed(ebiten.KeyEscape) {
+		g.endCaravan()
+		return
+	}
+	if g.currentSpawn >= len(g.waves) {
+		g.currentSpawn = 0
+		g.waveTimer = 0
+		g.nextStageEvent = 0
+	}
+	if g.stageIntroTimer > 0 {
+		return
+	}
+	c.timer--
+	if c.timer > 0 {
+		return
+	}
+	c.place = recordCaravanScore(g.score)
+	saveStats()
+	g.startTransition(TransitionFade, func() {
+		g.gameState = GameStateCaravanResult
+	}, nil)
+}
+
+// recordCaravanScore はスコアをランキングに加え、順位を返します。ランク外なら0を返します
+func recordCaravanScore(score int) int {
+	r := recordsFor(caravanHash)
+	var place int
+	r.CaravanScores, place = rankScore(r.CaravanScores, score, caravanLeaderboardSize)
+	return place
+}
+
+// rankScore はスコアを高い順のランキングに加えて上位size件に切り詰め、
+// 新しいランキングと加えたスコアの順位を返します。ランク外なら順位は0です
+func rankScore(scores []int, score, size int) ([]int, int) {
+	scores = append(scores, score)
+	sort.Sort(sort.Reverse(sort.IntSlice(scores)))
+	if len(scores) > size {
+		scores = scores[:size]
+	}
+	for i, s := range scores {
+		if s == score {
+			return scores, i + 1
+		}
+	}
+	return scores, 0
+}
+
+// updateCaravanResult は結果画面の入力を処理します。Rキーでもう一度、スペースキーでタイトルへ戻ります
+func (g *Game) updateCaravanResult() {
+	if g.input.JustPressed(ebiten.KeyR) {
+		g.sound.Play("menuConfirm")
+		g.startTransition(TransitionIris, func() {
+			g.startCaravan()
+		}, nil)
+	} else if g.input.JustPressed(ebiten.KeySpace) {
+		g.sound.Play("menuConfirm")
+		g.endCaravan()
+	}
+}
+
+// endCaravan はキャラバンを終えてタイトルへ戻ります
+func (g *Game) endCaravan() {
+	g.startTransition(TransitionFade, func() {
+		// 作り直したゲームでも暗転から明けるまでの演出は続ける。ハイスコアは記録から読み直す
+		ship, input, transition := g.selectedShip, g.input, g.transition
+		*g = *NewGame()
+		g.selectedShip, g.input, g.transition = ship, input, transition
+	}, nil)
+}
+
+// drawCaravanTimer は残り時間を画面上部に描画します。残り10秒を切ると赤くなります
+func (g *Game) drawCaravanTimer(screen *ebiten.Image) {
+	if g.caravan == nil {
+		return
+	}
+	clr := color.Color(color.White)
+	if g.caravan.timer < 10*baseTPS {
+		clr = color.RGBA{255, 80, 80, 255}
+	}
+	hud.DrawTextOutline(screen, formatMillis(g.caravan.timer), fonts.Face(fonts.Medium), resolution.Width/2, ui(60), hud.AlignCenter, clr, hud.OutlineColor)
+}
+
+// drawCaravanResult はキャラバンの結果とランキングを描画します
+func (g *Game) drawCaravanResult(screen *ebiten.Image) {
+	hud.DrawTextOutline(screen, i18n.T("caravan.timeUp"), fonts.Face(fonts.Large), resolution.Width/2, ui(64), hud.AlignCenter, color.White, hud.OutlineColor)
+	hud.DrawTextShadow(screen, i18n.Tf("gameOver.score", g.score), fonts.Face(fonts.Medium), resolution.Width/2, ui(104), hud.AlignCenter, color.White)
+	for i, s := range recordsFor(caravanHash).CaravanScores {
+		clr := color.Color(color.White)
+		if i+1 == g.caravan.place {
+			clr = color.RGBA{255, 255, 0, 255}
+		}
+		hud.DrawTextShadow(screen, fmt.Sprintf("%2d.", i+1), fonts.Face(fonts.Medium), resolution.Width/2-ui(80), ui(150+i*26), hud.AlignRight, clr)
+		hud.DrawTextShadow(screen, fmt.Sprint(s), fonts.Face(fonts.Medium), resolution.Width/2+ui(100), ui(150+i*26), hud.AlignRight, clr)
+	}
+	hud.DrawTextShadow(screen, i18n.T("caravan.guide"), fonts.Face(fonts.Small), resolution.Width/2, resolution.Height-ui(20), hud.AlignCenter, color.White)
+}
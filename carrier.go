@@ -0,0 +1,46 @@
+package main
+
+const (
+	carrierBonus         = 500 // キャリア撃破時のボーナススコア
+	carrierChildSpeed    = 3.0 // 子機の速度
+	defaultSpawnInterval = 90  // stages.jsonで省略されたときの子機の発進間隔
+	defaultMaxChildren   = 3   // stages.jsonで省略されたときの子機の同時出現数の上限
+)
+
+// countChildren は指定した親から発進し、まだ生きている子機の数を返します
+func (g *Game) countChildren(parentID int) int {
+	count := 0
+	for _, e := range g.enemies {
+		if e.parentID == parentID {
+			count++
+		}
+	}
+	return count
+}
+
+// updateCarrier はキャリアの発進タイマーを進め、発進させる子機があれば返します。
+// 子機の数が上限に達している間は発進を見送ります。子機はキャリアの種類でも、さらに子機を発進させません
+func (g *Game) updateCarrier(e *Enemy) (Enemy, bool) {
+	c := e.carrier
+	c.spawnTimer--
+	if c.spawnTimer > 0 || e.y < 0 {
+		return Enemy{}, false
+	}
+	c.spawnTimer = c.spawnInterval
+	if g.countChildren(e.id) >= c.maxChildren {
+		return Enemy{}, false
+	}
+
+	// 自機のいる側へ曲がるように発進させる
+	w, h := enemySize(e.enemyType)
+	turnDir := 1
+	if g.playerX < e.x+w/2 {
+		turnDir = -1
+	}
+	child := g.newEnemy(c.childType, e.x+w/2-10, e.y+h, carrierChildSpeed)
+	child.parentID = e.id
+	child.carrier = nil
+	child.wave = e.wave
+	child.turnDirection = turnDir
+	return child, true
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const cheatBombs = 9 // 装備を最大にしたときのボム数
+
+// devMode は-devで起動したときに有効になる、開発者向けのチートを使えるかを表します
+var devMode bool
+
+// ステージ番号へのジャンプに使う数字キー
+var stageKeys = []ebiten.Key{
+	ebiten.Key1, ebiten.Key2, ebiten.Key3, ebiten.Key4, ebiten.Key5,
+	ebiten.Key6, ebiten.Key7, ebiten.Key8, ebiten.Key9,
+}
+
+func init() {
+	registerCommand("spawn", ConsoleCommand{
+		Usage: "<enemyType> <x> <y>",
+		Help:  "指定した種類の敵をプレイエリアの座標に出現させる",
+		Run: func(g *Game, args []string) (string, error) {
+			v, err := parseCommandInts(args, 3)
+			if err != nil {
+				return "", err
+			}
+			if v[0] < EnemyTypeStraight || v[0] >= EnemyTypeTurret {
+				// 砲台は取り付ける親がいないとすぐ消えるので単独では出さない
+				return "", fmt.Errorf("敵の種類が不正です: %d（%d〜%dにしてください）", v[0], EnemyTypeStraight, EnemyTypeTurret-1)
+			}
+			e := g.newEnemy(v[0], float64(v[1]), float64(v[2]), 2.0)
+			g.addEnemy(e)
+			return fmt.Sprintf("spawned enemy %d (type %d)", e.id, e.enemyType), nil
+		},
+	})
+	registerCommand("killall", ConsoleCommand{
+		Help: "画面上の敵をすべて倒す",
+		Run: func(g *Game, args []string) (string, error) {
+			killed := 0
+			for i := range g.enemies {
+				if !g.enemies[i].dead {
+					g.markDead(&g.enemies[i], false)
+					killed++
+				}
+			}
+			g.sweepDead()
+			return fmt.Sprintf("killed %d enemies", killed), nil
+		},
+	})
+	registerCommand("stage", ConsoleCommand{
+		Usage: "<番号>",
+		Help:  "そのステージ（1始まり）へジャンプする",
+		Run: func(g *Game, args []string) (string, error) {
+			v, err := parseCommandInts(args, 1)
+			if err != nil {
+				return "", err
+			}
+			if v[0] < 1 || v[0] > len(stages) {
+				return "", fmt.Errorf("ステージは1〜%dです", len(stages))
+			}
+			g.startStage(v[0] - 1)
+			g.cheatWave = 0
+			return "stage " + g.stage().Name, nil
+		},
+	})
+	registerCommand("give", ConsoleCommand{
+		Usage: "<weapon|bombs|lives|multiplier> <数>",
+		Help:  "ショット（自機の番号、1始まり）・ボム・残機・スコア倍率を設定する",
+		Run: func(g *Game, args []string) (string, error) {
+			if len(args) != 2 {
+				return "", fmt.Errorf("引数の数が違います")
+			}
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 0 {
+				return "", fmt.Errorf("数が不正です: %s", args[1])
+			}
+			switch args[0] {
+			case "weapon":
+				if n < 1 || n > len(ships) {
+					return "", fmt.Errorf("weaponは1〜%dです", len(ships))
+				}
+				g.selectedShip = n - 1
+				return "weapon: " + g.ship().Name, nil
+			case "bombs":
+				g.bombs = n
+			case "lives":
+				g.lives = n
+			case "multiplier":
+				g.multiplier = max(1, min(n, maxMultiplier))
+				g.tokenGauge = 0
+			default:
+				return "", fmt.Errorf("設定できない項目です: %s", args[0])
+			}
+			return fmt.Sprintf("bombs %d  lives %d  multiplier x%d", g.bombs, g.lives, g.multiplier), nil
+		},
+	})
+}
+
+// parseCommandInts はコマンドの引数をn個の整数として解釈します
+func parseCommandInts(args []string, n int) ([]int, error) {
+	if len(args) != n {
+		return nil, fmt.Errorf("引数の数が違います")
+	}
+	v := make([]int, n)
+	for i, a := range args {
+		x, err := strconv.Atoi(a)
+		if err != nil {
+			return nil, fmt.Errorf("整数ではありません: %s", a)
+		}
+		v[i] = x
+	}
+	return v, nil
+}
+
+// updateCheats はプレイ中の開発者向けチートのキー操作を処理します
+//   - F1：無敵の切り替え
+//   - F2：ボム・スコア倍率・残機を最大にする
+//   - 1〜9：そのステージへジャンプ
+//   - PageUp/PageDown：出現させるウェーブを選ぶ、F4：選んだウェーブの敵をすぐに出現させる
+func (g *Game) updateCheats() {
+	if !devMode {
+		return
+	}
+	if g.input.JustPressed(ebiten.KeyF1) {
+		g.cheatInvincible = !g.cheatInvincible
+	}
+	if g.input.JustPressed(ebiten.KeyF2) {
+		g.bombs = cheatBombs
+		g.multiplier = maxMultiplier
+		g.tokenGauge = 0
+		g.lives = tuning.Player.InitialLives
+	}
+	for i, key := range stageKeys {
+		if i < len(stages) && g.input.JustPressed(key) {
+			g.startStage(i)
+			g.cheatWave = 0
+			return
+		}
+	}
+
+	if len(g.waves) == 0 {
+		return
+	}
+	if g.input.JustPressed(ebiten.KeyPageDown) {
+		g.cheatWave = (g.cheatWave + 1) % len(g.waves)
+	}
+	if g.input.JustPressed(ebiten.KeyPageUp) {
+		g.cheatWave = (g.cheatWave + len(g.waves) - 1) % len(g.waves)
+	}
+	if g.input.JustPressed(ebiten.KeyF4) {
+		g.spawnWave(g.waves[g.cheatWave])
+	}
+}
+
+// drawCheats はチートの状態を画面上部に表示します
+func (g *Game) drawCheats(screen *ebiten.Image) {
+	if !devMode || g.gameState != GameStatePlaying {
+		return
+	}
+	label := fmt.Sprintf("DEV  wave %d/%d", g.cheatWave+1, len(g.waves))
+	if g.cheatInvincible {
+		label += "  INVINCIBLE"
+	}
+	hud.DrawTextShadow(screen, label, fonts.Face(fonts.Small), resolution.Width/2, ui(36), hud.AlignCenter, color.RGBA{255, 120, 120, 255})
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"image/color"
+	"log/slog"
+
+	"SimpleShootingStar/capture"
+	"SimpleShootingStar/i18n"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const clipDir = "clips" // GIFクリップの保存先
+
+// 直近10秒を15コマ/秒・半分の大きさで保持する
+var clipRecorder = capture.NewRecorder(10, 15, 0.5)
+
+// updateClip はF9キーで直近の画面をGIFクリップとして書き出し、書き出しが終わったら知らせます
+func (g *Game) updateClip() {
+	if g.input.JustPressed(ebiten.KeyF9) {
+		clipRecorder.Export(clipDir)
+	}
+
+	path, finished, err := clipRecorder.Poll()
+	if !finished {
+		return
+	}
+	if err != nil {
+		slog.Error("クリップの書き出しに失敗", "err", err)
+		return
+	}
+	slog.Info("クリップを保存しました", "path", path)
+	g.addOverlay(Overlay{
+		text:  i18n.T("overlay.clipSaved"),
+		y:     int(playArea.height) - 24,
+		timer: 90,
+		color: color.RGBA{120, 255, 120, 255},
+	})
+}
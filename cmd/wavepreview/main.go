@@ -0,0 +1,276 @@
+// wavepreview はステージファイルを読み込み、敵の出現タイミングを
+// タイムライン画像にして書き出すツールです。
+//
+// 各ステージを1列に並べ、縦軸を時間（1ピクセル=1フレーム）、横軸を出現位置のx座標として
+// 敵の種類ごとに色分けした点を描きます。列の左には1秒ごとの出現数の棒グラフを付けるので、
+// 実際に遊ぶ前に敵の密度や間延びを確認できます。
+//
+//	go run ./cmd/wavepreview -o wavepreview.png
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log"
+	"os"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/i18n"
+	"SimpleShootingStar/stagedef"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	bossWarningDuration = 120 // ボス出現前の警告でウェーブが止まるフレーム数
+	enemyTypeBoss       = 3
+
+	columnWidth  = 240 // 1ステージ分の列の幅
+	densityWidth = 40  // 出現数の棒グラフの幅
+	axisWidth    = 48  // 左端の時間目盛りの幅
+	headerHeight = 56  // ステージ名を書く領域の高さ
+	legendHeight = 40  // 下端の凡例の高さ
+	margin       = 12
+	framesPerSec = 60
+)
+
+// Wave はstages.jsonのウェーブのうち、タイムラインに必要な項目です
+type Wave struct {
+	EnemyType    int  `json:"enemyType"`
+	X            int  `json:"x"`
+	Delay        int  `json:"delay"`
+	ShootsBullet bool `json:"shootsBullet"`
+}
+
+// Stage はstages.jsonのステージのうち、タイムラインに必要な項目です
+type Stage struct {
+	Name  string `json:"name"`
+	Waves []Wave `json:"waves"`
+}
+
+// StageData はJSONファイルから読み込むステージデータの構造体です
+type StageData struct {
+	Stages []Stage `json:"stages"`
+}
+
+// spawn はタイムライン上の1体分の出現です
+type spawn struct {
+	frame int // ステージ開始（イントロ終了）からのフレーム数
+	wave  Wave
+}
+
+// 敵の種類ごとの表示色（enemyTypeの順、名前はstagedef.EnemyTypeNames）
+var (
+	enemyTypeColors = []color.RGBA{
+		{255, 80, 80, 255},
+		{80, 200, 255, 255},
+		{255, 220, 60, 255},
+		{255, 60, 255, 255},
+		{120, 255, 120, 255},
+		{255, 150, 40, 255},
+		{200, 200, 200, 255},
+	}
+
+	backgroundColor = color.RGBA{16, 16, 32, 255}
+	columnColor     = color.RGBA{28, 28, 52, 255}
+	gridColor       = color.RGBA{60, 60, 90, 255}
+	densityColor    = color.RGBA{120, 120, 200, 255}
+	textColor       = color.RGBA{230, 230, 230, 255}
+)
+
+func main() {
+	stagesPath := flag.String("stages", "stage/stages.json", "読み込むステージファイル")
+	output := flag.String("o", "wavepreview.png", "書き出す画像ファイル")
+	fontPath := flag.String("font", "assets/NotoSansJP-Regular.ttf", "文字の描画に使うフォント")
+	lang := flag.String("lang", i18n.DefaultLanguage, "凡例の表示言語")
+	flag.Parse()
+
+	data, err := loadStages(*stagesPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := fonts.Load(*fontPath); err != nil {
+		log.Fatal(err)
+	}
+	if err := i18n.Load("lang", *lang); err != nil {
+		log.Fatal(err)
+	}
+
+	img := render(data.Stages)
+	if err := writePNG(*output, img); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%d ステージのタイムラインを %s に書き出しました", len(data.Stages), *output)
+}
+
+// loadStages はJSONファイルからステージ情報を読み込みます
+func loadStages(path string) (*StageData, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ステージファイルの読み込みに失敗: %v", err)
+	}
+
+	var data StageData
+	if err := json.Unmarshal(file, &data); err != nil {
+		return nil, fmt.Errorf("JSONのパースに失敗: %v", err)
+	}
+	for _, s := range data.Stages {
+		for _, w := range s.Waves {
+			if err := stagedef.ValidateDelay(w.Delay); err != nil {
+				return nil, fmt.Errorf("%sのウェーブの設定が不正です: %v", s.Name, err)
+			}
+		}
+	}
+	return &data, nil
+}
+
+// timeline はゲームと同じ累積delay方式で各ウェーブの出現フレームを求めます。
+// ボスの前には警告の間だけウェーブが止まる分も加えます。
+func timeline(stage Stage) []spawn {
+	spawns := make([]spawn, 0, len(stage.Waves))
+	frame := 0
+	for _, w := range stage.Waves {
+		frame += w.Delay
+		if w.EnemyType == enemyTypeBoss {
+			frame += bossWarningDuration
+		}
+		spawns = append(spawns, spawn{frame: frame, wave: w})
+	}
+	return spawns
+}
+
+// render はすべてのステージのタイムラインを1枚の画像に描きます
+func render(stages []Stage) *image.RGBA {
+	timelines := make([][]spawn, len(stages))
+	length := framesPerSec
+	for i, s := range stages {
+		timelines[i] = timeline(s)
+		if n := len(timelines[i]); n > 0 && timelines[i][n-1].frame+framesPerSec > length {
+			length = timelines[i][n-1].frame + framesPerSec
+		}
+	}
+
+	width := axisWidth + len(stages)*(columnWidth+margin) + margin
+	height := headerHeight + length + legendHeight
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(backgroundColor), image.Point{}, draw.Src)
+
+	// 時間の目盛り（1秒ごとに線、5秒ごとに数字）
+	for f := 0; f <= length; f += framesPerSec {
+		y := headerHeight + f
+		fillRect(img, axisWidth-6, y, width-margin, y+1, gridColor)
+		if sec := f / framesPerSec; sec%5 == 0 {
+			drawText(img, fmt.Sprintf("%ds", sec), fonts.Small, margin, y+5, textColor)
+		}
+	}
+
+	for i, s := range stages {
+		left := axisWidth + i*(columnWidth+margin)
+		drawColumn(img, s, timelines[i], left, length)
+	}
+
+	drawLegend(img, margin, headerHeight+length+26)
+	return img
+}
+
+// drawColumn は1ステージ分の列を描きます
+func drawColumn(img *image.RGBA, stage Stage, spawns []spawn, left, length int) {
+	top := headerHeight
+	fieldLeft := left + densityWidth
+	fieldW := columnWidth - densityWidth
+
+	fillRect(img, fieldLeft, top, left+columnWidth, top+length, columnColor)
+	drawText(img, stage.Name, fonts.Small, left, 22, textColor)
+	last := 0
+	if len(spawns) > 0 {
+		last = spawns[len(spawns)-1].frame
+	}
+	drawText(img, fmt.Sprintf("%d waves / %.1fs", len(spawns), float64(last)/framesPerSec), fonts.Small, left, 44, textColor)
+
+	// 1秒ごとの出現数の棒グラフ。横に長いほどその1秒に敵が集中している
+	counts := make([]int, length/framesPerSec+1)
+	for _, sp := range spawns {
+		counts[sp.frame/framesPerSec]++
+	}
+	for sec, n := range counts {
+		if n == 0 {
+			continue
+		}
+		w := n * 8
+		if w > densityWidth-4 {
+			w = densityWidth - 4
+		}
+		y := top + sec*framesPerSec
+		fillRect(img, fieldLeft-2-w, y+1, fieldLeft-2, y+framesPerSec-1, densityColor)
+		drawText(img, fmt.Sprint(n), fonts.Small, fieldLeft-2-w, y+framesPerSec/2+5, textColor)
+	}
+
+	// 出現位置を種類ごとの色で描く。ボスは大きく、弾を撃つ敵は枠付きにする
+	for _, sp := range spawns {
+		x := fieldLeft + sp.wave.X*fieldW/stagedef.BaseWidth
+		y := top + sp.frame
+		clr := enemyColor(sp.wave.EnemyType)
+		r := 4
+		if sp.wave.EnemyType == enemyTypeBoss {
+			r = 8
+		}
+		if sp.wave.ShootsBullet {
+			fillRect(img, x-r-1, y-r-1, x+r+2, y+r+2, textColor)
+		}
+		fillRect(img, x-r, y-r, x+r+1, y+r+1, clr)
+	}
+}
+
+// drawLegend は敵の種類と色の対応を描きます
+func drawLegend(img *image.RGBA, x, y int) {
+	for i, name := range stagedef.EnemyTypeNames {
+		fillRect(img, x, y-10, x+10, y, enemyTypeColors[i])
+		label := i18n.T("enemy." + name)
+		drawText(img, label, fonts.Small, x+14, y, textColor)
+		x += 14 + font.MeasureString(fonts.Face(fonts.Small), label).Ceil() + 16
+	}
+}
+
+// enemyColor は敵の種類に対応する表示色を返します
+func enemyColor(enemyType int) color.RGBA {
+	if enemyType < 0 || enemyType >= len(enemyTypeColors) {
+		return textColor
+	}
+	return enemyTypeColors[enemyType]
+}
+
+// fillRect は矩形を塗りつぶします
+func fillRect(img *image.RGBA, x0, y0, x1, y1 int, clr color.Color) {
+	draw.Draw(img, image.Rect(x0, y0, x1, y1), image.NewUniform(clr), image.Point{}, draw.Over)
+}
+
+// drawText はベースラインを(x, y)として文字列を描きます
+func drawText(img *image.RGBA, s string, size fonts.Size, x, y int, clr color.Color) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(clr),
+		Face: fonts.Face(size),
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(s)
+}
+
+// writePNG は画像をPNGファイルに書き出します
+func writePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("画像ファイルの作成に失敗: %v", err)
+	}
+	defer file.Close()
+
+	if err := png.Encode(file, img); err != nil {
+		return fmt.Errorf("PNGの書き出しに失敗: %v", err)
+	}
+	return nil
+}
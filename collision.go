@@ -0,0 +1,7 @@
+package main
+
+// overlaps は2つの矩形（左上の座標と幅・高さ）が重なっているかを返します。
+// 辺が接しているだけなら重なっていないとみなします
+func overlaps(ax, ay, aw, ah, bx, by, bw, bh float64) bool {
+	return ax < bx+bw && ax+aw > bx && ay < by+bh && ay+ah > by
+}
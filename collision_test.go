@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestOverlaps(t *testing.T) {
+	// 基準の矩形は(10, 10)から幅20・高さ20
+	tests := []struct {
+		name       string
+		x, y, w, h float64
+		want       bool
+	}{
+		{"inside", 15, 15, 4, 8, true},
+		{"covers", 0, 0, 40, 40, true},
+		{"overlaps left edge", 8, 12, 4, 8, true},
+		{"overlaps bottom edge", 12, 28, 4, 8, true},
+		{"touches left edge", 6, 12, 4, 8, false},
+		{"touches right edge", 30, 12, 4, 8, false},
+		{"touches top edge", 12, 2, 4, 8, false},
+		{"touches bottom edge", 12, 30, 4, 8, false},
+		{"far away", 100, 100, 4, 8, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := overlaps(tt.x, tt.y, tt.w, tt.h, 10, 10, 20, 20); got != tt.want {
+				t.Errorf("overlaps = %v, want %v", got, tt.want)
+			}
+			// 重なりは向きによらない
+			if got := overlaps(10, 10, 20, 20, tt.x, tt.y, tt.w, tt.h); got != tt.want {
+				t.Errorf("overlaps (swapped) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	consoleLines   = 12 // コンソールに残す出力の行数
+	consoleHistory = 20 // 上下キーで呼び出せる入力の履歴の数
+)
+
+// ConsoleCommand はデバッグコンソールのコマンドです。
+// Runは引数（コマンド名を除く）を受け取り、コンソールに表示する結果を返します
+type ConsoleCommand struct {
+	Usage string // 引数の書き方（例：「<enemyType> <x> <y>」）
+	Help  string // 説明
+	Run   func(g *Game, args []string) (string, error)
+}
+
+// consoleCommands はコマンド名ごとに登録されたコマンドです
+var consoleCommands = map[string]ConsoleCommand{}
+
+// registerCommand はデバッグコンソールにコマンドを登録します。
+// 各サブシステムのinitから呼び出し、そのサブシステムを操作するコマンドを追加します
+func registerCommand(name string, cmd ConsoleCommand) {
+	consoleCommands[name] = cmd
+}
+
+// Console はデバッグコンソールの状態です。リスタートでGameを作り直しても出力や履歴が残るよう、Gameとは別に持ちます
+type Console struct {
+	open    bool
+	line    []rune   // 入力中の行
+	output  []string // 出力（古い順）
+	history []string // 実行した入力（古い順）
+	recall  int      // 履歴を呼び出している位置（len(history)なら新しい入力）
+	blink   int      // カーソルの点滅用のカウンタ
+}
+
+var console Console
+
+func init() {
+	registerCommand("help", ConsoleCommand{
+		Help: "コマンドの一覧を表示する",
+		Run: func(g *Game, args []string) (string, error) {
+			names := make([]string, 0, len(consoleCommands))
+			for name := range consoleCommands {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			lines := make([]string, len(names))
+			for i, name := range names {
+				cmd := consoleCommands[name]
+				lines[i] = strings.TrimSpace(name+" "+cmd.Usage) + " : " + cmd.Help
+			}
+			return strings.Join(lines, "\n"), nil
+		},
+	})
+}
+
+// updateConsole はコンソールの開閉と入力を処理します。
+// コンソールを開いている間はtrueを返し、ゲームは止まってキー入力もゲームに渡りません。
+// -devで起動したときだけ使えます
+func (g *Game) updateConsole() bool {
+	if !devMode {
+		return false
+	}
+	if g.keyJustPressed(settings.ConsoleKey) {
+		console.open = !console.open
+		console.line = nil
+		console.recall = len(console.history)
+		return true
+	}
+	if !console.open {
+		return false
+	}
+	console.blink++
+	if g.keyJustPressed(ebiten.KeyEscape) {
+		console.open = false
+		return true
+	}
+	console.line = ebiten.AppendInputChars(console.line)
+	if g.keyJustPressed(ebiten.KeyBackspace) && len(console.line) > 0 {
+		console.line = console.line[:len(console.line)-1]
+	}
+	if g.keyJustPressed(ebiten.KeyUp) && console.recall > 0 {
+		console.recall--
+		console.line = []rune(console.history[console.recall])
+	}
+	if g.keyJustPressed(ebiten.KeyDown) && console.recall < len(console.history) {
+		console.recall++
+		console.line = nil
+		if console.recall < len(console.history) {
+			console.line = []rune(console.history[console.recall])
+		}
+	}
+	if g.keyJustPressed(ebiten.KeyEnter) {
+		line := strings.TrimSpace(string(console.line))
+		console.line = nil
+		if line != "" {
+			console.history = append(console.history, line)
+			if len(console.history) > consoleHistory {
+				console.history = console.history[len(console.history)-consoleHistory:]
+			}
+			console.print("> " + line)
+			console.print(g.runCommand(line))
+		}
+		console.recall = len(console.history)
+	}
+	return true
+}
+
+// runCommand は1行のコマンドを実行し、結果かエラーの文字列を返します
+func (g *Game) runCommand(line string) string {
+	fields := strings.Fields(line)
+	cmd, ok := consoleCommands[fields[0]]
+	if !ok {
+		return fmt.Sprintf("unknown command: %s (help で一覧を表示)", fields[0])
+	}
+	result, err := cmd.Run(g, fields[1:])
+	if err != nil {
+		return fmt.Sprintf("error: %v (usage: %s)", err, strings.TrimSpace(fields[0]+" "+cmd.Usage))
+	}
+	return result
+}
+
+// print はコンソールに出力を追加します。複数行の文字列は行ごとに分けます
+func (c *Console) print(text string) {
+	if text == "" {
+		return
+	}
+	c.output = append(c.output, strings.Split(text, "\n")...)
+	if len(c.output) > consoleLines {
+		c.output = c.output[len(c.output)-consoleLines:]
+	}
+}
+
+// drawConsole はコンソールを画面上部に半透明で描画します
+func (g *Game) drawConsole(screen *ebiten.Image) {
+	if !console.open {
+		return
+	}
+	height := ui((consoleLines + 1) * 16)
+	ebitenutil.DrawRect(screen, 0, 0, float64(resolution.Width), float64(height+ui(8)), color.RGBA{0, 0, 0, 200})
+	for i, line := range console.output {
+		hud.DrawText(screen, line, fonts.Face(fonts.Small), ui(8), ui(16+i*16), hud.AlignLeft, color.RGBA{200, 200, 200, 255})
+	}
+	cursor := ""
+	if console.blink/30%2 == 0 {
+		cursor = "_"
+	}
+	hud.DrawText(screen, "> "+string(console.line)+cursor, fonts.Face(fonts.Small), ui(8), height, hud.AlignLeft, color.RGBA{120, 255, 120, 255})
+}
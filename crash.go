@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	crashDir       = "crashes" // クラッシュレポートを書き出すフォルダ
+	crashInputKeep = 32        // クラッシュレポートに残す直前の入力の数
+)
+
+// gameStateNames はクラッシュレポートに書くゲームの状態の名前です
+var gameStateNames = map[int]string{
+	GameStateTitle:            "Title",
+	GameStateShipSelect:       "ShipSelect",
+	GameStatePlaying:          "Playing",
+	GameStateStageClear:       "StageClear",
+	GameStatePlayerExplosion:  "PlayerExplosion",
+	GameStateGameOver:         "GameOver",
+	GameStateStats:            "Stats",
+	GameStateBossSelect:       "BossSelect",
+	GameStateTimeAttackSelect: "TimeAttackSelect",
+	GameStateCaravanResult:    "CaravanResult",
+	GameStateStagePackSelect:  "StagePackSelect",
+	GameStateDailyResult:      "DailyResult",
+}
+
+// crashGuard はゲームのUpdate・Draw・Layoutでのパニックを受け止めるラッパーです。
+// パニックしたらクラッシュレポートを書き出し、以降はゲームの代わりにレポートの場所を示す画面を表示します
+type crashGuard struct {
+	game    *Game
+	updates int      // Updateを呼んだ回数
+	inputs  []string // 直前に押したキー（古い順）
+	screen  *ErrorScreen
+}
+
+// runGame はパニックをクラッシュレポートにするラッパーを通してゲームを実行します
+func runGame(g *Game) error {
+	guard := &crashGuard{game: g}
+	defer func() {
+		// Ebitenの外（呼び出し元のゴルーチン）でのパニックは画面を出せないので、レポートだけ残す
+		if r := recover(); r != nil && guard.screen == nil {
+			path := guard.writeReport(r, debug.Stack())
+			fmt.Fprintf(os.Stderr, "クラッシュしました。レポート: %s\n", path)
+			os.Exit(2)
+		}
+	}()
+	err := ebiten.RunGame(guard)
+	if guard.screen != nil {
+		// エラー画面を閉じたらクラッシュとして終了する
+		os.Exit(2)
+	}
+	if err == ebiten.Termination {
+		return nil
+	}
+	return err
+}
+
+// Update はゲームを1回更新します。パニックしたらエラー画面に切り替えます
+func (c *crashGuard) Update() error {
+	if c.screen != nil {
+		return c.screen.Update()
+	}
+	defer c.recover()
+	// パニックしてもレポートを書く前に、このUpdateで押されたキーを記録する
+	defer c.recordInputs()
+	c.updates++
+	return c.game.Update()
+}
+
+// recordInputs はゲームがこのUpdateで受け取ったキーを、直前の入力として残します
+func (c *crashGuard) recordInputs() {
+	for _, key := range c.game.justPolled {
+		c.inputs = append(c.inputs, fmt.Sprintf("update %d: %s", c.updates, key))
+	}
+	if len(c.inputs) > crashInputKeep {
+		c.inputs = c.inputs[len(c.inputs)-crashInputKeep:]
+	}
+}
+
+// Draw はゲームを描画します。パニックしたら次のフレームからエラー画面を描画します
+func (c *crashGuard) Draw(screen *ebiten.Image) {
+	if c.screen != nil {
+		c.screen.Draw(screen)
+		return
+	}
+	defer c.recover()
+	c.game.Draw(screen)
+}
+
+// Layout はエラー画面に切り替えた後はエラー画面の大きさを返します
+func (c *crashGuard) Layout(outsideWidth, outsideHeight int) (int, int) {
+	if c.screen != nil {
+		return c.screen.Layout(outsideWidth, outsideHeight)
+	}
+	return c.game.Layout(outsideWidth, outsideHeight)
+}
+
+// recover はパニックを受け止め、クラッシュレポートを書き出してエラー画面に切り替えます。
+// deferで呼び出します
+func (c *crashGuard) recover() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	path := c.writeReport(r, debug.Stack())
+	c.screen = &ErrorScreen{title: "The game crashed: a crash report was saved", lines: []string{
+		"Report: " + path,
+		"",
+	}, input: newKeyboardInput()}
+	c.screen.lines = append(c.screen.lines, wrapText(fmt.Sprint(r), errorScreenWrap)...)
+	c.screen.lines = append(c.screen.lines, "", "Please attach the report when you report this bug.")
+	ebiten.SetWindowSize(resolution.Width, resolution.Height)
+}
+
+// writeReport はパニックの内容・ゲームの状態・直前の入力・スタックトレースをファイルに書き出し、
+// そのパスを返します。書き出せなければ標準エラー出力に出して空文字列を返します
+func (c *crashGuard) writeReport(r any, stack []byte) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "SimpleShootingStar crash report\n")
+	fmt.Fprintf(&b, "time: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&b, "go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "panic: %v\n\n", r)
+	b.WriteString("[game]\n")
+	b.WriteString(c.game.crashSummary())
+	b.WriteString("\n[last inputs]\n")
+	for _, in := range c.inputs {
+		b.WriteString(in + "\n")
+	}
+	b.WriteString("\n[stack]\n")
+	b.Write(stack)
+	report := b.String()
+
+	path := filepath.Join(crashDir, "crash-"+time.Now().Format("20060102-150405")+".txt")
+	err := os.MkdirAll(crashDir, 0755)
+	if err == nil {
+		err = os.WriteFile(path, []byte(report), 0644)
+	}
+	if err != nil {
+		slog.Error("クラッシュレポートの書き込みに失敗", "file", path, "err", err)
+		fmt.Fprint(os.Stderr, report)
+		return ""
+	}
+	slog.Error("クラッシュしました", "panic", fmt.Sprint(r), "report", path)
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return path
+}
+
+// crashSummary はクラッシュレポートに書くゲームの状態の要約を返します
+func (g *Game) crashSummary() string {
+	mode := "normal"
+	switch {
+	case g.practice != nil:
+		mode = "bossPractice"
+	case g.timeAttack != nil:
+		mode = "timeAttack"
+	case g.caravan != nil:
+		mode = "caravan"
+	case g.daily != nil:
+		mode = "daily"
+	case g.tutorial != nil:
+		mode = "tutorial"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "state: %s\n", gameStateNames[g.gameState])
+	fmt.Fprintf(&b, "mode: %s\n", mode)
+	fmt.Fprintf(&b, "pack: %s (%s)\n", stagePacks[currentPack].Name, stagePacks[currentPack].Hash)
+	fmt.Fprintf(&b, "stage: %d\n", g.currentStage+1)
+	fmt.Fprintf(&b, "%s\n", g.waveInfo())
+	fmt.Fprintf(&b, "ship: %d\n", g.selectedShip)
+	fmt.Fprintf(&b, "player: (%.1f, %.1f)\n", g.playerX, g.playerY)
+	fmt.Fprintf(&b, "score: %d lives: %d bombs: %d multiplier: %d rank: %.2f\n", g.score, g.lives, g.bombs, g.multiplier, g.rank)
+	fmt.Fprintf(&b, "enemies: %d enemyBullets: %d bullets: %d particles: %d\n", len(g.enemies), len(g.enemyBullets), len(g.bullets), g.particles.len())
+	fmt.Fprintf(&b, "tps: %d resolution: %dx%d\n", logicTPS, resolution.Width, resolution.Height)
+	return b.String()
+}
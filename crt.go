@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const crtShaderFile = "assets/shader/crt.kage" // ブラウン管風の後処理のシェーダー
+
+// ブラウン管風の後処理の強さ
+const (
+	crtDistortion = 0.03 // たる型のゆがみ
+	crtScanline   = 0.25 // 走査線の暗さ
+	crtBloom      = 0.6  // 明るい部分のにじみ
+)
+
+// crtShader は読み込んだ後処理のシェーダーです。後処理をしないときはnil
+var crtShader *ebiten.Shader
+
+// loadCRTShader は設定で有効にされていれば後処理のシェーダーを読み込みます
+func loadCRTShader() error {
+	if !settings.CRT {
+		return nil
+	}
+	src, err := os.ReadFile(crtShaderFile)
+	if err != nil {
+		return fmt.Errorf("シェーダーの読み込みに失敗: %v", err)
+	}
+	shader, err := ebiten.NewShader(src)
+	if err != nil {
+		return fmt.Errorf("シェーダーのコンパイルに失敗: %v", err)
+	}
+	crtShader = shader
+	return nil
+}
+
+// applyCRT は描画し終えた画面にブラウン管風の後処理をかけた画像を返します
+func (g *Game) applyCRT(src *ebiten.Image) *ebiten.Image {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	if g.crt == nil {
+		g.crt = ebiten.NewImage(w, h)
+	}
+	op := &ebiten.DrawRectShaderOptions{}
+	op.Images[0] = src
+	op.Uniforms = map[string]any{
+		"Distortion": float32(crtDistortion),
+		"Scanline":   float32(crtScanline),
+		"Bloom":      float32(crtBloom),
+	}
+	g.crt.DrawRectShader(w, h, crtShader, op)
+	return g.crt
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+	"SimpleShootingStar/i18n"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	dailyGenerator       = 1  // ステージの作り方の版。作り方を変えたら上げて、ランキングを分ける
+	dailySegments        = 10 // ボスの前に並べる敵のまとまりの数
+	dailyLeaderboardSize = 10 // 日ごとのランキングに残すスコアの数
+)
+
+// Daily はデイリーチャレンジの状態です
+type Daily struct {
+	date    string // 挑戦している日付（UTC、YYYY-MM-DD）
+	stage   Stage  // その日の種から作ったステージ
+	cleared bool   // ボスまで倒したか
+	place   int    // 終了時のランキングの順位（1始まり、ランク外なら0）
+}
+
+// dailySeed は日付（UTC）から種を作ります。同じ日なら誰が遊んでも同じ種になります
+func dailySeed(t time.Time) (int64, string) {
+	t = t.UTC()
+	return int64(t.Year()*10000 + int(t.Month())*100 + t.Day()), t.Format("2006-01-02")
+}
+
+// dailyRecordKey はその日のランキングを残す記録のキーです
+func dailyRecordKey(date string) string {
+	return fmt.Sprintf("daily-%d-%s", dailyGenerator, date)
+}
+
+// generateDailyStage は種から敵のまとまりとボスを並べたステージを作ります。
+// 後半ほど敵が速く、弾を撃つ敵が増えます
+func generateDailyStage(seed int64, date string) Stage {
+	r := rand.New(rand.NewSource(seed))
+	skies := []string{"#000010", "#0a0418", "#001410", "#140808", "#08080f"}
+	stage := Stage{
+		Name: i18n.Tf("daily.stageName", date),
+		Background: Background{
+			SkyColor:  skies[r.Intn(len(skies))],
+			StarCount: 50 + r.Intn(70),
+			StarSpeed: 0.8 + r.Float64(),
+		},
+	}
+
+	for i := 0; i < dailySegments; i++ {
+		progress := float64(i) / dailySegments
+		speed := 1.8 + r.Float64()*0.6 + progress
+		shootChance := 0.2 + progress*0.5
+		first := 60 + r.Intn(60) // 前のまとまりからの間隔
+		// wave はまとまりのj番目の敵を作ります。2番目以降はgapフレームおきに続けて出します
+		wave := func(enemyType, x, j, gap int) Wave {
+			w := Wave{EnemyType: enemyType, X: x, Delay: gap, Speed: speed, TurnDirection: 1}
+			if j == 0 {
+				w.Delay = first
+			}
+			if r.Float64() < shootChance {
+				w.ShootsBullet, w.BulletType = true, r.Intn(4)
+			}
+			return w
+		}
+		switch r.Intn(4) {
+		case 0: // 横一列の編隊
+			for j := 0; j < 5; j++ {
+				w := wave(EnemyTypeStraight, 80+j*120, j, 12)
+				w.Formation = i + 1
+				stage.Waves = append(stage.Waves, w)
+			}
+		case 1: // 縦に続くサインカーブの編隊
+			x := 80 + r.Intn(480)
+			for j := 0; j < 4; j++ {
+				w := wave(EnemyTypeSine, x, j, 15)
+				w.Formation = i + 1
+				stage.Waves = append(stage.Waves, w)
+			}
+		case 2: // 左右対称の特殊な動きの敵
+			x := 80 + r.Intn(200)
+			left, right := wave(EnemyTypeSpecial, x, 0, 0), wave(EnemyTypeSpecial, 600-x, 1, 0)
+			right.TurnDirection = -1
+			stage.Waves = append(stage.Waves, left, right)
+		default: // 大型の敵。序盤は代わりに1体ずつの直進の敵にする
+			switch {
+			case i < dailySegments/3:
+				for j := 0; j < 3; j++ {
+					stage.Waves = append(stage.Waves, wave(EnemyTypeStraight, 80+r.Intn(480), j, 20))
+				}
+			case r.Intn(2) == 0:
+				w := wave(EnemyTypeMiner, 120+r.Intn(360), 0, 0)
+				w.Speed = 1
+				stage.Waves = append(stage.Waves, w)
+			default:
+				w := wave(EnemyTypeCarrier, 160+r.Intn(280), 0, 0)
+				w.Speed, w.ShootsBullet = 0.6, false
+				w.ChildType, w.SpawnInterval, w.MaxChildren = EnemyTypeSpecial, 90, 2+r.Intn(2)
+				stage.Waves = append(stage.Waves, w)
+			}
+		}
+	}
+
+	boss := Wave{EnemyType: EnemyTypeBoss, X: 290, Delay: 180, ShootsBullet: true, Speed: 1.5, TurnDirection: 1}
+	boss.Attack = []BossStep{
+		{Op: "wait", Frames: 10},
+		{Op: "ring", Count: 12 + r.Intn(9), From: float64(r.Intn(30))},
+		{Op: "wait", Frames: 20 + r.Intn(20)},
+		{Op: "aim", Count: 3 + 2*r.Intn(2), Spread: 10 + float64(r.Intn(8))},
+		{Op: "wait", Frames: 20},
+		{Op: "sweep", From: -60, To: 60, Count: 8 + r.Intn(6), Interval: 3 + r.Intn(3)},
+	}
+	stage.Waves = append(stage.Waves, boss)
+	return stage
+}
+
+// startDaily は今日のステージを作ってデイリーチャレンジを始めます
+func (g *Game) startDaily() {
+	seed, date := dailySeed(time.Now())
+	d := &Daily{date: date, stage: generateDailyStage(seed, date)}
+	if err := d.stage.Background.prepare(); err != nil {
+		slog.Warn("デイリーの背景の設定に失敗", "err", err)
+	}
+	slog.Info("デイリーチャレンジ開始", "date", date, "seed", seed)
+	g.score = 0
+	g.lives = tuning.Player.InitialLives
+	g.bombs = tuning.Player.InitialBombs
+	g.resetMultiplier()
+	g.rank = 0
+	g.daily = d
+	g.startStage(0)
+}
+
+// updateDaily はEscキーで記録を残さずにタイトルへ戻ります
+func (g *Game) updateDaily() {
+	if g.daily != nil && g.gameState == GameStatePlaying && g.input.JustPressed(ebiten.KeyEscape) {
+		g.endDaily()
+	}
+}
+
+// finishDaily はスコアをその日のランキングに加えて結果画面へ移ります
+func (g *Game) finishDaily(cleared bool) {
+	d := g.daily
+	d.cleared = cleared
+	r := recordsFor(dailyRecordKey(d.date))
+	r.DailyScores, d.place = rankScore(r.DailyScores, g.score, dailyLeaderboardSize)
+	saveStats()
+	slog.Info("デイリーチャレンジ終了", "date", d.date, "score", g.score, "cleared", cleared, "place", d.place)
+	g.startTransition(TransitionFade, func() {
+		g.gameState = GameStateDailyResult
+	}, nil)
+}
+
+// updateDailyResult は結果画面の入力を処理します。Rキーでもう一度、スペースキーでタイトルへ戻ります
+func (g *Game) updateDailyResult() {
+	if g.input.JustPressed(ebiten.KeyR) {
+		g.sound.Play("menuConfirm")
+		g.startTransition(TransitionIris, func() {
+			g.startDaily()
+		}, func() {
+			g.sound.PlayBGM("stage")
+		})
+	} else if g.input.JustPressed(ebiten.KeySpace) {
+		g.sound.Play("menuConfirm")
+		g.endDaily()
+	}
+}
+
+// endDaily はデイリーチャレンジを終えてタイトルへ戻ります
+func (g *Game) endDaily() {
+	g.startTransition(TransitionFade, func() {
+		// 作り直したゲームでも暗転から明けるまでの演出は続ける。ハイスコアは記録から読み直す
+		ship, input, transition := g.selectedShip, g.input, g.transition
+		*g = *NewGame()
+		g.selectedShip, g.input, g.transition = ship, input, transition
+	}, nil)
+}
+
+// drawDailyResult はデイリーチャレンジの結果とその日のランキングを描画します
+func (g *Game) drawDailyResult(screen *ebiten.Image) {
+	d := g.daily
+	title := i18n.T("daily.failed")
+	if d.cleared {
+		title = i18n.T("daily.cleared")
+	}
+	hud.DrawTextOutline(screen, title, fonts.Face(fonts.Large), resolution.Width/2, ui(56), hud.AlignCenter, color.White, hud.OutlineColor)
+	hud.DrawTextShadow(screen, i18n.Tf("daily.date", d.date), fonts.Face(fonts.Small), resolution.Width/2, ui(84), hud.AlignCenter, color.White)
+	hud.DrawTextShadow(screen, i18n.Tf("gameOver.score", g.score), fonts.Face(fonts.Medium), resolution.Width/2, ui(112), hud.AlignCenter, color.White)
+	for i, s := range recordsFor(dailyRecordKey(d.date)).DailyScores {
+		clr := color.Color(color.White)
+		if i+1 == d.place {
+			clr = color.RGBA{255, 255, 0, 255}
+		}
+		hud.DrawTextShadow(screen, fmt.Sprintf("%2d.", i+1), fonts.Face(fonts.Medium), resolution.Width/2-ui(80), ui(150+i*26), hud.AlignRight, clr)
+		hud.DrawTextShadow(screen, fmt.Sprint(s), fonts.Face(fonts.Medium), resolution.Width/2+ui(100), ui(150+i*26), hud.AlignRight, clr)
+	}
+	hud.DrawTextShadow(screen, i18n.T("daily.guide"), fonts.Face(fonts.Small), resolution.Width/2, resolution.Height-ui(20), hud.AlignCenter, color.White)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const debugFastForward = 4 // 早送り中の速さの倍率
+
+// debugMode は-debugで起動したときに有効になる、ステージ調整用の操作を使えるかを表します
+var debugMode bool
+
+// debugStepRate は早送り・一時停止・コマ送りを反映して、このUpdateで進めるステップ数を返します。
+// 一時停止中もキーを受け付けられるよう、ステップ単位ではなくUpdateごとにキーを読みます
+func (g *Game) debugStepRate(rate float64) float64 {
+	if !debugMode {
+		return rate
+	}
+	if g.keyJustPressed(ebiten.KeyF6) {
+		g.debugPaused = !g.debugPaused
+	}
+	if g.debugPaused {
+		// 一時停止中はF7キーを押すたびに1ステップだけ進める
+		if g.keyJustPressed(ebiten.KeyF7) {
+			return 1
+		}
+		return 0
+	}
+	if g.input.Pressed(ebiten.KeyF5) {
+		return rate * debugFastForward
+	}
+	return rate
+}
+
+// drawDebugControls は一時停止中・早送り中であることを画面上部に表示します
+func (g *Game) drawDebugControls(screen *ebiten.Image) {
+	if !debugMode {
+		return
+	}
+	label := ""
+	switch {
+	case g.debugPaused:
+		label = "PAUSED  F6: Resume  F7: Step"
+	case g.input.Pressed(ebiten.KeyF5):
+		label = fmt.Sprintf(">> x%d", debugFastForward)
+	default:
+		return
+	}
+	hud.DrawTextShadow(screen, label, fonts.Face(fonts.Small), resolution.Width/2, resolution.Height-ui(8), hud.AlignCenter, color.RGBA{0, 255, 0, 255})
+}
+
+// updateDebug はF3キーでデバッグ表示を切り替えます
+func (g *Game) updateDebug() {
+	if g.input.JustPressed(ebiten.KeyF3) {
+		g.showDebug = !g.showDebug
+	}
+}
+
+// drawDebug は画面左下にフレームレートやランクなどの内部状態を表示します
+func (g *Game) drawDebug(screen *ebiten.Image) {
+	if !g.showDebug {
+		return
+	}
+	lines := []string{
+		fmt.Sprintf("TPS: %.1f  FPS: %.1f", ebiten.ActualTPS(), ebiten.ActualFPS()),
+		fmt.Sprintf("Enemies: %d  Bullets: %d  Particles: %d", len(g.enemies), len(g.enemyBullets), g.particles.len()),
+		fmt.Sprintf("Rank: %.2f  x%.2f", g.rank, g.rankMultiplier()),
+		g.waveInfo().String(),
+	}
+	for i, line := range lines {
+		y := resolution.Height - ui(8+(len(lines)-1-i)*16)
+		hud.DrawTextShadow(screen, line, fonts.Face(fonts.Small), ui(4), y, hud.AlignLeft, color.RGBA{0, 255, 0, 255})
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"image/color"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	effectPoolSize   = 256 // 噴射炎・発射炎に使う粒の最大数
+	thrusterLife     = 10  // 噴射炎の粒の寿命（フレーム数）
+	muzzleFlashLife  = 4   // 発射炎の寿命（フレーム数）
+	muzzleFlashSize  = 7   // 発射炎の大きさ
+	thrusterPerFrame = 2   // 1フレームに出す噴射炎の粒の数
+)
+
+// Effect は噴射炎・発射炎の小さな光の粒です
+type Effect struct {
+	x, y    float64
+	vx, vy  float64
+	size    float64
+	life    int // 残りフレーム数
+	maxLife int
+	color   color.RGBA
+}
+
+// EffectPool は噴射炎・発射炎の粒を入れておく固定長の入れ物です。
+// 毎フレーム大量に出しては消える粒でメモリの確保が起きないよう、配列を使い回します。
+// いっぱいのときに出した粒は捨てます（見た目だけの粒なので、減っても遊びには影響しない）
+type EffectPool struct {
+	items [effectPoolSize]Effect
+	count int
+}
+
+// emit は粒を1つ加えます
+func (p *EffectPool) emit(e Effect) {
+	if p.count == effectPoolSize {
+		return
+	}
+	e.maxLife = e.life
+	p.items[p.count] = e
+	p.count++
+}
+
+// update は粒を動かし、寿命の切れた粒を詰めて取り除きます
+func (p *EffectPool) update() {
+	alive := 0
+	for i := 0; i < p.count; i++ {
+		e := p.items[i]
+		e.x += e.vx
+		e.y += e.vy
+		e.life--
+		if e.life > 0 {
+			p.items[alive] = e
+			alive++
+		}
+	}
+	p.count = alive
+}
+
+// draw は粒を寿命に応じて薄くしながら描画します
+func (p *EffectPool) draw(field *ebiten.Image) {
+	for i := 0; i < p.count; i++ {
+		e := &p.items[i]
+		a := float64(e.life) / float64(e.maxLife)
+		// 色は乗算済みアルファで渡す
+		c := color.RGBA{uint8(float64(e.color.R) * a), uint8(float64(e.color.G) * a), uint8(float64(e.color.B) * a), uint8(float64(e.color.A) * a)}
+		ebitenutil.DrawRect(field, e.x-e.size/2, e.y-e.size/2, e.size, e.size, c)
+	}
+}
+
+// emitThruster は自機の後ろから噴射炎の粒を出します。上へ動いているときは炎を長くします
+func (g *Game) emitThruster() {
+	speed := 2.0
+	if g.input.Pressed(ebiten.KeyUp) {
+		speed = 3.5
+	}
+	for i := 0; i < thrusterPerFrame; i++ {
+		g.effects.emit(Effect{
+			x:     g.playerX + 10 + (rand.Float64()-0.5)*4,
+			y:     g.playerY + 17,
+			vx:    (rand.Float64() - 0.5) * 0.6,
+			vy:    speed + rand.Float64(),
+			size:  2 + rand.Float64()*2,
+			life:  thrusterLife - rand.Intn(4),
+			color: color.RGBA{255, uint8(140 + rand.Intn(100)), 40, 255},
+		})
+	}
+}
+
+// emitMuzzleFlash は弾を撃った砲口に一瞬だけ発射炎を出します
+func (g *Game) emitMuzzleFlash(x, y float64) {
+	g.effects.emit(Effect{x: x, y: y, size: muzzleFlashSize, life: muzzleFlashLife, color: color.RGBA{255, 255, 200, 255}})
+	g.effects.emit(Effect{x: x, y: y - 3, vy: -1, size: muzzleFlashSize / 2, life: muzzleFlashLife, color: color.RGBA{255, 255, 255, 255}})
+}
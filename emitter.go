@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"math"
+	"math/rand"
+	"os"
+)
+
+const effectsFile = "effects.json" // パーティクルの出し方の定義ファイル名
+
+// EmitterDef はeffects.jsonで定義するパーティクルの出し方です。
+// ゲーム中のコードからは名前で参照するので、再ビルドせずに見た目を調整できます
+type EmitterDef struct {
+	Count    int        `json:"count"`    // 一度に出す粒の数
+	Speed    [2]float64 `json:"speed"`    // 初速の範囲（最小, 最大）
+	Size     [2]float64 `json:"size"`     // 大きさの範囲（最小, 最大）
+	Gravity  float64    `json:"gravity"`  // 1フレームごとに下向きに加わる速さ
+	Lifetime [2]int     `json:"lifetime"` // 寿命の範囲（フレーム数。最小, 最大）
+	Angle    float64    `json:"angle"`    // 飛ばす向き（度。0で右、90で下）
+	Spread   float64    `json:"spread"`   // 向きのばらつきの幅（度。360で全方向）
+	Colors   []string   `json:"colors"`   // 色の移り変わり（#RRGGBB。出た直後から消えるまでを順に補間する）
+
+	ramp []color.RGBA
+}
+
+// requiredEmitters はゲーム中のコードが参照するパーティクルの名前です。
+// effects.jsonにひとつでも欠けていれば起動時にエラーにします
+var requiredEmitters = []string{
+	"explosionSmall", "explosionBoss", "explosionPlayer",
+	"hitSpark", "armorPing", "bulletFade", "shieldDeflect",
+}
+
+// emitters は読み込んだパーティクルの出し方です
+var emitters map[string]*EmitterDef
+
+// loadEmitters はパーティクルの出し方の定義ファイルを読み込みます
+func loadEmitters() error {
+	file, err := os.ReadFile(effectsFile)
+	if err != nil {
+		return fmt.Errorf("パーティクルの定義ファイルの読み込みに失敗: %v", err)
+	}
+	var defs map[string]*EmitterDef
+	if err := json.Unmarshal(file, &defs); err != nil {
+		return fmt.Errorf("JSONのパースに失敗: %v", err)
+	}
+	for name, d := range defs {
+		if d.Count < 1 || d.Speed[0] > d.Speed[1] || d.Size[0] <= 0 || d.Size[0] > d.Size[1] || d.Lifetime[0] < 1 || d.Lifetime[0] > d.Lifetime[1] {
+			return fmt.Errorf("パーティクル(%s)の値が不正です", name)
+		}
+		if len(d.Colors) == 0 {
+			return fmt.Errorf("パーティクル(%s)の色が1つも指定されていません", name)
+		}
+		for _, s := range d.Colors {
+			c, err := parseHexColor(s)
+			if err != nil {
+				return fmt.Errorf("パーティクル(%s)の%v", name, err)
+			}
+			d.ramp = append(d.ramp, c)
+		}
+	}
+	for _, name := range requiredEmitters {
+		if _, ok := defs[name]; !ok {
+			return fmt.Errorf("パーティクル(%s)が定義されていません", name)
+		}
+	}
+	emitters = defs
+	return nil
+}
+
+// emitParticles は名前で指定したパーティクルを(x, y)から定義どおりの向きに出します
+func (g *Game) emitParticles(name string, x, y float64) {
+	d := emitters[name]
+	g.emitParticlesToward(name, x, y, d.Angle*math.Pi/180)
+}
+
+// emitParticlesToward は名前で指定したパーティクルを(x, y)からangle（ラジアン）の向きを中心に出します。
+// 弾が跳ね返る向きなど、出す向きがその場で決まるときに使います
+func (g *Game) emitParticlesToward(name string, x, y, angle float64) {
+	d := emitters[name]
+	spread := d.Spread * math.Pi / 180
+	for i := 0; i < d.Count; i++ {
+		a := angle + (rand.Float64()-0.5)*spread
+		speed := d.Speed[0] + rand.Float64()*(d.Speed[1]-d.Speed[0])
+		life := d.Lifetime[0] + rand.Intn(d.Lifetime[1]-d.Lifetime[0]+1)
+		g.particles.add(Particle{
+			x:        x,
+			y:        y,
+			vx:       math.Cos(a) * speed,
+			vy:       math.Sin(a) * speed,
+			size:     d.Size[0] + rand.Float64()*(d.Size[1]-d.Size[0]),
+			alpha:    1.0,
+			lifetime: life,
+			maxLife:  life,
+			gravity:  d.Gravity,
+			ramp:     d.ramp,
+		})
+	}
+}
+
+// rampColor はパーティクルの経過時間に応じて色の移り変わりを補間した色を返します。
+// 色の指定がなければ白を返します
+func (p *Particle) rampColor() color.RGBA {
+	if len(p.ramp) == 0 {
+		return color.RGBA{255, 255, 255, 255}
+	}
+	if len(p.ramp) == 1 || p.maxLife <= 1 {
+		return p.ramp[0]
+	}
+	t := float64(p.maxLife-p.lifetime) / float64(p.maxLife-1) * float64(len(p.ramp)-1)
+	i := min(int(t), len(p.ramp)-2)
+	f := min(t-float64(i), 1)
+	a, b := p.ramp[i], p.ramp[i+1]
+	lerp := func(x, y uint8) uint8 { return uint8(float64(x) + (float64(y)-float64(x))*f) }
+	return color.RGBA{lerp(a.R, b.R), lerp(a.G, b.G), lerp(a.B, b.B), lerp(a.A, b.A)}
+}
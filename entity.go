@@ -0,0 +1,180 @@
+package main
+
+import (
+	"math/rand"
+
+	"SimpleShootingStar/tween"
+)
+
+const (
+	bossHomeY          = 80 // ボスが左右に動き回る高さ
+	bossEntranceFrames = 90 // ボスが画面上部の定位置まで降りてくるフレーム数
+)
+
+// Position は位置のコンポーネントです
+type Position struct {
+	x, y float64
+}
+
+// Velocity は移動の速さと向きのコンポーネントです
+type Velocity struct {
+	speed         float64
+	turnDirection int // 横に曲がる向き（1:右, -1:左）
+}
+
+// Health は耐久度とダメージの受け方のコンポーネントです
+type Health struct {
+	hp               int  // 耐久度
+	maxHP            int  // 出現時の耐久度
+	armor            int  // 自機弾のダメージを減らす装甲値
+	flashTimer       int  // 被弾時に白く光る残りフレーム数
+	hpBarTimer       int  // HPバーを表示する残りフレーム数（被弾するたびに戻る）
+	hasTurrets       bool // ボス本体を守る砲台付きで出現したか（砲台が残っている間は無敵）
+	parts            int  // 出現時に取り付けられた砲台の数
+	weakPointExposed bool // 砲台がすべて破壊され弱点が露出したか
+}
+
+// Shooter は弾を撃つ敵のコンポーネントです
+type Shooter struct {
+	bulletType int // 0:主人公狙い, 1:真下, 2:斜め右下, 3:斜め左下, 4:レーザー
+	cooldown   int // 次の発射までのフレーム数
+}
+
+// BossBrain はボスの行動パターンのコンポーネントです
+type BossBrain struct {
+	state         int         // 行動状態（0:移動, 1:攻撃準備, 2:攻撃中, 3:休憩）
+	timer         int         // 今の行動状態になってからのフレーム数
+	moveDirection int         // 移動方向（-1:左, 1:右）
+	entrance      tween.Tween // 画面上部の定位置まで降りてくる登場の動き（y座標）
+
+	script      []BossStep // 攻撃状態で実行する命令
+	step        int        // 実行中の命令の番号
+	stepTimer   int        // 実行中の命令の経過（waitは経過フレーム数、sweepは次の弾までのフレーム数）
+	fired       int        // 実行中のsweepで撃った弾の数
+	shotSounded bool       // 今回の攻撃で効果音を鳴らしたか
+}
+
+// Mount は親の敵に取り付けられた砲台のコンポーネントです
+type Mount struct {
+	offsetX, offsetY float64 // 親からの相対位置
+	score            int     // 破壊したときのスコア
+}
+
+// MineLayer は機雷を設置する敵のコンポーネントです
+type MineLayer struct {
+	timer int // 次の機雷投下までのフレーム数
+}
+
+// CarrierBay は子機を発進させる敵のコンポーネントです
+type CarrierBay struct {
+	childType     int // 発進させる子機の種類
+	spawnInterval int // 子機の発進間隔
+	maxChildren   int // 同時に存在できる子機の数
+	spawnTimer    int // 次の発進までのフレーム数
+}
+
+// Enemy は敵1体分のエンティティです。一部の敵だけが持つ機能はポインタのコンポーネントで、nilなら持ちません
+type Enemy struct {
+	id          int // 敵ごとに一意な番号
+	parentID    int // 発進元の敵の番号（0なら親なし）
+	formationID int // 属している編隊の番号（0なら編隊なし）
+	wave        int // 出現したウェーブの番号（出現順で1始まり、0ならウェーブ以外から出現）
+	enemyType   int
+	drop        string  // 倒したときに落とすアイテム（空ならなし）
+	time        float64 // 時間経過（サインカーブ用）
+	phase       int     // 特殊な動きのフェーズ
+	Position
+	Velocity
+	Health
+	Lifecycle
+
+	shooter     *Shooter
+	boss        *BossBrain
+	mount       *Mount
+	mineLayer   *MineLayer
+	carrier     *CarrierBay
+	evader      *Evader
+	kamikaze    *Kamikaze
+	frontShield *FrontShield
+	systems     enemySystems // 出現したときに決めた、毎フレーム動かす処理
+}
+
+// enemySystems は敵ごとに毎フレーム動かす処理の組み合わせです
+type enemySystems uint8
+
+const (
+	systemKamikaze    enemySystems = 1 << iota // 特攻（突進中は通常の移動の代わりになる）
+	systemEvader                               // 弾避け
+	systemBoss                                 // ボスの行動パターン
+	systemMineLayer                            // 機雷の設置
+	systemCarrier                              // 子機の発進
+	systemShooter                              // 射撃
+	systemFrontShield                          // 正面の盾の向き
+)
+
+// resolveSystems は持っているコンポーネントから、毎フレーム動かす処理を決めます
+func (e *Enemy) resolveSystems() {
+	e.systems = 0
+	for _, c := range []struct {
+		has    bool
+		system enemySystems
+	}{
+		{e.kamikaze != nil, systemKamikaze},
+		{e.evader != nil, systemEvader},
+		{e.boss != nil, systemBoss},
+		{e.mineLayer != nil, systemMineLayer},
+		{e.carrier != nil, systemCarrier},
+		{e.shooter != nil, systemShooter},
+		{e.frontShield != nil, systemFrontShield},
+	} {
+		if c.has {
+			e.systems |= c.system
+		}
+	}
+}
+
+// has は敵がその処理を動かすかを返します
+func (e *Enemy) has(s enemySystems) bool {
+	return e.systems&s != 0
+}
+
+// addEnemy はコンポーネントを付け終えた敵の動かす処理を決めて、画面に出します
+func (g *Game) addEnemy(e Enemy) {
+	e.resolveSystems()
+	g.enemies = append(g.enemies, e)
+}
+
+// newEnemy は種類に応じたコンポーネントを持つ敵を作ります。
+// 弾を撃つかどうかや砲台の取り付けは、呼び出し側で設定します
+func (g *Game) newEnemy(enemyType int, x, y, speed float64) Enemy {
+	e := Enemy{
+		id:        g.newEntityID(),
+		enemyType: enemyType,
+		Position:  Position{x: x, y: y},
+		Velocity:  Velocity{speed: speed, turnDirection: 1},
+		Health:    Health{hp: enemyHP(enemyType), maxHP: enemyHP(enemyType)},
+		Lifecycle: newLifecycle(),
+	}
+	switch enemyType {
+	case EnemyTypeBoss:
+		e.boss = &BossBrain{
+			moveDirection: 1, // 右向きから開始
+			script:        defaultBossAttack(),
+			entrance:      tween.New(y, bossHomeY, bossEntranceFrames, tween.OutQuad),
+		}
+	case EnemyTypeMiner:
+		e.mineLayer = &MineLayer{timer: mineDropTime / 2}
+	case EnemyTypeCarrier:
+		e.carrier = &CarrierBay{
+			spawnInterval: defaultSpawnInterval,
+			maxChildren:   defaultMaxChildren,
+			spawnTimer:    defaultSpawnInterval / 2,
+		}
+	}
+	return e
+}
+
+// newShooter は弾の種類を指定して、発射間隔がばらけた射撃コンポーネントを作ります
+func newShooter(bulletType int) *Shooter {
+	return &Shooter{bulletType: bulletType, cooldown: tuning.EnemyShot.CooldownMin + rand.Intn(tuning.EnemyShot.CooldownRange)}
+}
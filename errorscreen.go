@@ -0,0 +1,97 @@
+package main
+
+import (
+	"image/color"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const errorScreenWrap = 72 // エラー画面でエラーの本文を折り返す文字数
+
+// ErrorScreen は起動に必要なファイルが読み込めなかったときや、プレイ中にパニックしたときに、
+// パニックの出力の代わりにウィンドウに表示するエラー画面です
+type ErrorScreen struct {
+	title string // 見出し
+	lines []string
+	input Input // 閉じるESCキーを読む入力
+}
+
+// showStartupError は読み込めなかったファイルと探した場所をエラー画面に表示し、
+// 閉じるまで待ってから終了します。ウィンドウを開かないシミュレーションでは表示せずに終了します
+func showStartupError(path string, err error) {
+	slog.Error("起動に必要なファイルの読み込みに失敗", "file", path, "err", err)
+	if headless {
+		os.Exit(1)
+	}
+
+	abs, absErr := filepath.Abs(path)
+	if absErr != nil {
+		abs = path
+	}
+	wd, _ := os.Getwd()
+	s := &ErrorScreen{title: "Failed to start: a required file could not be loaded", lines: []string{
+		"File: " + path,
+		"Looked at: " + abs,
+		"Working directory: " + wd,
+		"",
+	}, input: newKeyboardInput()}
+	s.lines = append(s.lines, wrapText(err.Error(), errorScreenWrap)...)
+
+	// フォントも読み込めなければ組み込みのフォントで表示する
+	if fonts.Face(fonts.Medium) == nil {
+		if err := fonts.Load(fontFile); err != nil {
+			fonts.LoadFallback()
+		}
+		fonts.SetScale(uiScale())
+	}
+	ebiten.SetWindowSize(resolution.Width, resolution.Height)
+	ebiten.SetWindowTitle("SimpleShootingStar - Error")
+	if err := ebiten.RunGame(s); err != nil && err != ebiten.Termination {
+		slog.Error("エラー画面の表示に失敗", "err", err)
+	}
+	os.Exit(1)
+}
+
+// wrapText は文字列を指定した文字数ごとに折り返します
+func wrapText(s string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		runes := []rune(line)
+		for len(runes) > width {
+			lines = append(lines, string(runes[:width]))
+			runes = runes[width:]
+		}
+		lines = append(lines, string(runes))
+	}
+	return lines
+}
+
+// Update はESCキーかウィンドウを閉じる操作で終了します
+func (s *ErrorScreen) Update() error {
+	if s.input.Pressed(ebiten.KeyEscape) || ebiten.IsWindowBeingClosed() {
+		return ebiten.Termination
+	}
+	return nil
+}
+
+// Draw はエラーの内容を描画します。案内は組み込みのフォントでも読めるよう英語にしています
+func (s *ErrorScreen) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{40, 0, 0, 255})
+	hud.DrawText(screen, s.title, fonts.Face(fonts.Medium), ui(16), ui(40), hud.AlignLeft, color.RGBA{255, 200, 80, 255})
+	for i, line := range s.lines {
+		hud.DrawText(screen, line, fonts.Face(fonts.Small), ui(16), ui(80+i*20), hud.AlignLeft, color.White)
+	}
+	hud.DrawText(screen, "Press ESC to quit", fonts.Face(fonts.Small), ui(16), resolution.Height-ui(20), hud.AlignLeft, color.White)
+}
+
+// Layout は内部解像度をそのまま使います
+func (s *ErrorScreen) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return resolution.Width, resolution.Height
+}
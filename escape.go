@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+)
+
+func init() {
+	subscribe(EventEnemyEscaped, func(g *Game, e Event) { g.onEnemyEscaped(e) })
+}
+
+// onEnemyEscaped は逃した敵を数え、tuning.jsonのescapeで設定されていればスコアを減らします。
+// 数はステージクリアの集計とログに出し、逃しやすいウェーブを見つける目安にします。
+// 砲台は親と一緒に消えるだけなので数えません
+func (g *Game) onEnemyEscaped(e Event) {
+	if e.EnemyType == EnemyTypeTurret || g.tutorial != nil {
+		return
+	}
+	g.stageEscaped++
+	if e.Wave > 0 {
+		if g.escapedByWave == nil {
+			g.escapedByWave = map[int]int{}
+		}
+		g.escapedByWave[e.Wave]++
+	}
+	if p := tuning.Escape.ScorePenalty; p > 0 && g.practice == nil {
+		g.score = max(g.score-p, 0)
+	}
+}
+
+// worstEscapeWave は最も多く敵を逃したウェーブの番号（1始まり）とその数を返します。
+// 同じ数なら先のウェーブを返し、逃していなければ0を返します
+func (g *Game) worstEscapeWave() (wave, count int) {
+	for w, n := range g.escapedByWave {
+		if n > count || n == count && w < wave {
+			wave, count = w, n
+		}
+	}
+	return wave, count
+}
+
+// escapeDeduction はステージクリアのボーナスから逃した敵の分として引く点数を返します。
+// ボーナスより多くは引きません
+func (g *Game) escapeDeduction(bonus int) int {
+	return min(bonus, g.stageEscaped*tuning.Escape.BonusPenalty*g.multiplier)
+}
+
+// logEscapes はステージで逃した敵の数をウェーブごとにログへ出します
+func (g *Game) logEscapes() {
+	if g.stageEscaped == 0 {
+		return
+	}
+	waves := make([]int, 0, len(g.escapedByWave))
+	for w := range g.escapedByWave {
+		waves = append(waves, w)
+	}
+	sort.Ints(waves)
+	args := []any{"stage", g.currentStage + 1, "total", g.stageEscaped}
+	for _, w := range waves {
+		args = append(args, slog.Int(fmt.Sprintf("wave%d", w), g.escapedByWave[w]))
+	}
+	slog.Info("画面外へ逃した敵", args...)
+}
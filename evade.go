@@ -0,0 +1,67 @@
+package main
+
+import "math"
+
+const (
+	evadeLookahead = 24   // 何フレーム先までの自機弾の進路を調べるか
+	evadeMargin    = 6.0  // 弾の進路からこれだけ離れるまで避け続ける
+	evadeAccel     = 0.3  // 横に避けるときの加速度（1フレームあたり）
+	evadeMaxSpeed  = 3.0  // 横に避ける最高速度
+	evadeFriction  = 0.85 // 脅威がないときの横の速さの減衰率
+)
+
+// Evader は自機弾を横に避ける敵のコンポーネントです
+type Evader struct {
+	vx float64 // 今の横方向の速さ（加速度を制限して変える）
+}
+
+// incomingBullet は矩形（x, y, w, h）に向かってくる自機弾のうち、最も早く届くものが
+// 届く位置のx座標を返します。弾は等速で進むとして、evadeLookaheadフレーム以内に
+// 矩形の下端（下から来る弾）か上端（跳ね返って上から来る弾）の高さに届くものだけを調べます
+func (g *Game) incomingBullet(x, y, w, h float64) (hitX float64, found bool) {
+	soonest := math.Inf(1)
+	for _, b := range g.bullets {
+		var t float64
+		switch {
+		case b.vy < 0 && b.y > y:
+			t = (b.y - (y + h)) / -b.vy
+		case b.vy > 0 && b.y+8 < y+h:
+			t = (y - (b.y + 8)) / b.vy
+		default:
+			continue
+		}
+		if t < 0 {
+			t = 0
+		}
+		if t > evadeLookahead || t >= soonest {
+			continue
+		}
+		px := b.x + b.vx*t
+		if px+4+evadeMargin <= x || px-evadeMargin >= x+w {
+			continue
+		}
+		soonest, hitX, found = t, px+2, true
+	}
+	return hitX, found
+}
+
+// updateEvader は向かってくる自機弾があれば弾の進路から離れる向きへ加速し、
+// なければ横の速さを徐々に落とします。プレイエリアの端では壁側へは避けません
+func (g *Game) updateEvader(e *Enemy) {
+	ev := e.evader
+	w, h := enemySize(e.enemyType)
+	if hitX, ok := g.incomingBullet(e.x, e.y, w, h); ok && e.y > 0 {
+		dir := 1.0
+		if hitX > e.x+w/2 {
+			dir = -1
+		}
+		// 壁際で追い詰められたら反対側へ抜ける
+		if (dir < 0 && e.x <= 0) || (dir > 0 && e.x+w >= playArea.width) {
+			dir = -dir
+		}
+		ev.vx = math.Max(-evadeMaxSpeed, math.Min(evadeMaxSpeed, ev.vx+dir*evadeAccel))
+	} else {
+		ev.vx *= evadeFriction
+	}
+	e.x = math.Max(0, math.Min(playArea.width-w, e.x+ev.vx))
+}
@@ -0,0 +1,57 @@
+package main
+
+// EventKind はゲーム中の出来事の種類です
+type EventKind int
+
+const (
+	EventGameStarted      EventKind = iota // ゲーム開始（リスタートを含む）
+	EventPlayFrame                         // プレイ中の1フレーム経過
+	EventShotFired                         // 自機がショットを撃った
+	EventEnemyKilled                       // 敵を倒した
+	EventPlayerHit                         // 敵弾・敵・機雷・レーザーが無敵でない自機に当たった
+	EventPlayerDied                        // 自機が被弾してやられた
+	EventBombUsed                          // ボムを使った
+	EventEnemyEscaped                      // 敵が倒されずに画面外へ出た
+	EventBossPhaseChanged                  // ボスの行動状態が切り替わった
+	EventPowerUpCollected                  // スタートークンを拾った
+	EventBossApproaching                   // ボスの出現まで5秒を切った
+)
+
+// Event はゲーム中の出来事です。統計などの集計や、音などのサブシステムへ通知します
+type Event struct {
+	Kind      EventKind
+	EntityID  int     // 出来事の元になった物体の番号（0なら特定の物体なし）
+	ParentID  int     // 元になった敵の親の番号（0なら親なし）
+	EnemyType int     // 敵に関する出来事のときの敵の種類
+	X, Y      float64 // 出来事が起きた位置
+	Points    int     // EventEnemyKilledのときに入ったスコア
+	Phase     int     // EventBossPhaseChangedのときの新しい行動状態
+	Formation int     // 敵に関する出来事のときの敵の編隊の番号（0なら編隊なし）
+	Wave      int     // 敵に関する出来事のときの、敵が出現したウェーブの番号（1始まり、0ならウェーブ以外から出現）
+}
+
+// EventHandler はイベントバスから特定の種類の出来事を受け取る関数です。
+// リスタートでGameが作り直されても使えるよう、Gameは引数で受け取ります
+type EventHandler func(g *Game, e Event)
+
+// eventHandlers は出来事の種類ごとに登録されたハンドラです
+var eventHandlers = map[EventKind][]EventHandler{}
+
+// subscribe は指定した種類の出来事を受け取るハンドラをイベントバスに登録します。
+// 各サブシステムのinitから呼び出し、Updateから個別に呼び出さなくても済むようにします
+func subscribe(kind EventKind, h EventHandler) {
+	eventHandlers[kind] = append(eventHandlers[kind], h)
+}
+
+// newEntityID は敵や機雷などの物体に割り当てる一意な番号を返します
+func (g *Game) newEntityID() int {
+	g.nextEntityID++
+	return g.nextEntityID
+}
+
+// emit はその種類のハンドラに出来事を通知します
+func (g *Game) emit(e Event) {
+	for _, h := range eventHandlers[e.Kind] {
+		h(g, e)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// パーティクルの形
+const (
+	ParticleSquare = iota // 四角い火花（既定）
+	ParticleRing          // 広がっていく衝撃波の輪
+	ParticleShard         // 回転しながら飛ぶ破片
+	ParticleFlash         // 一瞬だけ光る白い閃光
+)
+
+// 爆発の大きさ
+const (
+	ExplosionSmall  = iota // 雑魚敵や機雷
+	ExplosionBoss          // ボス
+	ExplosionPlayer        // 自機
+)
+
+// ExplosionStyle は爆発の大きさごとのパーティクルの出し方です
+type ExplosionStyle struct {
+	sparks      string    // 四角い火花のパーティクルの名前（effects.json）
+	shards      int       // 破片の数
+	ringGrowths []float64 // 衝撃波の輪ごとの1フレームで広がる量
+	ringLife    int       // 衝撃波の寿命（フレーム数）
+	flashSize   float64   // 閃光の半径
+	flashLife   int       // 閃光の寿命（フレーム数）
+}
+
+var explosionStyles = map[int]ExplosionStyle{
+	ExplosionSmall:  {sparks: "explosionSmall", shards: 4, ringGrowths: []float64{2}, ringLife: 14, flashSize: 14, flashLife: 4},
+	ExplosionBoss:   {sparks: "explosionBoss", shards: 14, ringGrowths: []float64{3, 5}, ringLife: 30, flashSize: 60, flashLife: 10},
+	ExplosionPlayer: {sparks: "explosionPlayer", shards: 8, ringGrowths: []float64{3.5}, ringLife: 20, flashSize: 28, flashLife: 6},
+}
+
+// createExplosion は爆発エフェクトのパーティクルを生成します。
+// 大きさに応じて火花・破片・衝撃波の輪・閃光の数や規模を変えます
+func (g *Game) createExplosion(x, y float64, clr color.RGBA, size int) {
+	style := explosionStyles[size]
+	g.emitParticles(style.sparks, x, y)
+	for i := 0; i < style.shards; i++ {
+		angle := rand.Float64() * math.Pi * 2
+		speed := 1 + rand.Float64()*2.5
+		g.particles.add(Particle{
+			x:        x,
+			y:        y,
+			vx:       math.Cos(angle) * speed,
+			vy:       math.Sin(angle)*speed - 1,
+			size:     6 + rand.Float64()*6,
+			alpha:    1.0,
+			lifetime: 40 + rand.Intn(20),
+			gravity:  0.1,
+			shape:    ParticleShard,
+			rotation: rand.Float64() * math.Pi * 2,
+			spin:     (rand.Float64() - 0.5) * 0.5,
+			color:    clr,
+		})
+	}
+	for _, growth := range style.ringGrowths {
+		g.particles.add(Particle{
+			x: x, y: y, size: 4, growth: growth, alpha: 1.0, lifetime: style.ringLife,
+			shape: ParticleRing, color: clr,
+		})
+	}
+	g.particles.add(Particle{
+		x: x, y: y, size: style.flashSize, alpha: 1.0, lifetime: style.flashLife,
+		shape: ParticleFlash, color: color.RGBA{255, 255, 255, 255},
+	})
+}
+
+// updateShape は形ごとの動きでパーティクルを1フレーム進めます。
+// 衝撃波と閃光はその場にとどまり、それ以外は重力で落ちていきます
+func (p *Particle) updateShape() {
+	switch p.shape {
+	case ParticleRing:
+		p.size += p.growth
+	case ParticleFlash:
+	default:
+		p.x += p.vx
+		p.y += p.vy
+		p.vy += p.gravity
+		p.rotation += p.spin
+	}
+}
+
+// drawShape は衝撃波・破片・閃光のパーティクルを描画します。
+// 四角い火花なら何もせずfalseを返します
+func (p *Particle) drawShape(field *ebiten.Image) bool {
+	a := max(p.alpha, 0)
+	// 色は乗算済みアルファで渡す
+	c := color.RGBA{uint8(float64(p.color.R) * a), uint8(float64(p.color.G) * a), uint8(float64(p.color.B) * a), uint8(255 * a)}
+	switch p.shape {
+	case ParticleRing:
+		vector.StrokeCircle(field, float32(p.x), float32(p.y), float32(p.size), 2, c, true)
+	case ParticleShard:
+		dx, dy := math.Cos(p.rotation)*p.size/2, math.Sin(p.rotation)*p.size/2
+		vector.StrokeLine(field, float32(p.x-dx), float32(p.y-dy), float32(p.x+dx), float32(p.y+dy), 2, c, true)
+	case ParticleFlash:
+		vector.DrawFilledCircle(field, float32(p.x), float32(p.y), float32(p.size*a), c, true)
+	default:
+		return false
+	}
+	return true
+}
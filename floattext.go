@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	floatingTextFrames = 40 // 浮かぶ文字の表示フレーム数
+	floatingTextMerge  = 10 // 表示し始めてからこのフレーム数までは同じ敵へのダメージをまとめる
+	maxFloatingTexts   = 24 // 同時に表示する浮かぶ文字の上限（超えたら古いものから消す）
+)
+
+// FloatingText はその場から少しずつ浮かび上がって消える文字です
+type FloatingText struct {
+	x, y     float64
+	text     string
+	value    int // 数値を表示しているときの値（まとめて足すのに使う）
+	targetID int // 数値の対象の敵の番号（0なら対象なし）
+	timer    int // 残り表示フレーム数
+	color    color.RGBA
+}
+
+// addFloatingText は浮かぶ文字を追加します。上限を超えたら古いものから消します
+func (g *Game) addFloatingText(t FloatingText) {
+	if len(g.floatingTexts) >= maxFloatingTexts {
+		g.floatingTexts = append(g.floatingTexts[:0], g.floatingTexts[1:]...)
+	}
+	g.floatingTexts = append(g.floatingTexts, t)
+}
+
+// addDamageNumber は設定で有効なら敵に与えたダメージを浮かぶ数字で表示します。
+// 連射で文字が増えすぎないよう、出たばかりの同じ敵の数字があればそこに足します
+func (g *Game) addDamageNumber(e *Enemy, damage int) {
+	if !settings.DamageNumbers {
+		return
+	}
+	for i := range g.floatingTexts {
+		t := &g.floatingTexts[i]
+		if t.targetID == e.id && t.timer > floatingTextFrames-floatingTextMerge {
+			t.value += damage
+			t.text = fmt.Sprint(t.value)
+			return
+		}
+	}
+	w, _ := enemySize(e.enemyType)
+	c := color.RGBA{255, 255, 255, 255}
+	if e.armor > 0 {
+		// 装甲で減らされたダメージは装甲のHPバーと同じ色で示す
+		c = color.RGBA{120, 180, 255, 255}
+	}
+	g.addFloatingText(FloatingText{
+		x:        e.x + w/2,
+		y:        e.y,
+		text:     fmt.Sprint(damage),
+		value:    damage,
+		targetID: e.id,
+		timer:    floatingTextFrames,
+		color:    c,
+	})
+}
+
+// updateFloatingTexts は浮かぶ文字を上へ動かし、表示時間の切れたものを取り除きます
+func (g *Game) updateFloatingTexts() {
+	alive := g.floatingTexts[:0]
+	for _, t := range g.floatingTexts {
+		t.y -= 0.6
+		t.timer--
+		if t.timer > 0 {
+			alive = append(alive, t)
+		}
+	}
+	g.floatingTexts = alive
+}
+
+// drawFloatingTexts は浮かぶ文字を描画します。消える間際は薄くします
+func (g *Game) drawFloatingTexts(field *ebiten.Image) {
+	for _, t := range g.floatingTexts {
+		c := t.color
+		if t.timer < 10 {
+			c.A = uint8(int(c.A) * t.timer / 10)
+		}
+		hud.DrawTextShadow(field, t.text, fonts.Face(fonts.Small), int(t.x), int(t.y), hud.AlignCenter, c)
+	}
+}
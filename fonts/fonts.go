@@ -0,0 +1,93 @@
+package fonts
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/opentype"
+)
+
+// Size は文字の大きさの段階です
+type Size int
+
+const (
+	Small  Size = iota // 操作説明などの補足
+	Medium             // HUDやメニューの本文
+	Large              // タイトルや警告などの見出し
+)
+
+// points は各段階のポイント数です
+var points = map[Size]float64{
+	Small:  14,
+	Medium: 20,
+	Large:  32,
+}
+
+var faces = map[Size]font.Face{}
+
+// parsed は読み込んだTTFです。組み込みのフォントを使っているときはnil
+var parsed *opentype.Font
+
+// scale はすべての大きさにかける倍率です
+var scale = 1.0
+
+// Load はTTFファイルを読み込み、すべての大きさのフォントを作成します
+func Load(path string) error {
+	fontBytes, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("フォントファイルの読み込みに失敗: %v", err)
+	}
+	ttf, err := opentype.Parse(fontBytes)
+	if err != nil {
+		return fmt.Errorf("フォントのパースに失敗: %v", err)
+	}
+	if err := buildFaces(ttf, scale); err != nil {
+		return err
+	}
+	parsed = ttf
+	return nil
+}
+
+// SetScale はすべての大きさのフォントをs倍で作り直します。
+// 組み込みのフォントは拡大できないので、そのときは等倍のままです
+func SetScale(s float64) error {
+	if parsed != nil {
+		if err := buildFaces(parsed, s); err != nil {
+			return err
+		}
+	}
+	scale = s
+	return nil
+}
+
+// buildFaces はTTFからすべての大きさのフォントをs倍で作成します
+func buildFaces(ttf *opentype.Font, s float64) error {
+	for size, pt := range points {
+		face, err := opentype.NewFace(ttf, &opentype.FaceOptions{
+			Size:    pt * s,
+			DPI:     72,
+			Hinting: font.HintingFull,
+		})
+		if err != nil {
+			return fmt.Errorf("フォントの作成に失敗: %v", err)
+		}
+		faces[size] = face
+	}
+	return nil
+}
+
+// LoadFallback はTTFファイルが読み込めないときの代わりに、
+// 組み込みのビットマップフォント（英数字のみ）をすべての大きさに使います
+func LoadFallback() {
+	parsed = nil
+	for size := range points {
+		faces[size] = basicfont.Face7x13
+	}
+}
+
+// Face は指定した大きさのフォントを返します。Loadより前に呼んではいけません
+func Face(size Size) font.Face {
+	return faces[size]
+}
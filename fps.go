@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// fpsCapの値として受け付ける範囲（0は制限なし）
+const (
+	minFPSCap = 30
+	maxFPSCap = 360
+)
+
+// applyFrameSettings は垂直同期とフレームレートの上限の設定をEbitenに反映します。
+// 上限を決めたときはEbitenのTPSをその値にし、前回の描画の後にUpdateがなければ描画を省きます。
+// ゲームの進み方はlogicTPSで決まるので、TPSを変えてもゲームの速さは変わりません
+func applyFrameSettings() {
+	ebiten.SetVsyncEnabled(settings.VSync)
+	if settings.FPSCap > 0 {
+		ebiten.SetTPS(settings.FPSCap)
+		ebiten.SetScreenClearedEveryFrame(false)
+	}
+}
+
+// skipFrame はフレームレートの上限があり、前回の描画の後にゲームが進んでいなければtrueを返します。
+// 描画しないときは前の画面がそのまま残ります
+func (g *Game) skipFrame(screen *ebiten.Image) bool {
+	if settings.FPSCap <= 0 {
+		return false
+	}
+	if !g.updatedSinceDraw {
+		return true
+	}
+	g.updatedSinceDraw = false
+	screen.Clear()
+	return false
+}
+
+// drawFPS は設定で有効にしたときに画面右下へフレームレートを表示します
+func drawFPS(screen *ebiten.Image) {
+	if !settings.ShowFPS {
+		return
+	}
+	text := fmt.Sprintf("FPS: %.1f", ebiten.ActualFPS())
+	hud.DrawTextOutline(screen, text, fonts.Face(fonts.Small), resolution.Width-ui(8), resolution.Height-ui(8), hud.AlignRight, color.RGBA{0, 255, 0, 255}, hud.OutlineColor)
+}
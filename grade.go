@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+	"SimpleShootingStar/i18n"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// StageGrade はステージクリア時の評価の基準です。
+// そのステージで稼いだスコアがs・a・b以上ならS・A・B、届かなければCになります。
+// Sは目標タイム以内にクリアしたときだけで、目標タイムを過ぎるとAまでになります
+type StageGrade struct {
+	ParTime int `json:"parTime"` // 目標タイム（ステージ開始のバナーが消えてからのフレーム数）
+	S       int `json:"s"`       // Sに必要なスコア
+	A       int `json:"a"`       // Aに必要なスコア
+	B       int `json:"b"`       // Bに必要なスコア
+}
+
+// gradeOrder は評価の良い順です
+var gradeOrder = []string{"S", "A", "B", "C"}
+
+// gradeColors は評価ごとの文字の色です
+var gradeColors = map[string]color.RGBA{
+	"S": {255, 215, 0, 255},
+	"A": {255, 120, 120, 255},
+	"B": {120, 200, 255, 255},
+	"C": {200, 200, 200, 255},
+}
+
+// validateGrade は評価の基準を確認します
+func validateGrade(gr *StageGrade) error {
+	if gr == nil {
+		return nil
+	}
+	if gr.ParTime <= 0 {
+		return fmt.Errorf("gradeのparTimeは1以上にしてください")
+	}
+	if gr.B < 0 || gr.A < gr.B || gr.S < gr.A {
+		return fmt.Errorf("gradeのスコアはs ≧ a ≧ b ≧ 0にしてください")
+	}
+	return nil
+}
+
+// judge はステージで稼いだスコアとクリアタイムから評価を返します
+func (gr *StageGrade) judge(score, frames int) string {
+	switch {
+	case score >= gr.S && frames <= gr.ParTime:
+		return "S"
+	case score >= gr.A:
+		return "A"
+	case score >= gr.B:
+		return "B"
+	}
+	return "C"
+}
+
+// betterGrade は評価aがbより良いかを返します
+func betterGrade(a, b string) bool {
+	for _, g := range gradeOrder {
+		if g == b {
+			return false
+		}
+		if g == a {
+			return true
+		}
+	}
+	return false
+}
+
+// updateStageTimer はステージ開始のバナーが消えてからのフレーム数を進めます
+func (g *Game) updateStageTimer() {
+	if g.stageIntroTimer <= 0 {
+		g.stageFrames++
+	}
+}
+
+// onStageGraded はステージクリア時に評価を決め、ステージの最高評価なら記録します。
+// 評価の基準がないステージや、ボス練習・キャラバン・デイリーでは評価しません
+func (g *Game) onStageGraded() {
+	g.stageGrade = ""
+	gr := g.stage().Grade
+	if gr == nil || g.practice != nil || g.caravan != nil || g.daily != nil {
+		return
+	}
+	g.stageGrade = gr.judge(g.score-g.stageStartScore, g.stageFrames)
+	r := stageRecords()
+	if best, ok := r.BestGrades[g.currentStage]; !ok || betterGrade(g.stageGrade, best) {
+		if r.BestGrades == nil {
+			r.BestGrades = map[int]string{}
+		}
+		r.BestGrades[g.currentStage] = g.stageGrade
+		saveStats()
+	}
+}
+
+// drawStageGrade はステージクリア画面に評価と、稼いだスコア・クリアタイムを描画します
+func (g *Game) drawStageGrade(screen *ebiten.Image) {
+	if g.stageGrade == "" {
+		return
+	}
+	gr := g.stage().Grade
+	hud.DrawTextOutline(screen, i18n.Tf("grade.rank", g.stageGrade), fonts.Face(fonts.Large), resolution.Width/2, resolution.Height/2-ui(90), hud.AlignCenter, gradeColors[g.stageGrade], hud.OutlineColor)
+	detail := i18n.Tf("grade.detail", g.score-g.stageStartScore, formatMillis(g.stageFrames), formatMillis(gr.ParTime))
+	hud.DrawTextShadow(screen, detail, fonts.Face(fonts.Small), resolution.Width/2, resolution.Height/2-ui(62), hud.AlignCenter, color.White)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	bulletTrailLength = 4    // 敵弾の軌跡に残す過去の位置の数
+	bulletTrailAlpha  = 0.35 // 一番新しい軌跡の不透明度（古いほど薄くなる）
+	grazeRadius       = 24.0 // 自機の当たり判定の中心からこの距離以内をかすめた弾で風切り音を鳴らす
+	grazeCooldown     = 6    // 風切り音を鳴らしてから次に鳴らせるまでのフレーム数
+)
+
+// BulletTrail は敵弾の直前の位置の履歴です。新しい位置ほど前に入ります
+type BulletTrail struct {
+	points [bulletTrailLength]Position
+	count  int
+}
+
+// push は移動する前の位置を履歴に加えます。古い位置からあふれて消えます
+func (t *BulletTrail) push(x, y float64) {
+	copy(t.points[1:], t.points[:bulletTrailLength-1])
+	t.points[0] = Position{x: x, y: y}
+	if t.count < bulletTrailLength {
+		t.count++
+	}
+}
+
+// checkGraze は当たらずに自機の近くをかすめた敵弾で風切り音を鳴らします。
+// 同じ弾では一度だけ、密集した弾幕でも鳴りすぎないよう間隔を空けて鳴らします
+func (g *Game) checkGraze(eb *EnemyBullet, hx, hy, hw, hh float64) {
+	if eb.grazed || g.invincibleTimer > 0 {
+		return
+	}
+	if math.Hypot(eb.x+3-(hx+hw/2), eb.y+6-(hy+hh/2)) > grazeRadius {
+		return
+	}
+	eb.grazed = true
+	if g.grazeCooldown > 0 {
+		return
+	}
+	g.grazeCooldown = grazeCooldown
+	g.sound.PlayAt("graze", eb.x, playArea.width)
+}
+
+// drawBulletTrail は敵弾の過去の位置に、弾の色の小さな点を古いほど薄く描きます
+func drawBulletTrail(field *ebiten.Image, eb EnemyBullet, c color.RGBA) {
+	for i := 0; i < eb.trail.count; i++ {
+		p := eb.trail.points[i]
+		a := bulletTrailAlpha * float64(bulletTrailLength-i) / bulletTrailLength
+		tc := color.RGBA{uint8(float64(c.R) * a), uint8(float64(c.G) * a), uint8(float64(c.B) * a), uint8(255 * a)}
+		size := 4.0 - float64(i)*0.5
+		ebitenutil.DrawRect(field, p.x+3-size/2, p.y+6-size/2, size, size, tc)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	mineSize        = 10  // 機雷の大きさ
+	mineFuse        = 180 // 機雷が爆発するまでのフレーム数
+	mineHP          = 2   // 機雷の耐久度
+	mineDriftSpeed  = 0.4 // 機雷がゆっくり流れる速さ
+	mineDropTime    = 90  // 機雷を投下する間隔
+	mineRingBullets = 8   // 爆発時にばらまく弾の数
+	mineRingSpeed   = 2.5 // 爆発時の弾速
+	mineScore       = 20  // 機雷を撃ち落としたときのスコア
+	mineBlinkFuse   = 60  // 残りフューズがこれ以下になると速く点滅する
+)
+
+// Mine は敵が設置する機雷（その場に残る障害物）を表す構造体
+type Mine struct {
+	id     int
+	x, y   float64
+	vx, vy float64
+	fuse   int // 爆発までの残りフレーム数
+	hp     int
+}
+
+// dropMine は指定位置に機雷を設置します
+func (g *Game) dropMine(x, y float64) {
+	g.mines = append(g.mines, Mine{
+		id:   g.newEntityID(),
+		x:    x,
+		y:    y,
+		vx:   0,
+		vy:   mineDriftSpeed,
+		fuse: mineFuse,
+		hp:   mineHP,
+	})
+}
+
+// explodeMine は機雷を爆発させ、リング状に弾をばらまきます
+func (g *Game) explodeMine(m Mine) {
+	cx, cy := m.x+mineSize/2, m.y+mineSize/2
+	for i := 0; i < mineRingBullets; i++ {
+		angle := math.Pi * 2 * float64(i) / mineRingBullets
+		g.enemyBullets = append(g.enemyBullets, EnemyBullet{
+			x: cx, y: cy, vx: math.Cos(angle) * mineRingSpeed, vy: math.Sin(angle) * mineRingSpeed,
+		})
+	}
+	g.createExplosion(cx, cy, color.RGBA{0, 200, 255, 255}, ExplosionSmall)
+	g.sound.PlayAt("explosion", cx, playArea.width)
+}
+
+// hitMine は自機弾が機雷に当たったかを判定し、当たった機雷の耐久度を減らします。
+// 耐久度が0になった機雷はフューズを0にして、次の更新で爆発させます
+func (g *Game) hitMine(b Bullet) bool {
+	for i := range g.mines {
+		m := &g.mines[i]
+		if m.fuse > 0 && overlaps(b.x, b.y, 4, 8, m.x, m.y, mineSize, mineSize) {
+			m.hp -= b.damage
+			if m.hp <= 0 {
+				m.fuse = 0
+				g.score += mineScore
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// updateMines は機雷の移動・爆発・自機との当たり判定を行います
+func (g *Game) updateMines() {
+	hx, hy, hw, hh := g.playerHitbox()
+	newMines := g.mines[:0]
+	for _, m := range g.mines {
+		m.x += m.vx
+		m.y += m.vy
+		m.fuse--
+		if m.fuse <= 0 {
+			g.explodeMine(m)
+			continue
+		}
+
+		// プレイヤーとの当たり判定
+		if g.gameState == GameStatePlaying && overlaps(hx, hy, hw, hh, m.x, m.y, mineSize, mineSize) {
+			g.killPlayer()
+		}
+
+		if m.y < playArea.height+mineSize {
+			newMines = append(newMines, m)
+		}
+	}
+	g.mines = newMines
+}
+
+// drawMines は機雷を描画します。爆発が近づくほど速く点滅します
+func (g *Game) drawMines(screen *ebiten.Image) {
+	for _, m := range g.mines {
+		blink := 20
+		if m.fuse <= mineBlinkFuse {
+			blink = 6
+		}
+		c := color.RGBA{0, 200, 255, 255}
+		if m.fuse%blink < blink/2 {
+			c = color.RGBA{255, 255, 255, 255}
+		}
+		ebitenutil.DrawRect(screen, m.x, m.y, mineSize, mineSize, c)
+		ebitenutil.DrawRect(screen, m.x+mineSize/2-1, m.y-3, 2, mineSize+6, c)
+		ebitenutil.DrawRect(screen, m.x-3, m.y+mineSize/2-1, mineSize+6, 2, c)
+	}
+}
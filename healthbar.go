@@ -0,0 +1,85 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	hpBarFrames   = 180 // 最後に被弾してからHPバーを表示するフレーム数
+	hpBarFade     = 30  // 表示の終わりにHPバーが薄くなっていくフレーム数
+	hpBarHeight   = 4   // HPバーの高さ（枠を除く）
+	hpBarSegments = 10  // HPバーの区切りの最大数
+)
+
+// drawEnemyHPBar は敵の頭上に区切り付きのHPバーを描画します。
+// 一度も被弾していない敵や、しばらく被弾していない敵には表示しません。
+// ボスに取り付けられた砲台は常に小さなバーを表示します
+func drawEnemyHPBar(field *ebiten.Image, e Enemy) {
+	if e.mount != nil {
+		drawPartHPBar(field, e)
+		return
+	}
+	if e.hpBarTimer <= 0 || e.maxHP <= 0 {
+		return
+	}
+	alpha := 1.0
+	if e.hpBarTimer < hpBarFade {
+		alpha = float64(e.hpBarTimer) / hpBarFade
+	}
+	fade := func(c color.RGBA) color.RGBA {
+		c.A = uint8(float64(c.A) * alpha)
+		return c
+	}
+
+	width, _ := enemySize(e.enemyType)
+	x, y := e.x, e.y-9
+	border := color.RGBA{200, 200, 200, 200}
+	if e.armor > 0 {
+		// 装甲のある敵は枠の色を変えて、効きにくいことを示す
+		border = color.RGBA{120, 180, 255, 255}
+	}
+	ebitenutil.DrawRect(field, x-1, y-1, width+2, hpBarHeight+2, fade(border))
+	ebitenutil.DrawRect(field, x, y, width, hpBarHeight, fade(color.RGBA{30, 30, 30, 220}))
+
+	// 残りの割合に応じて緑から黄色を経て赤へ変える
+	rate := float64(e.hp) / float64(e.maxHP)
+	if rate < 0 {
+		rate = 0
+	}
+	fill := color.RGBA{255, 255, 0, 255}
+	if rate > 0.5 {
+		fill.R = uint8(255 * (1 - rate) * 2)
+	} else {
+		fill.G = uint8(255 * rate * 2)
+	}
+	ebitenutil.DrawRect(field, x, y, width*rate, hpBarHeight, fade(fill))
+
+	// 区切りの線
+	segments := e.maxHP
+	if segments > hpBarSegments {
+		segments = hpBarSegments
+	}
+	for i := 1; i < segments; i++ {
+		sx := x + width*float64(i)/float64(segments)
+		ebitenutil.DrawRect(field, sx, y, 1, hpBarHeight, fade(color.RGBA{0, 0, 0, 255}))
+	}
+}
+
+// drawPartHPBar は砲台の下に細いHPバーを描画します。被弾した直後は白く光らせます
+func drawPartHPBar(field *ebiten.Image, e Enemy) {
+	if e.maxHP <= 0 {
+		return
+	}
+	width, height := enemySize(e.enemyType)
+	x, y := e.x, e.y+height+2
+	rate := max(float64(e.hp)/float64(e.maxHP), 0)
+	fill := color.RGBA{255, 160, 40, 255}
+	if e.flashTimer > 0 {
+		fill = color.RGBA{255, 255, 255, 255}
+	}
+	ebitenutil.DrawRect(field, x, y, width, 2, color.RGBA{30, 30, 30, 200})
+	ebitenutil.DrawRect(field, x, y, width*rate, 2, fill)
+}
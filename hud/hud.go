@@ -0,0 +1,127 @@
+package hud
+
+import (
+	"image/color"
+
+	"SimpleShootingStar/i18n"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"golang.org/x/image/font"
+)
+
+// Element は文字で表示するHUD要素の配置です
+type Element struct {
+	X      int   `json:"x"`      // 基準位置（画面左上からのピクセル）
+	Y      int   `json:"y"`      // ベースラインの位置
+	Align  Align `json:"align"`  // 基準位置に対する揃え方
+	Hidden bool  `json:"hidden"` // 表示しない
+	Short  bool  `json:"short"`  // 短い表記にする（ステージ名の代わりに番号など）
+}
+
+// Bar はゲージで表示するHUD要素の配置です
+type Bar struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	Hidden bool    `json:"hidden"`
+}
+
+// Layout はHUD全体の配置です
+type Layout struct {
+	Score           Element `json:"score"`
+	HighScore       Element `json:"highScore"`
+	Stage           Element `json:"stage"`
+	Lives           Element `json:"lives"`
+	Bombs           Element `json:"bombs"`
+	Combo           Element `json:"combo"`
+	Multiplier      Element `json:"multiplier"`
+	MultiplierGauge Bar     `json:"multiplierGauge"`
+	BossBar         Bar     `json:"bossBar"`
+	Loop            Element `json:"loop"`
+}
+
+// State はHUDに表示するゲームの状態です
+type State struct {
+	Score       int
+	HighScore   int
+	StageNumber int // 1から始まるステージ番号
+	StageName   string
+	Lives       int
+	Bombs       int
+	Combo       int
+	Multiplier  int     // スコア倍率
+	Gauge       float64 // 次の倍率までのゲージ（0〜1）
+	BossHP      int
+	BossMaxHP   int // 0ならボスはいない
+	Loop        int // 1から始まる周回（2周目以降だけ表示する）
+}
+
+var (
+	textColor   = color.RGBA{255, 255, 255, 255}
+	shadowColor = color.RGBA{0, 0, 40, 200}
+	// OutlineColor は見出しの縁取りに使う色です
+	OutlineColor = color.RGBA{0, 0, 40, 255}
+	comboColor   = color.RGBA{255, 220, 80, 255}
+	bossColor    = color.RGBA{220, 40, 40, 255}
+	gaugeColor   = color.RGBA{255, 230, 80, 255}
+	loopColor    = color.RGBA{255, 120, 120, 255}
+)
+
+// HUD はスコアや残機などの表示をまとめて描画します
+type HUD struct {
+	face   font.Face
+	layout Layout
+}
+
+// New は指定したフォントと配置でHUDを作成します
+func New(face font.Face, layout Layout) *HUD {
+	return &HUD{face: face, layout: layout}
+}
+
+// Draw はHUDを描画します
+func (h *HUD) Draw(dst *ebiten.Image, s State) {
+	l := h.layout
+	h.drawElement(dst, l.Score, i18n.Tf("hud.score", s.Score), textColor)
+	h.drawElement(dst, l.HighScore, i18n.Tf("hud.highScore", s.HighScore), textColor)
+	if l.Stage.Short {
+		h.drawElement(dst, l.Stage, i18n.Tf("hud.stageShort", s.StageNumber), textColor)
+	} else {
+		h.drawElement(dst, l.Stage, i18n.Tf("hud.stage", s.StageName), textColor)
+	}
+	h.drawElement(dst, l.Lives, i18n.Tf("hud.lives", s.Lives), textColor)
+	h.drawElement(dst, l.Bombs, i18n.Tf("hud.bombs", s.Bombs), textColor)
+	if s.Combo >= 2 {
+		h.drawElement(dst, l.Combo, i18n.Tf("hud.combo", s.Combo), comboColor)
+	}
+	h.drawElement(dst, l.Multiplier, i18n.Tf("hud.multiplier", s.Multiplier), gaugeColor)
+	h.drawBar(dst, l.MultiplierGauge, s.Gauge, gaugeColor)
+	if s.Loop >= 2 {
+		h.drawElement(dst, l.Loop, i18n.Tf("hud.loop", s.Loop), loopColor)
+	}
+	if s.BossMaxHP > 0 {
+		h.drawBar(dst, l.BossBar, float64(s.BossHP)/float64(s.BossMaxHP), bossColor)
+	}
+}
+
+// drawElement は影付きの文字でHUD要素を描画します
+func (h *HUD) drawElement(dst *ebiten.Image, e Element, s string, clr color.Color) {
+	if e.Hidden {
+		return
+	}
+	DrawTextShadow(dst, s, h.face, e.X, e.Y, e.Align, clr)
+}
+
+// drawBar は枠付きのゲージを描画します。rateは0〜1の残量です
+func (h *HUD) drawBar(dst *ebiten.Image, b Bar, rate float64, clr color.Color) {
+	if b.Hidden {
+		return
+	}
+	if rate < 0 {
+		rate = 0
+	}
+	ebitenutil.DrawRect(dst, b.X-1, b.Y-1, b.Width+2, b.Height+2, color.RGBA{255, 255, 255, 200})
+	ebitenutil.DrawRect(dst, b.X, b.Y, b.Width, b.Height, color.RGBA{40, 0, 0, 255})
+	ebitenutil.DrawRect(dst, b.X, b.Y, b.Width*rate, b.Height, clr)
+}
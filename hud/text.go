@@ -0,0 +1,52 @@
+package hud
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+)
+
+// Align は文字列の横方向の揃え方を表します
+type Align string
+
+const (
+	AlignLeft   Align = "left"   // xが文字列の左端
+	AlignCenter Align = "center" // xが文字列の中央
+	AlignRight  Align = "right"  // xが文字列の右端
+)
+
+// TextWidth は文字列を描画したときの幅をピクセルで返します
+func TextWidth(face font.Face, s string) int {
+	return font.MeasureString(face, s).Ceil()
+}
+
+// DrawText は揃え方に従って文字列を描画します。yはベースラインの位置です
+func DrawText(dst *ebiten.Image, s string, face font.Face, x, y int, align Align, clr color.Color) {
+	switch align {
+	case AlignCenter:
+		x -= TextWidth(face, s) / 2
+	case AlignRight:
+		x -= TextWidth(face, s)
+	}
+	text.Draw(dst, s, face, x, y, clr)
+}
+
+// DrawTextShadow は右下に影を付けて文字列を描画します
+func DrawTextShadow(dst *ebiten.Image, s string, face font.Face, x, y int, align Align, clr color.Color) {
+	DrawText(dst, s, face, x+1, y+1, align, shadowColor)
+	DrawText(dst, s, face, x, y, align, clr)
+}
+
+// DrawTextOutline は周囲を縁取りして文字列を描画します。星空の上でも読みやすくなります
+func DrawTextOutline(dst *ebiten.Image, s string, face font.Face, x, y int, align Align, clr, outline color.Color) {
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if dx != 0 || dy != 0 {
+				DrawText(dst, s, face, x+dx, y+dy, align, outline)
+			}
+		}
+	}
+	DrawText(dst, s, face, x, y, align, clr)
+}
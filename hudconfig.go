@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"SimpleShootingStar/hud"
+)
+
+var gameHUD *hud.HUD
+
+// newHUDLayout はプレイエリアに合わせたHUDの既定の配置を作り、
+// 設定ファイルのhudLayoutで指定された項目だけを上書きします。
+// 行の間隔は文字と同じくuiScaleの倍率で広げます
+func newHUDLayout() (hud.Layout, error) {
+	var layout hud.Layout
+	s := uiScale()
+	if playArea.letterboxed() {
+		// 左右のパネルに縦に並べる
+		left := 8
+		right := resolution.Width - 8
+		row := func(n float64) int { return int(32 * n * s) }
+		layout = hud.Layout{
+			Score:           hud.Element{X: left, Y: row(1), Align: hud.AlignLeft},
+			Stage:           hud.Element{X: left, Y: row(2), Align: hud.AlignLeft, Short: true},
+			Lives:           hud.Element{X: left, Y: row(3), Align: hud.AlignLeft},
+			Bombs:           hud.Element{X: left, Y: row(4), Align: hud.AlignLeft},
+			HighScore:       hud.Element{X: right, Y: row(1), Align: hud.AlignRight},
+			Combo:           hud.Element{X: right, Y: row(2), Align: hud.AlignRight},
+			Multiplier:      hud.Element{X: right, Y: row(3), Align: hud.AlignRight},
+			MultiplierGauge: hud.Bar{X: float64(right) - 100, Y: float64(row(3)) + 8, Width: 100, Height: 4},
+			BossBar:         hud.Bar{X: playArea.x + 10, Y: 8, Width: playArea.width - 20, Height: 6},
+			Loop:            hud.Element{X: left, Y: row(5), Align: hud.AlignLeft},
+		}
+	} else {
+		// 左上にスコア・ステージ・残機・ボム、右上にハイスコア・コンボ・スコア倍率、上部中央にボスの体力
+		row := func(n float64) int { return int(20 * n * s) }
+		layout = hud.Layout{
+			Score:           hud.Element{X: 0, Y: row(1.2), Align: hud.AlignLeft},
+			Stage:           hud.Element{X: 0, Y: row(2.0), Align: hud.AlignLeft},
+			Lives:           hud.Element{X: 0, Y: row(2.8), Align: hud.AlignLeft},
+			Bombs:           hud.Element{X: 0, Y: row(3.6), Align: hud.AlignLeft},
+			HighScore:       hud.Element{X: resolution.Width - 4, Y: row(1.2), Align: hud.AlignRight},
+			Combo:           hud.Element{X: resolution.Width - 4, Y: row(2.0), Align: hud.AlignRight},
+			Multiplier:      hud.Element{X: resolution.Width - 4, Y: row(2.8), Align: hud.AlignRight},
+			MultiplierGauge: hud.Bar{X: float64(resolution.Width) - 104, Y: float64(row(2.8)) + 6, Width: 100, Height: 4},
+			BossBar:         hud.Bar{X: float64(resolution.Width)/2 - 120, Y: 6, Width: 240, Height: 6},
+			Loop:            hud.Element{X: 0, Y: row(4.4), Align: hud.AlignLeft},
+		}
+	}
+
+	if len(settings.HUDLayout) > 0 {
+		if err := json.Unmarshal(settings.HUDLayout, &layout); err != nil {
+			return layout, fmt.Errorf("hudLayoutのパースに失敗: %v", err)
+		}
+	}
+	return layout, nil
+}
+
+// hudState はHUDに表示するためのゲームの状態をまとめます
+func (g *Game) hudState() hud.State {
+	s := hud.State{
+		Score:       g.scoreRoll.value(),
+		HighScore:   g.highScore,
+		StageNumber: g.currentStage + 1,
+		StageName:   g.stage().Name,
+		Lives:       g.lives,
+		Bombs:       g.bombs,
+		Combo:       g.combo,
+		Multiplier:  g.multiplier,
+		Gauge:       g.multiplierGauge(),
+		Loop:        g.loop + 1,
+	}
+	for _, e := range g.enemies {
+		if e.enemyType == EnemyTypeBoss {
+			s.BossHP = e.hp
+			s.BossMaxHP = e.maxHP
+			break
+		}
+	}
+	return s
+}
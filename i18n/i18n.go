@@ -0,0 +1,68 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultLanguage は訳がないときに使う言語です
+const DefaultLanguage = "en"
+
+var (
+	current  map[string]string // 選択中の言語の文字列テーブル
+	fallback map[string]string // 既定の言語の文字列テーブル
+)
+
+// Load はdir以下の<lang>.jsonから文字列テーブルを読み込みます。
+// 選択した言語にない文字列は既定の言語(en)のものを使います
+func Load(dir, lang string) error {
+	var err error
+	fallback, err = loadTable(dir, DefaultLanguage)
+	if err != nil {
+		return err
+	}
+	if lang == DefaultLanguage {
+		current = fallback
+		return nil
+	}
+	current, err = loadTable(dir, lang)
+	return err
+}
+
+// loadTable は1言語分の文字列テーブルを読み込みます
+func loadTable(dir, lang string) (map[string]string, error) {
+	file, err := os.ReadFile(filepath.Join(dir, lang+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("言語ファイル(%s)の読み込みに失敗: %v", lang, err)
+	}
+
+	var table map[string]string
+	if err := json.Unmarshal(file, &table); err != nil {
+		return nil, fmt.Errorf("言語ファイル(%s)のパースに失敗: %v", lang, err)
+	}
+	return table, nil
+}
+
+// Lookup はキーに対応する文字列を探します。どの言語にもなければfalseを返します
+func Lookup(key string) (string, bool) {
+	if s, ok := current[key]; ok {
+		return s, true
+	}
+	s, ok := fallback[key]
+	return s, ok
+}
+
+// T はキーに対応する文字列を返します。見つからなければキーをそのまま返します
+func T(key string) string {
+	if s, ok := Lookup(key); ok {
+		return s
+	}
+	return key
+}
+
+// Tf はキーに対応する書式文字列にargsを埋め込んで返します
+func Tf(key string, args ...interface{}) string {
+	return fmt.Sprintf(T(key), args...)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Input はゲームが参照するキー入力です。
+// 通常はキーボードを読み、シミュレーションでは自動操縦に差し替えます
+type Input interface {
+	Pressed(key ebiten.Key) bool     // 押されているか
+	JustPressed(key ebiten.Key) bool // このステップで押されたか
+}
+
+// bufferedInput は押した瞬間をためておく入力です。
+// 1回のUpdateで進めるステップ数は0回のことも複数回のこともあるため、
+// 押した瞬間を取りこぼしたり二重に数えたりしないよう、Updateの最初にpollで取り込み、
+// ステップを1回処理するごとにconsumeで消費します。pollはそのUpdateで新しく押されたキーを返します
+type bufferedInput interface {
+	Input
+	poll() []ebiten.Key
+	consume()
+}
+
+// keyboardInput は実際のキーボードを読む入力です
+type keyboardInput struct {
+	pending map[ebiten.Key]bool // まだステップで処理していない押した瞬間
+}
+
+func newKeyboardInput() *keyboardInput {
+	return &keyboardInput{pending: map[ebiten.Key]bool{}}
+}
+
+func (in *keyboardInput) Pressed(key ebiten.Key) bool {
+	return ebiten.IsKeyPressed(key)
+}
+
+func (in *keyboardInput) JustPressed(key ebiten.Key) bool {
+	return in.pending[key]
+}
+
+func (in *keyboardInput) poll() []ebiten.Key {
+	keys := inpututil.AppendJustPressedKeys(nil)
+	for _, key := range keys {
+		in.pending[key] = true
+	}
+	return keys
+}
+
+func (in *keyboardInput) consume() {
+	for key := range in.pending {
+		delete(in.pending, key)
+	}
+}
+
+// keyJustPressed はステップではなくUpdateごとに読む操作（音量・コンソール・デバッグ）のキーが、
+// このUpdateで押されたかを返します。ためておいた押した瞬間は見ないので、ステップが進まない間も二重に効きません
+func (g *Game) keyJustPressed(key ebiten.Key) bool {
+	if _, ok := g.input.(bufferedInput); !ok {
+		return g.input.JustPressed(key)
+	}
+	for _, k := range g.justPolled {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
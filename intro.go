@@ -0,0 +1,66 @@
+package main
+
+import (
+	"image/color"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+	"SimpleShootingStar/i18n"
+	"SimpleShootingStar/tween"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	stageIntroFrames = 120 // ステージ開始時のバナーの表示フレーム数
+	stageIntroSlide  = 20  // バナーが滑り込む・抜けていくのにかかるフレーム数
+)
+
+// startStageIntro はステージ開始のバナー表示を始めます。
+// 表示中は敵が出現しないよう、ウェーブのタイマーをバナーの長さだけ遅らせます
+func (g *Game) startStageIntro() {
+	g.stageIntroTimer = stageIntroFrames
+	g.waveTimer = -stageIntroFrames
+	g.introSlideIn = tween.New(-1, 0, stageIntroSlide, tween.OutCubic)
+	g.introSlideOut = tween.New(0, 1, stageIntroSlide, tween.InCubic)
+	g.introSlideOut.Delay = stageIntroFrames - stageIntroSlide
+}
+
+// updateStageIntro はバナーの表示時間を進めます
+func (g *Game) updateStageIntro() {
+	if g.stageIntroTimer > 0 {
+		g.stageIntroTimer--
+		g.introSlideIn.Update()
+		g.introSlideOut.Update()
+	}
+}
+
+// drawStageIntro はステージ番号・ステージ名・目標を帯に載せて描画します。
+// 帯は左から滑り込み、最後は右へ抜けていきます
+func (g *Game) drawStageIntro(field *ebiten.Image) {
+	if g.stageIntroTimer <= 0 {
+		return
+	}
+	offset := playArea.width * (g.introSlideIn.Value() + g.introSlideOut.Value())
+
+	stage := g.stage()
+	title := i18n.Tf("stageIntro.title", g.currentStage+1)
+	if g.caravan != nil {
+		title = i18n.T("caravan.title")
+	}
+	if g.daily != nil {
+		title = i18n.T("daily.title")
+	}
+	if g.tutorial != nil {
+		title = i18n.T("tutorial.title")
+	}
+	cx := int(playArea.width/2 + offset)
+	cy := int(playArea.height / 3)
+	ebitenutil.DrawRect(field, offset, float64(cy-ui(44)), playArea.width, float64(ui(88)), color.RGBA{0, 0, 80, 160})
+	hud.DrawTextOutline(field, title, fonts.Face(fonts.Large), cx, cy-ui(8), hud.AlignCenter, color.White, hud.OutlineColor)
+	hud.DrawTextShadow(field, stage.Name, fonts.Face(fonts.Medium), cx, cy+ui(18), hud.AlignCenter, color.White)
+	if stage.Objective != "" {
+		hud.DrawTextShadow(field, stage.Objective, fonts.Face(fonts.Small), cx, cy+ui(38), hud.AlignCenter, color.RGBA{255, 220, 80, 255})
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// BehaviorKamikaze はstages.jsonのbehaviorに書く、自機めがけて突っ込む行動の名前です
+const BehaviorKamikaze = "kamikaze"
+
+// stages.jsonで省略されたときの特攻の設定
+const (
+	defaultKamikazeTrigger  = 180.0 // 自機との距離がこれ以下になると狙いを定める
+	defaultKamikazeWarning  = 30    // 狙いを定めてから突っ込むまでの予告のフレーム数
+	defaultKamikazeAccel    = 0.35  // 突っ込むときの加速度（1フレームあたり）
+	defaultKamikazeMaxSpeed = 10.0  // 突っ込むときの最高速度
+)
+
+// 特攻の状態
+const (
+	kamikazeApproach = iota // ふだんの動きで近づいている
+	kamikazeWarning         // 狙いを定めて点滅している
+	kamikazeDash            // 狙った位置へ突っ込んでいる
+)
+
+// KamikazeDef はstages.jsonのkamikazeに書く特攻の設定です。0の項目は既定値になります
+type KamikazeDef struct {
+	TriggerDistance float64 `json:"triggerDistance"` // 狙いを定める自機との距離
+	WarningFrames   int     `json:"warningFrames"`   // 突っ込むまでの予告のフレーム数
+	Accel           float64 `json:"accel"`           // 突っ込むときの加速度
+	MaxSpeed        float64 `json:"maxSpeed"`        // 突っ込むときの最高速度
+}
+
+// Kamikaze は自機に近づくと位置を狙い定めて突っ込む敵のコンポーネントです
+type Kamikaze struct {
+	KamikazeDef
+	state            int
+	timer            int     // 今の状態になってからのフレーム数
+	targetX, targetY float64 // 狙いを定めたときの自機の中心
+	dirX, dirY       float64 // 突っ込む向き（単位ベクトル）
+	speed            float64 // 突っ込む今の速さ
+}
+
+// validateBehavior はウェーブのbehaviorが知っている名前かを確かめます
+func validateBehavior(w Wave) error {
+	switch w.Behavior {
+	case "", BehaviorKamikaze:
+		return nil
+	}
+	return fmt.Errorf("behaviorの値が不正です: %q", w.Behavior)
+}
+
+// newKamikaze は省略された設定を既定値で埋めた特攻のコンポーネントを作ります
+func newKamikaze(def KamikazeDef) *Kamikaze {
+	if def.TriggerDistance == 0 {
+		def.TriggerDistance = defaultKamikazeTrigger
+	}
+	if def.WarningFrames == 0 {
+		def.WarningFrames = defaultKamikazeWarning
+	}
+	if def.Accel == 0 {
+		def.Accel = defaultKamikazeAccel
+	}
+	if def.MaxSpeed == 0 {
+		def.MaxSpeed = defaultKamikazeMaxSpeed
+	}
+	return &Kamikaze{KamikazeDef: def}
+}
+
+// updateKamikaze は特攻の状態を進めます。狙いを定めた後はふだんの動きの代わりにこちらで動かすため、
+// moveEnemyを呼ぶ必要があるかを返します
+func (g *Game) updateKamikaze(e *Enemy) (move bool) {
+	k := e.kamikaze
+	k.timer++
+	w, h := enemySize(e.enemyType)
+	cx, cy := e.x+w/2, e.y+h/2
+	switch k.state {
+	case kamikazeApproach:
+		px, py := g.playerX+10, g.playerY+12
+		if g.gameState != GameStatePlaying || e.y < 0 || math.Hypot(px-cx, py-cy) > k.TriggerDistance {
+			return true
+		}
+		// その時点の自機の位置を狙い、以後は自機が動いても向きを変えない
+		k.state, k.timer = kamikazeWarning, 0
+		k.targetX, k.targetY = px, py
+		d := math.Hypot(px-cx, py-cy)
+		if d == 0 {
+			k.dirX, k.dirY = 0, 1
+		} else {
+			k.dirX, k.dirY = (px-cx)/d, (py-cy)/d
+		}
+		g.sound.PlayAt("warning", cx, playArea.width)
+	case kamikazeWarning:
+		if k.timer >= k.WarningFrames {
+			k.state, k.timer = kamikazeDash, 0
+		}
+	case kamikazeDash:
+		k.speed = math.Min(k.MaxSpeed, k.speed+k.Accel)
+		e.x += k.dirX * k.speed
+		e.y += k.dirY * k.speed
+	}
+	return false
+}
+
+// leftField は突っ込んでいる敵がプレイエリアの左右か上から出たかを返します。
+// 下から出た敵は他の敵と同じく画面外へ逃げたものとして扱います
+func (e *Enemy) leftField() bool {
+	if e.kamikaze == nil || e.kamikaze.state != kamikazeDash {
+		return false
+	}
+	w, h := enemySize(e.enemyType)
+	return e.x+w < -20 || e.x > playArea.width+20 || e.y+h < -20
+}
+
+// kamikazeFlash は予告中の敵を点滅させる色を返します。点滅していなければfalseを返します
+func (e *Enemy) kamikazeFlash() (color.RGBA, bool) {
+	k := e.kamikaze
+	if k == nil || k.state != kamikazeWarning || k.timer%6 >= 3 {
+		return color.RGBA{}, false
+	}
+	return color.RGBA{255, 40, 40, 255}, true
+}
+
+// drawKamikazeTarget は予告中の敵から狙った位置への線と、狙った位置の印を描画します
+func drawKamikazeTarget(field *ebiten.Image, e Enemy) {
+	k := e.kamikaze
+	if k == nil || k.state != kamikazeWarning {
+		return
+	}
+	w, h := enemySize(e.enemyType)
+	alpha := uint8(80 + 100*k.timer/k.WarningFrames)
+	clr := color.RGBA{255, 60, 60, alpha}
+	ebitenutil.DrawLine(field, e.x+w/2, e.y+h/2, k.targetX, k.targetY, clr)
+	ebitenutil.DrawLine(field, k.targetX-8, k.targetY, k.targetX+8, k.targetY, clr)
+	ebitenutil.DrawLine(field, k.targetX, k.targetY-8, k.targetX, k.targetY+8, clr)
+}
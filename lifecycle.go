@@ -0,0 +1,163 @@
+package main
+
+import (
+	"image/color"
+
+	"SimpleShootingStar/tween"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	spawnAnimFrames = 15  // 出現した敵が大きくなりながら現れるフレーム数
+	deathAnimFrames = 14  // 倒した敵が膨らみながら消えるフレーム数
+	deathAnimScale  = 1.6 // 倒した敵が消えるときの最大の大きさの倍率
+	animMargin      = 32  // 拡大縮小して描くときに敵の周りに取る余白（盾などがはみ出す分）
+)
+
+// animCanvas は出現中・消滅中の敵をいったん描いてから拡大縮小するための画像です
+var animCanvas *ebiten.Image
+
+// Lifecycle は敵の出現から撃破までの状態のコンポーネントです。すべての敵が持ちます
+type Lifecycle struct {
+	appear tween.Tween // 出現時の大きさ（0から1へ）
+	dead   bool        // 倒されて、そのフレームの掃除で取り除かれるのを待っているか
+}
+
+// KilledEnemy は倒されて、掃除で取り除かれるのを待っている敵です
+type KilledEnemy struct {
+	Enemy
+	revenge bool // 自機の弾で倒したか（撃ち返し弾を出す）
+}
+
+// DyingEnemy は倒されて消えていく途中の敵です。当たり判定はありません
+type DyingEnemy struct {
+	Enemy
+	vanish tween.Tween // 消えていく進み具合（0から1へ）
+}
+
+// newLifecycle は出現の演出を始めた状態のコンポーネントを作ります
+func newLifecycle() Lifecycle {
+	return Lifecycle{appear: tween.New(0, 1, spawnAnimFrames, tween.OutBack)}
+}
+
+// markDead は敵に倒された印を付けます。g.enemiesからはすぐに取り除かず、
+// 当たり判定を終えたあとのsweepDeadでまとめて取り除きます。
+// そのため、敵を回すループの中で倒しても残りの敵を飛ばしたり、同じ敵を二度倒したりしません。
+// すでに倒された敵には何もしません
+func (g *Game) markDead(e *Enemy, revenge bool) {
+	if e.dead {
+		return
+	}
+	e.dead = true
+	g.deathQueue = append(g.deathQueue, KilledEnemy{Enemy: *e, revenge: revenge})
+}
+
+// sweepDead は倒された印の付いた敵をg.enemiesから取り除き、倒した順にスコアや爆発などを処理します
+func (g *Game) sweepDead() {
+	if len(g.deathQueue) == 0 {
+		return
+	}
+	alive := g.enemies[:0]
+	for _, e := range g.enemies {
+		if !e.dead {
+			alive = append(alive, e)
+		}
+	}
+	g.enemies = alive
+
+	// 処理の途中で倒された敵は次の掃除に回す
+	queue := g.deathQueue
+	g.deathQueue = nil
+	for _, k := range queue {
+		g.onEnemyKilled(k.Enemy)
+		if k.revenge {
+			g.fireRevengeBullet(k.Enemy)
+		}
+	}
+}
+
+// startDeathAnim は倒した敵を消えていく途中の敵に加えます
+func (g *Game) startDeathAnim(e Enemy) {
+	g.dyingEnemies = append(g.dyingEnemies, DyingEnemy{Enemy: e, vanish: tween.New(0, 1, deathAnimFrames, tween.OutQuad)})
+}
+
+// updateLifecycle は敵の出現の演出と、消えていく途中の敵を進めます
+func (g *Game) updateLifecycle() {
+	for i := range g.enemies {
+		g.enemies[i].appear.Update()
+	}
+	alive := g.dyingEnemies[:0]
+	for _, d := range g.dyingEnemies {
+		d.vanish.Update()
+		if !d.vanish.Done() {
+			alive = append(alive, d)
+		}
+	}
+	g.dyingEnemies = alive
+}
+
+// drawEnemyAnimated は敵を、中心を基準にscale倍の大きさ・alphaの不透明度で描画します
+func drawEnemyAnimated(field *ebiten.Image, e Enemy, scale, alpha float64) {
+	if animCanvas == nil {
+		animCanvas = ebiten.NewImage(128, 128)
+	}
+	animCanvas.Clear()
+	w, h := enemySize(e.enemyType)
+	local := e
+	local.x, local.y = animMargin, animMargin
+	drawEnemy(animCanvas, local)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(-(animMargin + w/2), -(animMargin + h/2))
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(e.x+w/2, e.y+h/2)
+	op.ColorScale.ScaleAlpha(float32(alpha))
+	field.DrawImage(animCanvas, op)
+}
+
+// drawEnemies は敵を描画します。出現中の敵は大きくなりながら現れ、
+// 倒された敵は白く光って膨らみながら消えていきます
+func (g *Game) drawEnemies(field *ebiten.Image) {
+	for _, e := range g.enemies {
+		if e.dead {
+			continue
+		}
+		if !e.appear.Done() {
+			drawEnemyAnimated(field, e, e.appear.Value(), e.appear.Progress())
+			continue
+		}
+		drawEnemy(field, e)
+		drawKamikazeTarget(field, e)
+		drawEnemyHPBar(field, e)
+	}
+	for _, d := range g.dyingEnemies {
+		v := d.vanish.Value()
+		d.flashTimer = 1 // 消えるときは白く光らせる
+		drawEnemyAnimated(field, d.Enemy, 1+(deathAnimScale-1)*v, 1-v)
+	}
+}
+
+// drawEnemy は敵の本体・露出した弱点・正面の盾を描画します
+func drawEnemy(dst *ebiten.Image, e Enemy) {
+	enemyWidth, enemyHeight := enemySize(e.enemyType)
+	bodyColor := enemyColor(e.enemyType)
+	// ボスの攻撃準備状態で点滅効果
+	if e.boss != nil && e.boss.state == 1 && e.boss.timer%10 < 5 {
+		bodyColor = color.RGBA{255, 255, 255, 255}
+	}
+	if c, ok := e.kamikazeFlash(); ok {
+		bodyColor = c
+	}
+	if e.flashTimer > 0 {
+		bodyColor = color.RGBA{255, 255, 255, 255}
+	}
+
+	ebitenutil.DrawRect(dst, e.x, e.y, enemyWidth, enemyHeight, bodyColor)
+	if e.weakPointExposed && int(e.time*20)%12 < 6 {
+		// 露出した弱点を点滅表示
+		ebitenutil.DrawRect(dst, e.x+enemyWidth/2-8, e.y+enemyHeight/2-8, 16, 16, color.RGBA{255, 255, 0, 255})
+	}
+	drawFrontShield(dst, e)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// parseLogLevel はログレベルの名前（debug・info・warn・error）を解釈します
+func parseLogLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.ToUpper(name))); err != nil {
+		return 0, fmt.Errorf("log-levelの値が不正です: %q", name)
+	}
+	return level, nil
+}
+
+// setupLogging は指定したレベル以上のログを標準エラー出力に書き出すようにします。
+// pathを指定したときはそのファイルにも追記します。ファイルは終了時に閉じるため返します
+func setupLogging(levelName, path string) (io.Closer, error) {
+	level, err := parseLogLevel(levelName)
+	if err != nil {
+		return nil, err
+	}
+	var w io.Writer = os.Stderr
+	var file *os.File
+	if path != "" {
+		file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("ログファイルを開けません: %v", err)
+		}
+		w = io.MultiWriter(os.Stderr, file)
+	}
+	// logパッケージの出力もこのハンドラを通る
+	slog.SetDefault(slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})))
+	if file == nil {
+		return io.NopCloser(nil), nil
+	}
+	return file, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"image/color"
+	"log/slog"
+
+	"SimpleShootingStar/i18n"
+)
+
+const (
+	maxLoops             = 2   // 周回の数。最後の周の最終ステージをクリアするとゲームクリア
+	loopBulletSpeedScale = 1.3 // 2周目以降の敵弾の速さの倍率
+	loopBannerFrames     = 150 // 周回の開始を知らせる帯の表示フレーム数
+)
+
+// loopSpeedScale は周回に応じて敵弾の速さに掛ける倍率を返します
+func (g *Game) loopSpeedScale() float64 {
+	if g.loop == 0 {
+		return 1
+	}
+	return loopBulletSpeedScale
+}
+
+// canStartNextLoop は最終ステージをクリアしたときに次の周へ進めるかを返します。
+// キャラバンなどのモードでは周回しません
+func (g *Game) canStartNextLoop() bool {
+	return g.loop+1 < maxLoops && g.caravan == nil && g.practice == nil && g.timeAttack == nil && g.daily == nil
+}
+
+// startNextLoop はスコアや残機を引き継いだまま、最初のステージから次の周を始めます
+func (g *Game) startNextLoop() {
+	g.loop++
+	slog.Info("周回", "loop", g.loop+1, "score", g.score)
+	g.startStage(0)
+	g.addOverlay(Overlay{
+		text:  i18n.Tf("overlay.loop", g.loop+1),
+		y:     int(playArea.height / 3),
+		timer: loopBannerFrames,
+		color: color.RGBA{255, 120, 120, 255},
+		band:  color.RGBA{80, 0, 0, 160},
+	})
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"SimpleShootingStar/i18n"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	DropMagnet = "magnet" // 倒すとマグネットのアイテムを落とす（Waveのdrop）
+
+	maxMagnetLevel     = 3   // マグネットの段階の上限
+	magnetBaseRange    = 40  // マグネットなしでアイテムを引き寄せ始める自機の中心からの距離
+	magnetRangePerLv   = 45  // マグネット1段ごとに広がる引き寄せの距離
+	magnetAccel        = 0.5 // 引き寄せの範囲にあるアイテムの自機へ向かう加速度
+	magnetMaxSpeed     = 9   // 引き寄せられるアイテムの最高速度
+	magnetItemSize     = 12  // マグネットのアイテムの大きさ
+	magnetItemFall     = 0.8 // マグネットのアイテムが流れてくる速さ
+	magnetItemPickSize = 20  // 自機の中心からこの距離以内のマグネットのアイテムを回収する
+)
+
+// MagnetItem は取るとアイテムを引き寄せる範囲が広がるパワーアップです
+type MagnetItem struct {
+	x, y   float64
+	vx, vy float64
+	phase  float64 // 点滅の位相
+}
+
+func init() {
+	// やられるとマグネットが1段下がる
+	subscribe(EventPlayerDied, func(g *Game, _ Event) {
+		if g.magnetLevel > 0 {
+			g.magnetLevel--
+		}
+	})
+}
+
+// validateDrop はウェーブのdropの値を確認します
+func validateDrop(w Wave) error {
+	switch w.Drop {
+	case "", DropMagnet:
+		return nil
+	}
+	return fmt.Errorf("dropの値が不正です: %q", w.Drop)
+}
+
+// magnetRange は今のマグネットの段階でアイテムを引き寄せ始める距離を返します
+func (g *Game) magnetRange() float64 {
+	return magnetBaseRange + float64(g.magnetLevel)*magnetRangePerLv
+}
+
+// attract は自機の中心(px, py)から引き寄せの範囲にあるアイテムの速度を自機へ向けて加速させ、
+// 範囲にあればtrueを返します
+func (g *Game) attract(x, y float64, vx, vy *float64, px, py float64) bool {
+	dx, dy := px-x, py-y
+	dist := math.Hypot(dx, dy)
+	if dist >= g.magnetRange() || dist == 0 {
+		return false
+	}
+	*vx += dx / dist * magnetAccel
+	*vy += dy / dist * magnetAccel
+	if speed := math.Hypot(*vx, *vy); speed > magnetMaxSpeed {
+		*vx *= magnetMaxSpeed / speed
+		*vy *= magnetMaxSpeed / speed
+	}
+	return true
+}
+
+// dropItem は倒した敵がdropを持っていれば、その位置にアイテムを落とします
+func (g *Game) dropItem(e Enemy) {
+	if e.drop != DropMagnet {
+		return
+	}
+	w, h := enemySize(e.enemyType)
+	g.magnets = append(g.magnets, MagnetItem{x: e.x + w/2, y: e.y + h/2})
+}
+
+// updateMagnets はマグネットのアイテムを流し、自機が取ったらマグネットを1段上げます
+func (g *Game) updateMagnets() {
+	px, py := g.playerX+10, g.playerY+12
+	newItems := g.magnets[:0]
+	for _, m := range g.magnets {
+		m.phase += 0.15
+		if !g.attract(m.x, m.y, &m.vx, &m.vy, px, py) {
+			m.vx *= 0.9
+			m.vy = magnetItemFall
+		}
+		m.x += m.vx
+		m.y += m.vy
+		if math.Hypot(m.x-px, m.y-py) < magnetItemPickSize {
+			g.collectMagnet(m)
+			continue
+		}
+		if m.y < playArea.height+magnetItemSize {
+			newItems = append(newItems, m)
+		}
+	}
+	g.magnets = newItems
+}
+
+// collectMagnet はマグネットを1段上げ、段階を浮かぶ文字で知らせます
+func (g *Game) collectMagnet(m MagnetItem) {
+	g.magnetLevel = min(g.magnetLevel+1, maxMagnetLevel)
+	g.emit(Event{Kind: EventPowerUpCollected, X: m.x, Y: m.y})
+	g.addFloatingText(FloatingText{
+		x:     m.x,
+		y:     m.y - 12,
+		text:  i18n.Tf("magnet.level", g.magnetLevel),
+		timer: floatingTextFrames * 2,
+		color: color.RGBA{120, 200, 255, 255},
+	})
+}
+
+// drawMagnets はマグネットのアイテムを点滅する青い輪として描画します
+func (g *Game) drawMagnets(field *ebiten.Image) {
+	for _, m := range g.magnets {
+		clr := color.RGBA{80, 160, 255, 255}
+		if math.Sin(m.phase) > 0 {
+			clr = color.RGBA{200, 230, 255, 255}
+		}
+		vector.StrokeCircle(field, float32(m.x), float32(m.y), magnetItemSize/2, 2, clr, true)
+		vector.DrawFilledCircle(field, float32(m.x), float32(m.y), 2, clr, true)
+	}
+}
@@ -1,41 +1,55 @@
 package main
 
 import (
-	"encoding/json"
+	"flag"
 	"fmt"
 	"image/color"
-	"log"
+	"log/slog"
 	"math"
-	"math/rand"
-	"os"
+	"path/filepath"
 
 	"SimpleShootingStar/audio"
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+	"SimpleShootingStar/i18n"
+	"SimpleShootingStar/tween"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"github.com/hajimehoshi/ebiten/v2/text"
-	"golang.org/x/image/font"
-	"golang.org/x/image/font/opentype"
 )
 
 const (
-	screenWidth  = 640
-	screenHeight = 480
+	fontFile = "assets/NotoSansJP-Regular.ttf" // 表示に使うフォント
+
+	hitFlashFrames = 4  // 被弾した敵を白く光らせるフレーム数
+	comboWindow    = 90 // 次の撃破までにこのフレーム数を過ぎるとコンボが途切れる
 )
 
 // GameState はゲームの状態を表す定数
 const (
 	GameStateTitle = iota
+	GameStateShipSelect
 	GameStatePlaying
 	GameStateStageClear
 	GameStatePlayerExplosion
 	GameStateGameOver
+	GameStateStats
+	GameStateBossSelect
+	GameStateTimeAttackSelect
+	GameStateCaravanResult
+	GameStateStagePackSelect
+	GameStateDailyResult
 )
 
 // Bullet は弾の状態を保持する構造体です
 type Bullet struct {
-	x, y   float64
-	vx, vy float64
+	x, y    float64
+	vx, vy  float64
+	damage  int     // 命中時に与えるダメージ
+	pierce  int     // あと何体の敵を貫通できるか
+	bounces int     // あと何回画面端で跳ね返るか
+	blast   float64 // 当たったときの爆風の半径（0なら爆風なし）
+	hitIDs  []int   // 貫通中に当たった敵の番号（同じ敵に続けて当たらないように）
 }
 
 // Star は背景の流れる星を表す構造体
@@ -52,30 +66,53 @@ const (
 	EnemyTypeSine            // サインカーブで動く敵
 	EnemyTypeSpecial         // 特殊な動きをする敵
 	EnemyTypeBoss            // ボス敵
+	EnemyTypeMiner           // 機雷を設置する敵
+	EnemyTypeCarrier         // 子機を発進させる敵
+	EnemyTypeTurret          // ボスに取り付けられた砲台
 )
 
 // EnemyBullet構造体を追加
 type EnemyBullet struct {
-	x, y   float64
-	vx, vy float64
+	x, y    float64
+	vx, vy  float64
+	ownerID int         // 撃った敵の番号（0なら機雷など敵以外から出た弾）
+	trail   BulletTrail // 軌跡を描くための直前の位置
+	grazed  bool        // 自機の近くをかすめて風切り音を鳴らしたか
+}
+
+// enemySize は敵の種類ごとの大きさを返します
+func enemySize(enemyType int) (width, height float64) {
+	switch enemyType {
+	case EnemyTypeBoss:
+		return 60, 40 // ボスは大きく
+	case EnemyTypeCarrier:
+		return 40, 30
+	case EnemyTypeTurret:
+		return 16, 16
+	}
+	return 20, 20
 }
 
-// Enemy は敵の状態を保持する構造体
-type Enemy struct {
-	x, y           float64
-	speed          float64
-	enemyType      int
-	time           float64 // 時間経過（サインカーブ用）
-	phase          int     // 特殊な動きのフェーズ
-	hp             int     // 耐久度を追加
-	shootsBullet   bool    // 弾を撃つ敵かどうか
-	bulletType     int     // 0:主人公狙い, 1:真下, 2:斜め
-	bulletCooldown int     // 弾発射クールダウン
-	turnDirection  int     // 追加
-	// ボス専用フィールド
-	bossState     int // ボスの行動状態（0:移動, 1:攻撃準備, 2:攻撃中, 3:休憩）
-	bossTimer     int // ボス用タイマー
-	moveDirection int // 移動方向（-1:左, 1:右）
+// enemyHP は敵の種類ごとの耐久度を返します。値はtuning.jsonで変えられます
+func enemyHP(enemyType int) int {
+	hp := tuning.EnemyHP
+	switch enemyType {
+	case EnemyTypeStraight:
+		return hp.Straight
+	case EnemyTypeSine:
+		return hp.Sine
+	case EnemyTypeSpecial:
+		return hp.Special
+	case EnemyTypeBoss:
+		return hp.Boss
+	case EnemyTypeMiner:
+		return hp.Miner
+	case EnemyTypeCarrier:
+		return hp.Carrier
+	case EnemyTypeTurret:
+		return hp.Turret
+	}
+	return 1
 }
 
 // Wave は敵の出現パターンを表す構造体
@@ -87,503 +124,628 @@ type Wave struct {
 	BulletType    int     `json:"bulletType"`
 	Speed         float64 `json:"speed"`
 	TurnDirection int     `json:"turnDirection"`
+	Armor         int     `json:"armor"`       // 自機弾のダメージを減らす装甲値（省略時は0）
+	Evasive       bool    `json:"evasive"`     // 向かってくる自機弾を横に避ける
+	FrontShield   bool    `json:"frontShield"` // 進む向きの正面に盾を構え、正面からの弾を弾く
+	Formation     int     `json:"formation"`   // 同じ番号のウェーブの敵を1組の編隊にする（0なら編隊なし）
+	Drop          string  `json:"drop"`        // 倒したときに落とすアイテム（"magnet"。省略時はなし）
+	// 特別な行動（"kamikaze"）とその設定
+	Behavior string      `json:"behavior"`
+	Kamikaze KamikazeDef `json:"kamikaze"`
+	// キャリア用の設定
+	ChildType     int `json:"childType"`     // 発進させる子機の種類
+	SpawnInterval int `json:"spawnInterval"` // 子機の発進間隔（フレーム）
+	MaxChildren   int `json:"maxChildren"`   // 同時に存在できる子機の数
+	// ボス用の設定
+	Turrets []TurretDef `json:"turrets"` // 取り付ける砲台
+	Attack  []BossStep  `json:"attack"`  // 攻撃状態で実行する命令（省略時は真下への5way弾）
 }
 
 // Particle はパーティクルの状態を保持する構造体
 type Particle struct {
 	x, y     float64
-	vx, vy   float64 // 速度
-	size     float64 // サイズ
-	alpha    float64 // 透明度
-	lifetime int     // 生存時間
-	ptype    int     // 0:通常, 1:発射ライン
+	vx, vy   float64      // 速度
+	size     float64      // サイズ
+	alpha    float64      // 透明度
+	lifetime int          // 生存時間
+	ptype    int          // 0:通常, 1:発射ライン
+	maxLife  int          // 出たときの寿命（色の移り変わりに使う）
+	gravity  float64      // 1フレームごとに下向きに加わる速さ
+	ramp     []color.RGBA // 色の移り変わり（nilなら白）
+	shape    int          // 形（ParticleSquareなど）
+	rotation float64      // 回転角（破片用）
+	spin     float64      // 1フレームの回転量（破片用）
+	growth   float64      // 1フレームで広がる量（衝撃波用）
+	color    color.RGBA   // 色（衝撃波・破片・閃光用）
 }
 
 // Stage はステージの情報を保持する構造体
 type Stage struct {
-	Name  string `json:"name"`
-	Waves []Wave `json:"waves"`
+	Name       string       `json:"name"`
+	Objective  string       `json:"objective"`  // ステージ開始時に表示する目標（省略可）
+	Background Background   `json:"background"` // 背景の設定（省略時は既定の星空）
+	Waves      []Wave       `json:"waves"`
+	Events     []StageEvent `json:"events"`  // ステージの途中で起きる演出（省略可）
+	Shuffle    *WaveShuffle `json:"shuffle"` // ウェーブの並びと横位置を入れ替える（省略時は書いた順）
+	Grade      *StageGrade  `json:"grade"`   // クリア時の評価の基準（省略時は評価しない）
 }
 
 // StageData はJSONファイルから読み込むステージデータの構造体
 type StageData struct {
+	Name   string  `json:"name"`   // ステージパックの名前（modsのパックのみ）
+	Append bool    `json:"append"` // trueなら標準のステージの後ろに続ける（modsのパックのみ）
 	Stages []Stage `json:"stages"`
 }
 
+// stages は選んでいるステージパックのステージです
 var stages []Stage
 
-// loadStages はJSONファイルからステージ情報を読み込みます
-func loadStages() error {
-	file, err := os.ReadFile("stage/stages.json")
-	if err != nil {
-		return fmt.Errorf("ステージファイルの読み込みに失敗: %v", err)
-	}
-
-	var stageData StageData
-	if err := json.Unmarshal(file, &stageData); err != nil {
-		return fmt.Errorf("JSONのパースに失敗: %v", err)
-	}
-
-	stages = stageData.Stages
-	return nil
-}
-
 // Game はゲームの状態を保持する構造体です
 type Game struct {
 	playerX               float64
 	playerY               float64
+	playerVX, playerVY    float64 // 自機の速度（操作感がinertialの自機は慣性が残る）
 	bullets               []Bullet
 	shootCooldown         int    // 連射防止用
 	stars                 []Star // 星のスライスを追加
 	enemies               []Enemy
 	waves                 []Wave
+	spawnFrames           []int // ウェーブごとの出現フレーム（setWavesで計算する）
 	waveTimer             int
 	currentSpawn          int
+	framesSinceSpawn      int          // 最後にウェーブのまとまりを出し始めてからのフレーム数
+	stageFrames           int          // ステージ開始のバナーが消えてからのフレーム数
+	stageStartScore       int          // ステージ開始時のスコア
+	stageGrade            string       // クリアしたステージの評価（評価しないときは空）
+	stageMisses           int          // ステージ中にやられた回数
+	stageEscaped          int          // ステージ中に画面外へ逃した敵の数
+	escapedByWave         map[int]int  // ウェーブの番号ごとの逃した敵の数
+	tally                 *Tally       // ステージクリア時のボーナスの集計（集計しないときはnil）
+	scoreRoll             RollingScore // HUDに表示する、実際のスコアを追いかけるスコア
+	introSlideIn          tween.Tween  // ステージ開始のバナーが左から滑り込む動き（プレイエリアの幅に対する割合）
+	introSlideOut         tween.Tween  // ステージ開始のバナーが右へ抜けていく動き
+	shipCursor            tween.Tween  // 自機選択画面の選択枠の位置（何番目の自機か）
 	score                 int
-	gameState             int        // ゲームの状態
-	highScore             int        // ハイスコア
-	particles             []Particle // パーティクルを追加
-	currentStage          int        // 現在のステージ番号
-	stageClearTimer       int        // ステージクリア演出用
-	stageClearKeyReleased bool       // ステージクリア画面でキーリリースを検知
-	playerExplosionTimer  int        // 爆発演出用
+	gameState             int          // ゲームの状態
+	highScore             int          // ハイスコア
+	particles             ParticlePool // 爆発などのパーティクル（上限を超えたら古い粒から消す）
+	currentStage          int          // 現在のステージ番号
+	stageClearTimer       int          // ステージクリア演出用
+	stageClearKeyReleased bool         // ステージクリア画面でキーリリースを検知
+	playerExplosionTimer  int          // 爆発演出用
 	enemyBullets          []EnemyBullet
+	mines                 []Mine             // 敵が設置した機雷
+	beams                 []Beam             // 敵のレーザー攻撃
+	overlays              []Overlay          // 一時的なUI表示
+	floatingTexts         []FloatingText     // ダメージの数字など、その場に浮かぶ文字
+	playerTrail           PlayerTrail        // 自機の残像を描くための直前の位置
+	effects               EffectPool         // 噴射炎・発射炎の粒（固定長で使い回す）
+	formations            map[int]*Formation // 出現中の編隊（編隊の番号ごと）
+	openFormations        map[int]int        // stages.jsonのformation番号ごとの、出現させている途中の編隊の番号
+	bossWarningTimer      int                // ボス警告の残りフレーム数
+	bossWarned            bool               // 次のボス出現に対して警告済みか
+	bossApproach          BossApproach       // ボス接近の背景の演出
+	loop                  int                // 周回（0が1周目）
+	nextStageEvent        int                // 次に実行するステージイベントの番号
+	backgroundOverride    *Background        // ステージイベントで切り替えた背景（nilならステージの背景）
+	shakeTimer            int                // 画面を揺らす残りフレーム数
+	shakeStrength         float64            // 画面の揺れ幅（ピクセル）
+	hitStopTimer          int                // ヒットストップの残りフレーム数
+	grazeCooldown         int                // 次に風切り音を鳴らせるまでのフレーム数
+	slowMotionTimer       int                // スローモーションの残りフレーム数
+	timeAccumulator       float64            // スローモーション中に進めた時間の端数
+	selectedShip          int                // 選択中の自機（ships のインデックス）
+	field                 *ebiten.Image      // プレイエリアの描画先
+	canvas                *ebiten.Image      // 画面を回転・後処理するときの元の描画先
+	crt                   *ebiten.Image      // ブラウン管風の後処理をかけた画面
+	lives                 int                // 残機
+	invincibleTimer       int                // 復活後の無敵の残りフレーム数
+	focused               bool               // 低速移動（フォーカス）中か
+	bombs                 int                // ボムの残り数
+	bombFlashTimer        int                // ボム使用時の画面フラッシュの残りフレーム数
+	bulletTimeTimer       int                // 敵弾が遅くなっている残りフレーム数
+	ceaseFireTimer        int                // 復活直後に敵が弾を撃たない残りフレーム数
+	practice              *BossPractice      // ボス練習モードの状態（通常のプレイ中はnil）
+	bossSelect            bossSelect         // ボス選択画面のカーソルと設定
+	timeAttack            *TimeAttack        // タイムアタックの状態（通常のプレイ中はnil）
+	timeAttackSelect      timeAttackSelect
+	caravan               *Caravan  // キャラバンの状態（通常のプレイ中はnil）
+	daily                 *Daily    // デイリーチャレンジの状態（通常のプレイ中はnil）
+	tutorial              *Tutorial // チュートリアルの状態（通常のプレイ中はnil）
+	stagePackSelect       stagePackSelect
+	combo                 int           // 連続撃破数
+	comboTimer            int           // コンボが途切れるまでの残りフレーム数
+	nextEntityID          int           // 最後に割り当てた物体の番号
+	transition            *Transition   // 画面切り替えの演出（nilなら演出なし）
+	stageIntroTimer       int           // ステージ開始のバナーの残り表示フレーム数
+	scrollY               float64       // 背景のタイル画像のスクロール位置
+	rank                  float64       // 難易度の自動調整値（0〜1）
+	showDebug             bool          // デバッグ表示中か
+	tokens                []StarToken   // 敵が落としたスタートークン
+	scoreItems            []ScoreItem   // 敵弾を消して出た得点アイテム
+	magnets               []MagnetItem  // 敵が落としたマグネットのアイテム
+	dyingEnemies          []DyingEnemy  // 倒されて消えていく途中の敵
+	deathQueue            []KilledEnemy // 倒されて、当たり判定のあとの掃除で取り除かれるのを待っている敵
+	magnetLevel           int           // マグネットの段階（アイテムを引き寄せる範囲が広がる）
+	multiplier            int           // スコア倍率
+	tokenGauge            int           // 次の倍率までに集めたトークンの数
+	recordsStats          bool          // ゲーム中の出来事を通算の統計に記録するか（シミュレーションは記録しない）
+	simReport             *SimReport    // シミュレーション中に出来事を数える結果（シミュレーション以外はnil）
+	input                 Input         // キー入力
+	justPolled            []ebiten.Key  // このUpdateで押されたキー（Updateごとに読む操作とクラッシュレポート用）
+	sound                 Sound         // 効果音・BGMの出力先
+	stepAccumulator       float64       // 固定タイムステップで未処理のステップの端数
+	debugPaused           bool          // デバッグ操作で一時停止中か
+	audioPaused           bool          // ゲームの一時停止に合わせて音を止めているか
+	cheatInvincible       bool          // チートで無敵にしているか
+	cheatWave             int           // チートで出現させるウェーブの番号
+	volumeIndicatorTimer  int           // 音量の表示を出しておく残りフレーム数
+	titleDemo             *Game         // タイトル画面の背景で動かしているデモ（タイトル画面を開くまではnil）
+	demo                  bool          // タイトル画面の背景のデモか（自機やオーバーレイを描かない）
+	windowTitleTimer      int           // ウィンドウのタイトルを次に作り直すまでのフレーム数
+	updatedSinceDraw      bool          // 前回の描画の後にUpdateが呼ばれたか（フレームレートの上限があるときに使う）
+	bench                 *Bench        // ベンチマークの状態（ベンチマーク中でなければnil）
 }
 
-var (
-	gameFont font.Face
-)
-
 // NewGame は新しいゲームインスタンスを作成します
 func NewGame() *Game {
-	// 星の色バリエーション
-	starColors := []color.RGBA{
-		{180, 180, 255, 100}, // 白
-		{140, 180, 255, 100}, // 青白
-		{100, 140, 255, 100}, // 青
-		{200, 200, 255, 80},  // 明るい白
-		{80, 120, 255, 80},   // 暗い青
-	}
-	stars := make([]Star, 60)
-	for i := range stars {
-		c := starColors[rand.Intn(len(starColors))]
-		stars[i] = Star{
-			x:      rand.Float64() * screenWidth,
-			y:      rand.Float64() * screenHeight,
-			speed:  2 + rand.Float64()*3,
-			length: 8 + rand.Float64()*8,
-			color:  c,
+	// 効果音システムの初期化（シミュレーション中は音を鳴らさない）
+	var sound Sound = silentSound{}
+	if !headless {
+		// 効果音が読み込めなくても音なしで遊べるようにする
+		if err := audio.Initialize(settings.audioConfig()); err != nil {
+			slog.Warn("効果音の読み込みに失敗したため、音なしで起動します", "err", err)
+		} else {
+			sound = audio.GetInstance()
 		}
 	}
+	sound.SetMasterVolume(settings.Volume, settings.Muted)
 
-	// 効果音システムの初期化
-	if err := audio.Initialize(); err != nil {
-		log.Fatal(err)
-	}
-
-	return &Game{
-		playerX:               screenWidth / 2,
-		playerY:               screenHeight / 2 * 1.7,
+	g := &Game{
+		playerX:               playArea.width / 2,
+		playerY:               playArea.height / 2 * 1.7,
 		bullets:               []Bullet{},
-		stars:                 stars,
 		enemies:               []Enemy{},
-		waves:                 stages[0].Waves,
 		waveTimer:             0,
 		currentSpawn:          0,
 		score:                 0,
 		gameState:             GameStateTitle,
-		highScore:             0,
-		particles:             []Particle{},
+		highScore:             stageRecords().HighScore,
+		particles:             newParticlePool(settings.MaxParticles),
 		currentStage:          0,
 		stageClearTimer:       0,
 		stageClearKeyReleased: false,
 		playerExplosionTimer:  0,
 		enemyBullets:          []EnemyBullet{},
+		mines:                 []Mine{},
+		beams:                 []Beam{},
+		overlays:              []Overlay{},
+		input:                 newKeyboardInput(),
+		sound:                 sound,
+		lives:                 tuning.Player.InitialLives,
+		bombs:                 tuning.Player.InitialBombs,
+		multiplier:            1,
+		recordsStats:          !headless,
 	}
+	g.setWaves(stages[0].Waves)
+	// 最初のステージの背景で星を作る
+	g.applyBackground()
+	return g
 }
 
-// createExplosion は爆発エフェクトのパーティクルを生成します
-func (g *Game) createExplosion(x, y float64, color color.RGBA) {
-	particleCount := 20
-	for i := 0; i < particleCount; i++ {
-		angle := rand.Float64() * math.Pi * 2
-		speed := 2 + rand.Float64()*3
-		particle := Particle{
-			x:        x,
-			y:        y,
-			vx:       math.Cos(angle) * speed,
-			vy:       math.Sin(angle) * speed,
-			size:     4 + rand.Float64()*4,
-			alpha:    1.0,
-			lifetime: 30 + rand.Intn(20),
-			ptype:    0,
+// onEnemyKilled は敵を倒したときのスコア加算・爆発・効果音などを処理します。
+// 倒した敵はmarkDeadで印を付け、sweepDeadでg.enemiesから取り除いてから呼び出されます
+func (g *Game) onEnemyKilled(e Enemy) {
+	// 敵の種類に応じたスコア加算
+	points := 100
+	switch e.enemyType {
+	case EnemyTypeBoss:
+		points = 1000 // ボスは高得点
+		if bonus := g.partsBonus(e); bonus > 1 {
+			// 砲台をすべて壊してから倒すとボスのスコアが増える
+			points *= bonus
+			g.addFloatingText(FloatingText{
+				x:     e.x + 40,
+				y:     e.y - 16,
+				text:  i18n.Tf("boss.partsBonus", bonus),
+				timer: floatingTextFrames * 3,
+				color: color.RGBA{255, 215, 0, 255},
+			})
 		}
-		g.particles = append(g.particles, particle)
+		g.startSlowMotion(8, 60)
+	case EnemyTypeCarrier:
+		points = 100 + carrierBonus // 子機の発進を止めたボーナス
+	case EnemyTypeTurret:
+		points = turretPoints(e)
 	}
-}
+	points *= g.multiplier // スタートークンで上げた倍率を掛ける
+	g.score += points
+	g.emit(Event{Kind: EventEnemyKilled, EntityID: e.id, ParentID: e.parentID, EnemyType: e.enemyType, X: e.x + 10, Y: e.y + 10, Points: points, Formation: e.formationID})
+	g.dropTokens(e)
+	g.dropItem(e)
+	g.cancelBullets(e)
+
+	// 連続撃破でコンボを伸ばす
+	g.combo++
+	g.comboTimer = comboWindow
+
+	// 敵の種類に応じた色で爆発エフェクト
+	explosionColor, size := enemyColor(e.enemyType), ExplosionSmall
+	if e.enemyType == EnemyTypeBoss {
+		explosionColor, size = color.RGBA{255, 215, 0, 255}, ExplosionBoss // 金色
+	}
+	g.createExplosion(e.x+10, e.y+10, explosionColor, size)
+	g.startDeathAnim(e)
 
-// nextWave は次のウェーブに進みます
-func (g *Game) nextWave() {
-	g.currentSpawn = 0
-	g.waveTimer = 0
-
-	// 現在のステージの全ウェーブをクリアした場合
-	if g.currentSpawn >= len(g.waves) {
-		g.currentStage++
-		// 全ステージクリア
-		if g.currentStage >= len(stages) {
-			g.gameState = GameStateGameOver
-			if g.score > g.highScore {
-				g.highScore = g.score
-			}
-			return
-		}
-		// 次のステージのウェーブを設定
-		g.waves = stages[g.currentStage].Waves
-		g.currentSpawn = 0
+	if e.enemyType == EnemyTypeTurret {
+		g.onTurretDestroyed(e.parentID)
 	}
 }
 
-// Update はゲームの状態を更新します
-func (g *Game) Update() error {
-	// 星の移動（どの状態でも動く）
-	for i := range g.stars {
-		g.stars[i].y += g.stars[i].speed
-		if g.stars[i].y > screenHeight {
-			g.stars[i].x = rand.Float64() * screenWidth
-			g.stars[i].y = -g.stars[i].length
-			g.stars[i].speed = 2 + rand.Float64()*3
-			g.stars[i].length = 8 + rand.Float64()*8
-		}
+// startStage は画面上の敵や弾を消して指定したステージを最初から始めます
+func (g *Game) startStage(stage int) {
+	g.currentStage = stage
+	g.progression().ResetStage()
+}
+
+// stage は今遊んでいるステージを返します。キャラバン中はキャラバン専用のステージです
+func (g *Game) stage() *Stage {
+	if g.caravan != nil {
+		return &caravanStage
+	}
+	if g.daily != nil {
+		return &g.daily.stage
 	}
+	if g.tutorial != nil {
+		return &tutorialStage
+	}
+	if g.currentStage >= len(stages) {
+		// 全ステージクリア後は最終ステージのまま
+		return &stages[len(stages)-1]
+	}
+	return &stages[g.currentStage]
+}
 
-	// パーティクルの更新（どの状態でも動く）
-	newParticles := g.particles[:0]
-	for _, p := range g.particles {
-		if p.ptype != 1 {
-			p.x += p.vx
-			p.y += p.vy
-			p.vy += 0.1 // 重力効果
+// infiniteLives はやられても残機が減らないモードならtrueを返します
+func (g *Game) infiniteLives() bool {
+	return g.caravan != nil || g.tutorial != nil || (g.practice != nil && g.practice.infiniteLives)
+}
+
+// spawnWave はウェーブの定義に従って敵を1体（砲台付きならその砲台も）出現させます
+func (g *Game) spawnWave(wave Wave) {
+	speed := wave.Speed
+	if speed == 0 {
+		speed = 2.0 // デフォルト
+	}
+	turnDir := wave.TurnDirection
+	if turnDir == 0 {
+		turnDir = 1 // デフォルト右
+	}
+	enemy := g.newEnemy(wave.EnemyType, playArea.stageX(wave.X), -20, speed)
+	enemy.turnDirection = turnDir
+	enemy.armor = wave.Armor
+	enemy.drop = wave.Drop
+	enemy.hasTurrets = shieldingTurrets(wave.Turrets)
+	enemy.parts = len(wave.Turrets)
+	enemy.formationID = g.joinFormation(wave)
+	enemy.wave = g.currentSpawn + 1
+	if wave.Evasive {
+		enemy.evader = &Evader{}
+	}
+	if wave.FrontShield {
+		enemy.frontShield = newFrontShield()
+	}
+	if wave.Behavior == BehaviorKamikaze {
+		enemy.kamikaze = newKamikaze(wave.Kamikaze)
+	}
+	if wave.ShootsBullet {
+		enemy.shooter = newShooter(wave.BulletType)
+	}
+	if c := enemy.carrier; c != nil {
+		c.childType = wave.ChildType
+		if wave.SpawnInterval > 0 {
+			c.spawnInterval = wave.SpawnInterval
+			c.spawnTimer = wave.SpawnInterval / 2
 		}
-		p.alpha -= 1.0 / float64(p.lifetime)
-		p.lifetime--
-		if p.lifetime > 0 && p.alpha > 0 {
-			newParticles = append(newParticles, p)
+		if wave.MaxChildren > 0 {
+			c.maxChildren = wave.MaxChildren
 		}
 	}
-	g.particles = newParticles
+	if enemy.boss != nil && len(wave.Attack) > 0 {
+		enemy.boss.script = wave.Attack
+	}
+	g.addEnemy(enemy)
+	g.spawnTurrets(enemy, wave.Turrets)
+	if wave.EnemyType == EnemyTypeBoss && g.practice == nil && g.caravan == nil && g.daily == nil {
+		markBossSeen(g.currentStage)
+	}
+}
+
+// beforeSpawn はウェーブの敵を出現させる直前に呼ばれます。
+// 出現を保留する場合はfalseを返します
+func (g *Game) beforeSpawn(wave Wave) bool {
+	if wave.EnemyType == EnemyTypeBoss && !g.bossWarned {
+		g.startBossWarning()
+		return false
+	}
+	return g.bossWarningTimer == 0 && g.shuffleAllows()
+}
+
+// startBossWarning はボス出現前の警告演出を開始します
+func (g *Game) startBossWarning() {
+	g.bossWarned = true
+	g.bossWarningTimer = tuning.Boss.WarningFrames
+	g.addOverlay(Overlay{
+		text:     i18n.T("overlay.warning"),
+		y:        int(playArea.height / 2),
+		timer:    tuning.Boss.WarningFrames,
+		color:    color.RGBA{255, 255, 255, 255},
+		band:     color.RGBA{200, 0, 0, 160},
+		flashing: true,
+	})
+	g.sound.Play("warning")
+	g.sound.PlayBGM("boss")
+}
+
+// tick はゲームの状態を1ステップ（1/60秒分）進めます
+func (g *Game) tick() error {
+	// ヒットストップ・スローモーション中はエンティティの更新を間引く
+	step := g.advanceTime()
+
+	if step {
+		// 背景のスクロール（どの状態でも動く）
+		g.updateBossApproach()
+		g.updateShake()
+		g.updateBackground()
+
+		// パーティクルの更新（どの状態でも動く）
+		g.particles.update()
+		g.effects.update()
+		g.updateFloatingTexts()
+		g.updateLifecycle()
+		g.scoreRoll.update(g.score)
+	}
+
+	// オーバーレイとデバッグ表示の更新（どの状態でも動く。シミュレーション中はデバッグ操作とクリップを扱わない）
+	g.updateOverlays()
+	if !headless {
+		g.updateDebug()
+		g.updateClip()
+		g.updateProfiling()
+	}
+
+	// 画面切り替えの演出中は状態を更新しない
+	if g.updateTransition() {
+		return nil
+	}
 
 	switch g.gameState {
 	case GameStateTitle:
-		// スペースキーでゲーム開始
-		if ebiten.IsKeyPressed(ebiten.KeySpace) {
-			g.gameState = GameStatePlaying
+		g.updateTitleDemo()
+
+		// スペースキーで自機選択へ、Sキーで統計画面へ、中断セーブがあればRキーで再開、
+		// 出会ったボスがいればBキーでボス練習へ、Tキーでタイムアタックへ、Cキーでキャラバンへ、Dキーでデイリーチャレンジへ、
+		// Hキーでチュートリアルへ（初めて遊ぶときはスペースキーでもチュートリアルへ）、
+		// ステージパックが入っていればPキーでパック選択へ
+		if suspended != nil && g.input.JustPressed(ebiten.KeyR) {
+			g.sound.Play("menuConfirm")
+			g.resumeRun()
+		} else if len(stagePacks) > 1 && g.input.JustPressed(ebiten.KeyP) {
+			g.sound.Play("menuConfirm")
+			g.stagePackSelect.cursor = currentPack
+			g.startTransition(TransitionFade, func() {
+				g.gameState = GameStateStagePackSelect
+			}, nil)
+		} else if g.input.JustPressed(ebiten.KeyC) {
+			g.sound.Play("menuConfirm")
+			g.startTransition(TransitionIris, func() {
+				g.startCaravan()
+			}, func() {
+				g.sound.PlayBGM("stage")
+			})
+		} else if g.input.JustPressed(ebiten.KeyH) {
+			g.sound.Play("menuConfirm")
+			g.startTransition(TransitionIris, func() {
+				g.startTutorial(false)
+			}, nil)
+		} else if g.input.JustPressed(ebiten.KeyD) {
+			g.sound.Play("menuConfirm")
+			g.startTransition(TransitionIris, func() {
+				g.startDaily()
+			}, func() {
+				g.sound.PlayBGM("stage")
+			})
+		} else if g.input.JustPressed(ebiten.KeyT) {
+			g.sound.Play("menuConfirm")
+			g.startTransition(TransitionFade, func() {
+				g.gameState = GameStateTimeAttackSelect
+			}, nil)
+		} else if len(practiceStages()) > 0 && g.input.JustPressed(ebiten.KeyB) {
+			g.sound.Play("menuConfirm")
+			g.startTransition(TransitionFade, func() {
+				g.gameState = GameStateBossSelect
+			}, nil)
+		} else if g.input.Pressed(ebiten.KeySpace) && !saveData.TutorialDone {
+			// 初めて遊ぶときは自機選択の前にチュートリアルを挟む
+			g.sound.Play("menuConfirm")
+			g.startTransition(TransitionIris, func() {
+				g.startTutorial(true)
+			}, nil)
+		} else if g.input.Pressed(ebiten.KeySpace) {
+			g.sound.Play("menuConfirm")
+			g.startTransition(TransitionFade, func() {
+				g.gameState = GameStateShipSelect
+			}, nil)
+		} else if g.input.JustPressed(ebiten.KeyS) {
+			g.sound.Play("menuConfirm")
+			g.startTransition(TransitionFade, func() {
+				g.gameState = GameStateStats
+			}, nil)
+		} else if g.input.JustPressed(ebiten.KeyR) || g.input.JustPressed(ebiten.KeyP) || g.input.JustPressed(ebiten.KeyB) {
+			// 中断セーブ・ステージパック・出会ったボスがなく今は選べないことを音で知らせる
+			g.sound.Play("menuDenied")
 		}
+	case GameStateShipSelect:
+		g.updateShipSelect()
+	case GameStateStats:
+		g.updateStats()
+	case GameStateBossSelect:
+		g.updateBossSelect()
+	case GameStateTimeAttackSelect:
+		g.updateTimeAttackSelect()
+	case GameStateCaravanResult:
+		g.updateCaravanResult()
+	case GameStateStagePackSelect:
+		g.updateStagePackSelect()
+	case GameStateDailyResult:
+		g.updateDailyResult()
 	case GameStatePlaying:
-		// 既存のゲームプレイ処理
-		moveSpeed := 8.0
-		// プレイヤーの移動処理
-		if ebiten.IsKeyPressed(ebiten.KeyLeft) {
-			g.playerX -= moveSpeed
-			if g.playerX < 20 {
-				g.playerX = 20
-			}
+		if !step {
+			break
 		}
-		if ebiten.IsKeyPressed(ebiten.KeyRight) {
-			g.playerX += moveSpeed
-			if g.playerX > screenWidth-40 {
-				g.playerX = screenWidth - 40
-			}
+		if g.invincibleTimer > 0 {
+			g.invincibleTimer--
 		}
-		if ebiten.IsKeyPressed(ebiten.KeyUp) {
-			g.playerY -= moveSpeed
-			if g.playerY < 40 {
-				g.playerY = 40
-			}
+		g.updateStageIntro()
+		g.updateRank()
+		g.updateCheats()
+		g.updateSuspend()
+		g.updateBossPractice()
+		g.updateTimeAttack()
+		g.updateCaravan()
+		g.updateDaily()
+		g.updateTutorial()
+		g.updateStageTimer()
+		g.emit(Event{Kind: EventPlayFrame})
+		g.updateBombs()
+		if g.ceaseFireTimer > 0 {
+			g.ceaseFireTimer--
 		}
-		if ebiten.IsKeyPressed(ebiten.KeyDown) {
-			g.playerY += moveSpeed
-			if g.playerY > screenHeight-20 {
-				g.playerY = screenHeight - 20
+		if g.comboTimer > 0 {
+			g.comboTimer--
+			if g.comboTimer == 0 {
+				g.combo = 0
 			}
 		}
 
-		// 敵の出現処理
-		if g.currentSpawn < len(g.waves) {
-			// 累積delay方式
-			totalDelay := 0
-			for i := 0; i <= g.currentSpawn; i++ {
-				totalDelay += g.waves[i].Delay
-			}
-			if g.waveTimer >= totalDelay {
-				wave := g.waves[g.currentSpawn]
-				hp := 1
-				switch wave.EnemyType {
-				case EnemyTypeStraight:
-					hp = 2
-				case EnemyTypeSine:
-					hp = 3
-				case EnemyTypeSpecial:
-					hp = 4
-				case EnemyTypeBoss:
-					hp = 50 // ボスは高い耐久力
-				}
-				speed := wave.Speed
-				if speed == 0 {
-					speed = 2.0 // デフォルト
-				}
-				turnDir := wave.TurnDirection
-				if turnDir == 0 {
-					turnDir = 1 // デフォルト右
-				}
-				enemy := Enemy{
-					x:              float64(wave.X),
-					y:              -20,
-					speed:          speed,
-					enemyType:      wave.EnemyType,
-					time:           0,
-					phase:          0,
-					hp:             hp,
-					shootsBullet:   wave.ShootsBullet,
-					bulletType:     wave.BulletType,
-					bulletCooldown: 60 + rand.Intn(60), // 1〜2秒ごとに発射
-					turnDirection:  turnDir,
-					// ボス専用の初期化
-					bossState:     0, // 移動状態から開始
-					bossTimer:     0,
-					moveDirection: 1, // 右向きから開始
-				}
-				g.enemies = append(g.enemies, enemy)
-				g.currentSpawn++
-			}
+		prevX, prevY := g.playerX, g.playerY
+		// Shiftキーを押している間は低速移動
+		g.focused = g.input.Pressed(ebiten.KeyShift)
+
+		// プレイヤーの移動処理
+		g.movePlayer()
+		g.recordPlayerTrail(prevX, prevY)
+		if !g.demo {
+			g.emitThruster()
 		}
-		g.waveTimer++
-
-		// 敵の移動処理
-		for i := range g.enemies {
-			e := &g.enemies[i]
-			e.time += 0.05
-
-			switch e.enemyType {
-			case EnemyTypeStraight:
-				e.y += e.speed
-			case EnemyTypeSine:
-				e.y += e.speed
-				e.x += math.Sin(e.time) * 3
-			case EnemyTypeSpecial:
-				switch e.phase {
-				case 0: // 上昇
-					e.y += e.speed
-					if e.y > screenHeight/2 {
-						e.phase = 1
-					}
-				case 1: // 横移動
-					e.x += e.speed * float64(e.turnDirection)
-					if (e.turnDirection == 1 && e.x > screenWidth-40) || (e.turnDirection == -1 && e.x < 20) {
-						e.phase = 2
-					}
-				case 2: // 下降
-					e.y += e.speed
-				}
-			case EnemyTypeBoss:
-				// ボスの行動パターン
-				e.bossTimer++
-
-				switch e.bossState {
-				case 0: // 移動状態
-					// 画面上部で一定位置に移動
-					if e.y < 80 {
-						e.y += e.speed
-					} else {
-						// 左右に移動
-						e.x += e.speed * float64(e.moveDirection)
-
-						// 端に到達したら方向転換
-						if e.x <= 50 {
-							e.moveDirection = 1
-						} else if e.x >= screenWidth-90 {
-							e.moveDirection = -1
-						}
-
-						// 一定時間移動したら攻撃準備へ
-						if e.bossTimer > 120 { // 2秒間移動
-							e.bossState = 1
-							e.bossTimer = 0
-						}
-					}
-				case 1: // 攻撃準備（前振り）
-					// 攻撃の前振りで一時停止
-					if e.bossTimer > 60 { // 1秒間前振り
-						e.bossState = 2
-						e.bossTimer = 0
-					}
-				case 2: // 攻撃中
-					// 大量の弾を発射
-					if e.bossTimer%8 == 0 && e.bossTimer < 80 { // 10回連続発射
-						// 5way弾幕
-						for j := -2; j <= 2; j++ {
-							angle := float64(j) * 0.3 // 真下から左右に扇状
-							speed := 3.0
-							vx := math.Sin(angle) * speed
-							vy := math.Cos(angle) * speed
-							g.enemyBullets = append(g.enemyBullets, EnemyBullet{
-								x: e.x + 20, y: e.y + 30, vx: vx, vy: vy,
-							})
-						}
-						// 攻撃エフェクト
-						g.particles = append(g.particles, Particle{
-							x: e.x + 20, y: e.y + 30, vx: 0, vy: 4.0,
-							size: 100, alpha: 1.0, lifetime: 8, ptype: 1,
-						})
-					}
-
-					if e.bossTimer > 80 { // 攻撃終了
-						e.bossState = 3
-						e.bossTimer = 0
-					}
-				case 3: // 休憩状態
-					// 次の攻撃まで休憩
-					if e.bossTimer > 90 { // 1.5秒休憩
-						e.bossState = 0
-						e.bossTimer = 0
-					}
-				}
-			}
 
-			// 弾発射
-			if e.shootsBullet {
-				e.bulletCooldown--
-				if e.bulletCooldown <= 0 {
-					switch e.bulletType {
-					case 0: // 主人公狙い
-						dx := g.playerX - e.x
-						dy := g.playerY - e.y
-						dist := math.Hypot(dx, dy)
-						speed := 4.0
-						vx := dx / dist * speed
-						vy := dy / dist * speed
-						g.enemyBullets = append(g.enemyBullets, EnemyBullet{x: e.x + 10, y: e.y + 20, vx: vx, vy: vy})
-						g.particles = append(g.particles, Particle{x: e.x + 10, y: e.y + 20, vx: vx, vy: vy, size: 80, alpha: 1.0, lifetime: 5, ptype: 1})
-					case 1: // 真下
-						g.enemyBullets = append(g.enemyBullets, EnemyBullet{x: e.x + 10, y: e.y + 20, vx: 0, vy: 4.0})
-						g.particles = append(g.particles, Particle{x: e.x + 10, y: e.y + 20, vx: 0, vy: 4.0, size: 80, alpha: 1.0, lifetime: 5, ptype: 1})
-					case 2: // 斜め右下
-						g.enemyBullets = append(g.enemyBullets, EnemyBullet{x: e.x + 10, y: e.y + 20, vx: 2.0, vy: 4.0})
-						g.particles = append(g.particles, Particle{x: e.x + 10, y: e.y + 20, vx: 2.0, vy: 4.0, size: 80, alpha: 1.0, lifetime: 5, ptype: 1})
-					case 3: // 斜め左下
-						g.enemyBullets = append(g.enemyBullets, EnemyBullet{x: e.x + 10, y: e.y + 20, vx: -2.0, vy: 4.0})
-						g.particles = append(g.particles, Particle{x: e.x + 10, y: e.y + 20, vx: -2.0, vy: 4.0, size: 80, alpha: 1.0, lifetime: 5, ptype: 1})
-					}
-					e.bulletCooldown = 60 + rand.Intn(60)
-				}
+		// 敵の出現処理
+		g.framesSinceSpawn++
+		g.spawnDueWaves()
+		g.checkBossApproach()
+		g.updateStageEvents()
+
+		// ボス警告中はウェーブの進行を止め、サイレンを繰り返す
+		if g.bossWarningTimer > 0 {
+			g.bossWarningTimer--
+			if g.bossWarningTimer > 0 && g.bossWarningTimer%40 == 0 {
+				g.sound.Play("warning")
 			}
+		} else {
+			g.waveTimer++
 		}
 
-		// 画面外に出た敵を削除
+		// 敵の移動・攻撃
+		g.updateEnemies()
+
+		// 画面外に出た敵・親を失った砲台を削除（倒された敵は当たり判定のあとの掃除で取り除く）
 		newEnemies := g.enemies[:0]
 		for _, e := range g.enemies {
-			if e.y < screenHeight+20 {
+			if e.dead || (e.y < playArea.height+20 && !e.leftField() && e.hp > 0) {
 				newEnemies = append(newEnemies, e)
+			} else if e.hp > 0 {
+				g.emit(Event{Kind: EventEnemyEscaped, EntityID: e.id, ParentID: e.parentID, EnemyType: e.enemyType, Formation: e.formationID, Wave: e.wave})
 			}
 		}
 		g.enemies = newEnemies
 
-		// 全ての敵が出現し、かつ全滅したら次のステージへ
-		if g.currentSpawn >= len(g.waves) && len(g.enemies) == 0 {
-			g.gameState = GameStateStageClear
-			g.stageClearTimer = 0
-			g.stageClearKeyReleased = false
+		// 全ての敵が出現し、かつ全滅したら次のステージへ（キャラバンは時間切れまで、チュートリアルは手順を終えるまで続く）
+		if g.currentSpawn >= len(g.waves) && len(g.enemies) == 0 && g.caravan == nil && g.tutorial == nil {
+			g.startTransition(TransitionWipe, func() {
+				if g.practice != nil {
+					g.onBossPracticeCleared()
+				}
+				if g.timeAttack != nil {
+					g.onTimeAttackCleared()
+				}
+				g.onStageGraded()
+				g.logEscapes()
+				g.startTally()
+				g.gameState = GameStateStageClear
+				g.stageClearTimer = 0
+				g.stageClearKeyReleased = false
+			}, nil)
 		}
 
 		// 弾の発射（スペースキー）
-		if ebiten.IsKeyPressed(ebiten.KeySpace) && g.shootCooldown == 0 {
-			angles := []float64{-3, 0, 3}  // 度
-			offsets := []float64{0, 8, 16} // 左・中央・右
-			for i, deg := range angles {
-				rad := (math.Pi / 180) * deg
-				speed := 12.0
+		if g.input.Pressed(ebiten.KeySpace) && g.shootCooldown == 0 {
+			ship := g.ship()
+			for i, deg := range ship.ShotAngles {
+				rad := (math.Pi / 180) * g.shotAngle(deg)
+				speed := ship.ShotSpeed
 				bullet := Bullet{
-					x:  g.playerX + offsets[i],
-					y:  g.playerY,
-					vx: math.Sin(rad) * speed,
-					vy: -math.Cos(rad) * speed,
+					x:       g.playerX + ship.ShotOffsets[i],
+					y:       g.playerY,
+					vx:      math.Sin(rad) * speed,
+					vy:      -math.Cos(rad) * speed,
+					damage:  ship.shotDamage(),
+					pierce:  ship.ShotPierce,
+					bounces: ship.ShotBounces,
+					blast:   ship.ShotBlast,
 				}
 				g.bullets = append(g.bullets, bullet)
+				g.emitMuzzleFlash(bullet.x+2, bullet.y)
 			}
-			g.shootCooldown = 5
+			g.shootCooldown = ship.ShotCooldown
+			g.emit(Event{Kind: EventShotFired})
 			// 効果音を再生
-			audio.GetInstance().Play("shoot")
+			g.sound.Play("shoot")
 		}
 		if g.shootCooldown > 0 {
 			g.shootCooldown--
 		}
 
-		// 弾の移動と当たり判定
-		newBullets := g.bullets[:0]
-		for _, b := range g.bullets {
-			hit := false
-			for i := range g.enemies {
-				// 敵のサイズを考慮した当たり判定
-				var enemyWidth, enemyHeight float64 = 20, 20
-				if g.enemies[i].enemyType == EnemyTypeBoss {
-					enemyWidth, enemyHeight = 60, 40
-				}
+		// 機雷・ビームの更新
+		g.updateMines()
+		g.updateBeams()
+		g.updateTokens()
+		g.updateMagnets()
+		g.updateScoreItems()
 
-				if b.x < g.enemies[i].x+enemyWidth && b.x+4 > g.enemies[i].x &&
-					b.y < g.enemies[i].y+enemyHeight && b.y+8 > g.enemies[i].y {
-					hit = true
-					g.enemies[i].hp--
-					if g.enemies[i].hp <= 0 {
-						// 敵の種類に応じたスコア加算
-						switch g.enemies[i].enemyType {
-						case EnemyTypeBoss:
-							g.score += 1000 // ボスは高得点
-						default:
-							g.score += 100
-						}
-
-						// 敵の種類に応じた色で爆発エフェクト
-						var explosionColor color.RGBA
-						switch g.enemies[i].enemyType {
-						case EnemyTypeStraight:
-							explosionColor = color.RGBA{255, 0, 0, 255}
-						case EnemyTypeSine:
-							explosionColor = color.RGBA{255, 165, 0, 255}
-						case EnemyTypeSpecial:
-							explosionColor = color.RGBA{255, 0, 255, 255}
-						case EnemyTypeBoss:
-							explosionColor = color.RGBA{255, 215, 0, 255} // 金色
-						}
-						g.createExplosion(g.enemies[i].x+10, g.enemies[i].y+10, explosionColor)
-						g.enemies = append(g.enemies[:i], g.enemies[i+1:]...)
-					}
-					break
-				}
-			}
-			if !hit {
-				b.x += b.vx
-				b.y += b.vy
-				if b.y > -8 && b.x > -8 && b.x < screenWidth+8 {
-					newBullets = append(newBullets, b)
-				}
-			}
-		}
-		g.bullets = newBullets
+		// 弾の移動と当たり判定
+		g.updateBullets()
+		// 倒した敵をまとめて取り除く
+		g.sweepDead()
 
 		// 敵弾の移動・当たり判定
+		hx, hy, hw, hh := g.playerHitbox()
 		newEnemyBullets := g.enemyBullets[:0]
+		bulletScale := g.enemyBulletTimeScale()
+		if g.grazeCooldown > 0 {
+			g.grazeCooldown--
+		}
 		for _, eb := range g.enemyBullets {
-			eb.x += eb.vx
-			eb.y += eb.vy
-			// プレイヤーとの当たり判定
-			if eb.x < g.playerX+20 && eb.x+4 > g.playerX && eb.y < g.playerY+24 && eb.y+8 > g.playerY {
-				g.createExplosion(g.playerX+10, g.playerY+12, color.RGBA{0, 255, 0, 255})
-				g.gameState = GameStatePlayerExplosion
-				g.playerExplosionTimer = 0
+			eb.trail.push(eb.x, eb.y)
+			eb.x += eb.vx * bulletScale
+			eb.y += eb.vy * bulletScale
+			// プレイヤーとの当たり判定（無敵中はすり抜ける）
+			if g.invincibleTimer == 0 && overlaps(eb.x, eb.y, 4, 8, hx, hy, hw, hh) {
+				g.killPlayer()
 				break
 			}
+			g.checkGraze(&eb, hx, hy, hw, hh)
 			// 画面内に残す
-			if eb.y < screenHeight+8 && eb.x > -8 && eb.x < screenWidth+8 {
+			if eb.y < playArea.height+8 && eb.x > -8 && eb.x < playArea.width+8 {
 				newEnemyBullets = append(newEnemyBullets, eb)
 			}
 		}
@@ -591,330 +753,397 @@ func (g *Game) Update() error {
 
 		// プレイヤーと敵の当たり判定
 		for _, e := range g.enemies {
-			// 敵のサイズを考慮した当たり判定
-			var enemyWidth, enemyHeight float64 = 20, 20
-			if e.enemyType == EnemyTypeBoss {
-				enemyWidth, enemyHeight = 60, 40
+			if e.dead {
+				continue
 			}
+			// 敵のサイズを考慮した当たり判定
+			enemyWidth, enemyHeight := enemySize(e.enemyType)
 
-			if g.playerX < e.x+enemyWidth && g.playerX+20 > e.x &&
-				g.playerY < e.y+enemyHeight && g.playerY+24 > e.y {
-				if g.score > g.highScore {
-					g.highScore = g.score
-				}
-				// プレイヤーの爆発エフェクト
-				g.createExplosion(g.playerX+10, g.playerY+12, color.RGBA{0, 255, 0, 255})
-				g.gameState = GameStatePlayerExplosion
-				g.playerExplosionTimer = 0
+			if g.invincibleTimer == 0 && overlaps(hx, hy, hw, hh, e.x, e.y, enemyWidth, enemyHeight) {
+				g.killPlayer()
 				break
 			}
 		}
 
 	case GameStatePlayerExplosion:
+		if !step {
+			break
+		}
 		g.playerExplosionTimer++
+		g.updateTimeAttack()
+		g.updateCaravan()
+		g.updateDaily()
+		g.updateStageTimer()
 		if g.playerExplosionTimer > 60 {
-			g.gameState = GameStateGameOver
+			// 残機があれば復活、なければゲームオーバー
+			if g.lives > 0 || g.infiniteLives() {
+				g.respawnPlayer()
+			} else if g.practice != nil {
+				g.endBossPractice()
+			} else if g.timeAttack != nil {
+				g.endTimeAttack()
+			} else if g.daily != nil {
+				g.finishDaily(false)
+			} else {
+				slog.Info("ゲームオーバー", "stage", g.currentStage+1, "score", g.score)
+				g.startTransition(TransitionFade, func() {
+					g.gameState = GameStateGameOver
+					saveStats()
+				}, nil)
+			}
 		}
 
 	case GameStateStageClear:
 		g.stageClearTimer++
-		// 1秒経過後、スペースキーが一度離されてから押された場合のみ進行
+		tallied := g.updateTally()
+		// 1秒経過後、スペースキーが一度離されてから押された場合のみ進行（集計の途中なら集計を飛ばす）
 		if g.stageClearTimer > 60 {
-			if !ebiten.IsKeyPressed(ebiten.KeySpace) {
+			if !g.input.Pressed(ebiten.KeySpace) {
 				g.stageClearKeyReleased = true
 			}
-			if g.stageClearKeyReleased && ebiten.IsKeyPressed(ebiten.KeySpace) {
-				g.currentStage++
-				if g.currentStage >= len(stages) {
-					g.gameState = GameStateGameOver
-					if g.score > g.highScore {
-						g.highScore = g.score
-					}
-				} else {
-					g.waves = stages[g.currentStage].Waves
-					g.currentSpawn = 0
-					g.waveTimer = 0
-					g.enemies = []Enemy{}
-					g.bullets = []Bullet{}
-					g.enemyBullets = []EnemyBullet{}
-					g.gameState = GameStatePlaying
+			if g.stageClearKeyReleased && g.input.Pressed(ebiten.KeySpace) {
+				g.stageClearKeyReleased = false
+				if !g.skipTally() {
+					g.progression().AdvanceStage()
 				}
 				return nil
 			}
 		}
-		// 2秒経過で自動進行
-		if g.stageClearTimer > 120 {
-			g.currentStage++
-			if g.currentStage >= len(stages) {
-				g.gameState = GameStateGameOver
-				if g.score > g.highScore {
-					g.highScore = g.score
-				}
-			} else {
-				g.waves = stages[g.currentStage].Waves
-				g.currentSpawn = 0
-				g.waveTimer = 0
-				g.enemies = []Enemy{}
-				g.bullets = []Bullet{}
-				g.enemyBullets = []EnemyBullet{}
-				g.gameState = GameStatePlaying
-			}
+		// 2秒経過し、集計を出し終えたら自動進行
+		if g.stageClearTimer > 120 && tallied {
+			g.progression().AdvanceStage()
 		}
 
 	case GameStateGameOver:
 		// 敵の移動処理（ゲームオーバー時も継続）
-		for i := range g.enemies {
-			e := &g.enemies[i]
-			e.time += 0.05
-
-			switch e.enemyType {
-			case EnemyTypeStraight:
-				e.y += e.speed
-			case EnemyTypeSine:
-				e.y += e.speed
-				e.x += math.Sin(e.time) * 3
-			case EnemyTypeSpecial:
-				switch e.phase {
-				case 0: // 上昇
-					e.y += e.speed
-					if e.y > screenHeight/2 {
-						e.phase = 1
-					}
-				case 1: // 横移動
-					e.x += e.speed
-					if e.x > screenWidth-40 {
-						e.phase = 2
-					}
-				case 2: // 下降
-					e.y += e.speed
-				}
+		if step {
+			for i := range g.enemies {
+				e := &g.enemies[i]
+				e.time += 0.05
+				g.moveEnemy(e)
 			}
-		}
 
-		// 画面外に出た敵を削除
-		newEnemies := g.enemies[:0]
-		for _, e := range g.enemies {
-			if e.y < screenHeight+20 {
-				newEnemies = append(newEnemies, e)
+			// 画面外に出た敵を削除
+			newEnemies := g.enemies[:0]
+			for _, e := range g.enemies {
+				if e.y < playArea.height+20 {
+					newEnemies = append(newEnemies, e)
+				}
 			}
+			g.enemies = newEnemies
 		}
-		g.enemies = newEnemies
 
 		// Rキーでリスタート
-		if ebiten.IsKeyPressed(ebiten.KeyR) {
+		if g.input.Pressed(ebiten.KeyR) {
+			ship, input := g.selectedShip, g.input
 			*g = *NewGame()
+			g.selectedShip, g.input = ship, input
 			g.gameState = GameStatePlaying
+			g.startStageIntro()
+			g.emit(Event{Kind: EventGameStarted})
+			g.sound.PlayBGM("stage")
 		}
 	}
 
 	return nil
 }
 
-// Draw はゲームの描画を行います
-func (g *Game) Draw(screen *ebiten.Image) {
-	// 背景の星を描画（どの状態でも表示）
-	for _, s := range g.stars {
-		ebitenutil.DrawLine(screen, s.x, s.y, s.x, s.y+s.length, s.color)
+// drawScreen は内部解像度の画面にゲームを描画します
+func (g *Game) drawScreen(screen *ebiten.Image) {
+	// 星や敵などプレイエリア内のものはフィールド用の画像に描いてから画面に転写する
+	field := g.fieldImage()
+	field.Clear()
+
+	// 背景を描画（どの状態でも表示）
+	g.drawBackground(field)
+
+	if g.gameState == GameStatePlaying || g.gameState == GameStatePlayerExplosion {
+		g.drawField(field)
+	} else if g.gameState == GameStateTitle {
+		g.drawTitleDemo(field)
 	}
 
+	op := &ebiten.DrawImageOptions{}
+	shakeX, shakeY := g.shakeOffset()
+	op.GeoM.Translate(playArea.x+shakeX, playArea.y+shakeY)
+	screen.DrawImage(field, op)
+	playArea.drawPanels(screen)
+
 	switch g.gameState {
 	case GameStateTitle:
 		// タイトル画面
-		titleText := "SIMPLE SHOOTING STAR"
-		startText := "Press SPACE to Start"
-		highScoreText := fmt.Sprintf("High Score: %d", g.highScore)
+		titleText := i18n.T("title.name")
+		startText := i18n.T("title.start")
+		highScoreText := i18n.Tf("common.highScore", g.highScore)
+		statsText := i18n.T("title.stats") + "  " + i18n.T("title.tutorial")
+
+		hud.DrawTextOutline(screen, titleText, fonts.Face(fonts.Large), resolution.Width/2, resolution.Height/3, hud.AlignCenter, color.White, hud.OutlineColor)
+		hud.DrawTextShadow(screen, startText, fonts.Face(fonts.Medium), resolution.Width/2, resolution.Height/2, hud.AlignCenter, color.White)
+		hud.DrawTextShadow(screen, highScoreText, fonts.Face(fonts.Medium), resolution.Width/2, resolution.Height*2/3, hud.AlignCenter, color.White)
+		hud.DrawTextShadow(screen, statsText, fonts.Face(fonts.Small), resolution.Width/2, resolution.Height*5/6, hud.AlignCenter, color.White)
+		modeText := i18n.T("title.caravan") + "  " + i18n.T("title.daily") + "  " + i18n.T("title.timeAttack")
+		if len(practiceStages()) > 0 {
+			modeText += "  " + i18n.T("title.practice")
+		}
+		hud.DrawTextShadow(screen, modeText, fonts.Face(fonts.Small), resolution.Width/2, resolution.Height*5/6-ui(24), hud.AlignCenter, color.White)
+		if len(stagePacks) > 1 {
+			packText := i18n.Tf("title.pack", stagePacks[currentPack].Name)
+			hud.DrawTextShadow(screen, packText, fonts.Face(fonts.Small), resolution.Width/2, resolution.Height/2+ui(28), hud.AlignCenter, color.White)
+		}
+		if suspended != nil {
+			resumeText := i18n.Tf("title.resume", suspended.Stage+1)
+			hud.DrawTextShadow(screen, resumeText, fonts.Face(fonts.Small), resolution.Width/2, resolution.Height*5/6+ui(24), hud.AlignCenter, color.RGBA{255, 255, 0, 255})
+		}
 
-		text.Draw(screen, titleText, gameFont, (screenWidth-len(titleText)*6)/2, screenHeight/3, color.White)
-		text.Draw(screen, startText, gameFont, (screenWidth-len(startText)*6)/2, screenHeight/2, color.White)
-		text.Draw(screen, highScoreText, gameFont, (screenWidth-len(highScoreText)*6)/2, screenHeight*2/3, color.White)
+	case GameStateShipSelect:
+		g.drawShipSelect(screen)
 
-	case GameStatePlaying:
-		// スコアとステージ表示
-		scoreText := fmt.Sprintf("Score: %d", g.score)
-		stageText := fmt.Sprintf("Stage: %s", stages[g.currentStage].Name)
-		text.Draw(screen, scoreText, gameFont, 0, int(20*1.2), color.White)
-		text.Draw(screen, stageText, gameFont, 0, int(20*2.0), color.White)
+	case GameStateStats:
+		g.drawStats(screen)
 
-		// 敵を描画
-		for _, e := range g.enemies {
-			var enemyColor color.RGBA
-			var enemyWidth, enemyHeight float64 = 20, 20
-
-			switch e.enemyType {
-			case EnemyTypeStraight:
-				enemyColor = color.RGBA{255, 0, 0, 255}
-			case EnemyTypeSine:
-				enemyColor = color.RGBA{255, 165, 0, 255}
-			case EnemyTypeSpecial:
-				enemyColor = color.RGBA{255, 0, 255, 255}
-			case EnemyTypeBoss:
-				enemyColor = color.RGBA{200, 0, 0, 255} // ダークレッド
-				enemyWidth, enemyHeight = 60, 40        // ボスは大きく
-
-				// ボスの攻撃準備状態で点滅効果
-				if e.bossState == 1 && e.bossTimer%10 < 5 {
-					enemyColor = color.RGBA{255, 255, 255, 255}
-				}
-			}
+	case GameStateBossSelect:
+		g.drawBossSelect(screen)
 
-			ebitenutil.DrawRect(screen, e.x, e.y, enemyWidth, enemyHeight, enemyColor)
+	case GameStateTimeAttackSelect:
+		g.drawTimeAttackSelect(screen)
 
-			// HPバーを表示
-			var hpBarWidth float64
-			if e.enemyType == EnemyTypeBoss {
-				hpBarWidth = float64(e.hp) * 1.0 // ボス用のHPバー
-			} else {
-				hpBarWidth = float64(e.hp) * 5
-			}
-			ebitenutil.DrawRect(screen, e.x, e.y-8, hpBarWidth, 4, color.RGBA{0, 255, 0, 255})
-		}
+	case GameStateCaravanResult:
+		g.drawCaravanResult(screen)
 
-		// 自機を描画
-		ebitenutil.DrawRect(screen, g.playerX, g.playerY, 4, 16, color.RGBA{0, 255, 0, 255})
-		ebitenutil.DrawRect(screen, g.playerX+8, g.playerY-8, 4, 24, color.RGBA{0, 255, 0, 255})
-		ebitenutil.DrawRect(screen, g.playerX+16, g.playerY, 4, 16, color.RGBA{0, 255, 0, 255})
+	case GameStateStagePackSelect:
+		g.drawStagePackSelect(screen)
 
-		// 自機弾の描画
-		for _, b := range g.bullets {
-			ebitenutil.DrawRect(screen, b.x, b.y, 4, 8, color.RGBA{255, 255, 0, 255})
-		}
+	case GameStateDailyResult:
+		g.drawDailyResult(screen)
 
-		// 敵弾の描画（追加）
-		for _, eb := range g.enemyBullets {
-			ebitenutil.DrawRect(screen, eb.x, eb.y, 6, 12, color.RGBA{255, 0, 0, 255})
-		}
+	case GameStatePlaying:
+		// スコアやステージなどのHUD表示
+		gameHUD.Draw(screen, g.hudState())
+		g.drawBossPracticeTimer(screen)
+		g.drawTimeAttackTimer(screen)
+		g.drawCaravanTimer(screen)
+		g.drawTutorial(screen)
 
-		// パーティクルを描画
-		for _, p := range g.particles {
-			if p.ptype == 1 {
-				norm := math.Hypot(p.vx, p.vy)
-				if norm == 0 {
-					norm = 1
-				}
-				length := 1000.0 // 画面端まで
-				dx := p.vx / norm * length
-				dy := p.vy / norm * length
-				ebitenutil.DrawLine(screen, p.x, p.y, p.x+dx, p.y+dy, color.RGBA{255, 255, 0, uint8(p.alpha * 255)})
-			} else {
-				alpha := uint8(p.alpha * 255)
-				ebitenutil.DrawRect(screen, p.x, p.y, p.size, p.size, color.RGBA{255, 255, 255, alpha})
-			}
+	case GameStateStageClear:
+		clearText := i18n.T("stageClear.title")
+		nextText := i18n.T("stageClear.next")
+		if g.practice != nil {
+			clearText = i18n.Tf("practice.clear", formatFrames(g.practice.timer))
+			nextText = i18n.T("practice.next")
 		}
+		if g.timeAttack != nil {
+			clearText = i18n.Tf("timeAttack.clear", formatMillis(g.timeAttack.frames))
+			nextText = i18n.T("timeAttack.next")
+			g.drawTimeAttackResult(screen)
+		}
+		g.drawStageGrade(screen)
+		g.drawTally(screen)
+		hud.DrawTextOutline(screen, clearText, fonts.Face(fonts.Large), resolution.Width/2, resolution.Height/2-ui(20), hud.AlignCenter, color.White, hud.OutlineColor)
+		hud.DrawTextShadow(screen, nextText, fonts.Face(fonts.Small), resolution.Width/2, resolution.Height/2+ui(20), hud.AlignCenter, color.White)
 
-	case GameStatePlayerExplosion:
-		// 敵を描画
-		for _, e := range g.enemies {
-			var enemyColor color.RGBA
-			var enemyWidth, enemyHeight float64 = 20, 20
-
-			switch e.enemyType {
-			case EnemyTypeStraight:
-				enemyColor = color.RGBA{255, 0, 0, 255}
-			case EnemyTypeSine:
-				enemyColor = color.RGBA{255, 165, 0, 255}
-			case EnemyTypeSpecial:
-				enemyColor = color.RGBA{255, 0, 255, 255}
-			case EnemyTypeBoss:
-				enemyColor = color.RGBA{200, 0, 0, 255} // ダークレッド
-				enemyWidth, enemyHeight = 60, 40        // ボスは大きく
-
-				// ボスの攻撃準備状態で点滅効果
-				if e.bossState == 1 && e.bossTimer%10 < 5 {
-					enemyColor = color.RGBA{255, 255, 255, 255}
-				}
-			}
+	case GameStateGameOver:
+		// ゲームオーバー画面
+		gameOverText := i18n.T("gameOver.title")
+		scoreText := i18n.Tf("gameOver.score", g.score)
+		highScoreText := i18n.Tf("common.highScore", g.highScore)
+		restartText := i18n.T("gameOver.restart")
+
+		hud.DrawTextOutline(screen, gameOverText, fonts.Face(fonts.Large), resolution.Width/2, resolution.Height/3, hud.AlignCenter, color.White, hud.OutlineColor)
+		hud.DrawTextShadow(screen, scoreText, fonts.Face(fonts.Medium), 0, ui(24), hud.AlignLeft, color.White)
+		hud.DrawTextShadow(screen, highScoreText, fonts.Face(fonts.Medium), resolution.Width/2, resolution.Height*2/3-ui(20), hud.AlignCenter, color.White)
+		hud.DrawTextShadow(screen, restartText, fonts.Face(fonts.Medium), resolution.Width/2, resolution.Height*2/3+ui(20), hud.AlignCenter, color.White)
+	}
 
-			ebitenutil.DrawRect(screen, e.x, e.y, enemyWidth, enemyHeight, enemyColor)
+	// 画面切り替えの演出を最前面に描画
+	g.drawTransition(screen)
+
+	// GIFクリップ用に画面を取り込む（デバッグ表示は含めない）
+	clipRecorder.Capture(screen)
+	captureScreenshot(screen)
+	g.drawDebug(screen)
+	g.drawDebugControls(screen)
+	g.drawCheats(screen)
+	g.drawVolumeIndicator(screen)
+	drawFPS(screen)
+	g.drawConsole(screen)
+}
 
-			// HPバーを表示
-			var hpBarWidth float64
-			if e.enemyType == EnemyTypeBoss {
-				hpBarWidth = float64(e.hp) * 1.0 // ボス用のHPバー
-			} else {
-				hpBarWidth = float64(e.hp) * 5
-			}
-			ebitenutil.DrawRect(screen, e.x, e.y-8, hpBarWidth, 4, color.RGBA{0, 255, 0, 255})
-		}
+// drawField はプレイエリア内の敵・自機・弾・パーティクルを描画します
+func (g *Game) drawField(field *ebiten.Image) {
+	// 敵を描画
+	g.drawEnemies(field)
 
-		// 弾を描画
-		for _, eb := range g.enemyBullets {
-			ebitenutil.DrawRect(screen, eb.x, eb.y, 6, 12, color.RGBA{255, 128, 128, 255})
-		}
+	// 機雷・ビームを描画
+	g.drawMines(field)
+	g.drawBeams(field)
+	g.drawTokens(field)
+	g.drawMagnets(field)
+	g.drawScoreItems(field)
 
-		// パーティクルを描画
-		for _, p := range g.particles {
-			if p.ptype == 1 {
-				norm := math.Hypot(p.vx, p.vy)
-				if norm == 0 {
-					norm = 1
-				}
-				length := 1000.0 // 画面端まで
-				dx := p.vx / norm * length
-				dy := p.vy / norm * length
-				ebitenutil.DrawLine(screen, p.x, p.y, p.x+dx, p.y+dy, color.RGBA{255, 255, 0, uint8(p.alpha * 255)})
-			} else {
-				alpha := uint8(p.alpha * 255)
-				ebitenutil.DrawRect(screen, p.x, p.y, p.size, p.size, color.RGBA{255, 255, 255, alpha})
-			}
+	// タイムアタックの自己ベストのゴーストと、噴射炎・発射炎は自機の下に描く
+	g.drawGhost(field)
+	g.effects.draw(field)
+
+	if g.gameState == GameStatePlaying && !g.demo {
+		// 自機を描画
+		g.drawPlayer(field)
+
+		// 自機弾の描画
+		for _, b := range g.bullets {
+			ebitenutil.DrawRect(field, b.x, b.y, 4, 8, color.RGBA{255, 255, 0, 255})
 		}
+	}
 
-	case GameStateStageClear:
-		clearText := "STAGE CLEAR!"
-		nextText := "Press SPACE or wait for next stage"
-		text.Draw(screen, clearText, gameFont, (screenWidth-len(clearText)*6)/2, screenHeight/2-20, color.White)
-		text.Draw(screen, nextText, gameFont, (screenWidth-len(nextText)*6)/2, screenHeight/2+20, color.White)
+	// 敵弾の描画（速さで色と形を変え、自機の爆発中は淡く表示）
+	for _, eb := range g.enemyBullets {
+		drawEnemyBullet(field, eb, g.gameState == GameStatePlayerExplosion)
+	}
 
-	case GameStateGameOver:
-		// ゲームオーバー画面
-		gameOverText := "GAME OVER"
-		scoreText := fmt.Sprintf("Score: %d", g.score)
-		highScoreText := fmt.Sprintf("High Score: %d", g.highScore)
-		restartText := "Press R to Restart"
+	// パーティクルを描画
+	g.particles.draw(field)
+
+	g.drawFloatingTexts(field)
 
-		text.Draw(screen, gameOverText, gameFont, (screenWidth-len(gameOverText)*6)/2, screenHeight/3, color.White)
-		text.Draw(screen, scoreText, gameFont, 0, int(20*1.2), color.White)
-		text.Draw(screen, highScoreText, gameFont, (screenWidth-len(highScoreText)*6)/2, screenHeight*2/3-20, color.White)
-		text.Draw(screen, restartText, gameFont, (screenWidth-len(restartText)*6)/2, screenHeight*2/3+20, color.White)
+	if g.gameState == GameStatePlaying && !g.demo {
+		// ボムの光と、ステージ開始のバナーや警告などのオーバーレイを最前面に描画
+		g.drawBombEffects(field)
+		g.drawStageIntro(field)
+		g.drawOverlays(field)
 	}
 }
 
 // Layout はゲームのレイアウトを設定します
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return screenWidth, screenHeight
+	return layoutSize(outsideWidth, outsideHeight)
 }
 
-func loadFont() font.Face {
-	fontBytes, err := os.ReadFile("assets/NotoSansJP-Regular.ttf")
-	if err != nil {
-		panic(err)
+func main() {
+	simFrames := flag.Int("sim", 0, "ウィンドウを開かずに指定フレーム数だけシミュレーションし、結果を表示する")
+	simStage := flag.Int("sim-stage", 1, "シミュレーションするステージ（1始まり）")
+	simShip := flag.Int("sim-ship", 0, "シミュレーションで使う自機（0始まり）")
+	simSeed := flag.Int64("sim-seed", 1, "シミュレーションの乱数の種")
+	simInput := flag.String("sim-input", "random", "シミュレーションの操作（random または idle）")
+	simInvincible := flag.Bool("sim-invincible", false, "シミュレーション中の自機を無敵にする")
+	tps := flag.Int("tps", baseTPS, "1秒あたりのゲームの処理回数（30で半分の速さ、240で4倍速）")
+	flag.BoolVar(&devMode, "dev", false, "無敵・ステージジャンプ・ウェーブ出現などの開発者向けチートを有効にする")
+	flag.BoolVar(&debugMode, "debug", false, "早送り(F5)・一時停止(F6)・コマ送り(F7)のデバッグ操作を有効にする")
+	logLevel := flag.String("log-level", "info", "ログに出す最低のレベル（debug・info・warn・error）")
+	logFile := flag.String("log-file", "", "ログを追記するファイル（不具合の報告に添付できる）")
+	pprofAddr := flag.String("pprof", "", "pprofのHTTPエンドポイントを開くアドレス（例: localhost:6060）。F10でCPU、F11でヒープのプロファイルを保存できる")
+	bench := flag.Bool("bench", false, "敵・敵弾・パーティクルを段階的に増やし、段階ごとの更新と描画の時間を測って終了する")
+	flag.Parse()
+	if *tps <= 0 {
+		panic(fmt.Sprintf("tpsの値が不正です: %d", *tps))
 	}
-	ttf, err := opentype.Parse(fontBytes)
+	logCloser, err := setupLogging(*logLevel, *logFile)
 	if err != nil {
 		panic(err)
 	}
-	const fontSize = 20.0 // 1.5倍相当のサイズ
-	face, err := opentype.NewFace(ttf, &opentype.FaceOptions{
-		Size:    fontSize,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
-	if err != nil {
-		panic(err)
+	defer logCloser.Close()
+	logicTPS = *tps
+	if *pprofAddr != "" {
+		startPprofServer(*pprofAddr)
+	}
+	// シミュレーションではエラー画面を開かずに終了する
+	headless = *simFrames > 0
+
+	// 必要なファイルが読み込めなければ、パニックせずにエラー画面で知らせる
+	// 設定の読み込み
+	if err := loadSettings(); err != nil {
+		showStartupError(settingsFile, err)
+	}
+	playArea = newPlayArea(settings.PlayArea)
+	// ゲームバランスの調整値の読み込み（ファイルがなければ組み込みの値を使う）
+	if err := loadTuning(); err != nil {
+		showStartupError(tuningFile, err)
+	}
+
+	// パーティクルの出し方の読み込み
+	if err := loadEmitters(); err != nil {
+		showStartupError(effectsFile, err)
+	}
+
+	// 表示言語の文字列テーブルの読み込み
+	if err := i18n.Load("lang", settings.Language); err != nil {
+		showStartupError(filepath.Join("lang", settings.Language+".json"), err)
 	}
-	return face
-}
 
-func main() {
 	// ステージ情報の読み込み
 	if err := loadStages(); err != nil {
-		panic(err)
+		showStartupError(stageFile, err)
+	}
+	if err := loadCaravan(); err != nil {
+		showStartupError(caravanFile, err)
+	}
+	// セーブデータ（通算の統計と、ステージデータごとの記録）の読み込み
+	if err := loadSaveData(); err != nil {
+		showStartupError(saveFile, err)
+	}
+	// 自機情報の読み込み
+	if err := loadShips(); err != nil {
+		showStartupError(shipFile, err)
+	}
+	// 中断セーブは壊れていても捨てるだけでゲームは起動する
+	if err := loadSuspendData(); err != nil {
+		slog.Warn("中断セーブを読み飛ばします", "file", suspendFile, "err", err)
 	}
 
-	gameFont = loadFont()
-	ebiten.SetWindowSize(screenWidth, screenHeight)
-	ebiten.SetWindowTitle("Simple Game")
+	// シミュレーションモード：ウィンドウを開かずに結果だけを表示して終了
+	if *simFrames > 0 {
+		report, err := runSimulation(SimConfig{
+			Frames:     *simFrames,
+			Stage:      *simStage - 1,
+			Ship:       *simShip,
+			Seed:       *simSeed,
+			InputMode:  *simInput,
+			Invincible: *simInvincible,
+		})
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(report)
+		return
+	}
 
-	if err := ebiten.RunGame(NewGame()); err != nil {
+	// フォントの読み込み（読み込めなければ英数字だけの組み込みフォントで続ける）
+	if err := fonts.Load(fontFile); err != nil {
+		slog.Warn("組み込みのフォントで起動します", "file", fontFile, "err", err)
+		fonts.LoadFallback()
+	}
+	// どの画面の文字も内部解像度に合わせた同じ倍率で描く
+	if err := fonts.SetScale(uiScale()); err != nil {
+		slog.Warn("等倍のフォントで起動します", "err", err)
+	}
+	slog.Info("起動しました", "resolution", fmt.Sprintf("%dx%d", resolution.Width, resolution.Height), "language", settings.Language, "tps", logicTPS)
+	layout, err := newHUDLayout()
+	if err != nil {
+		showStartupError(settingsFile, err)
+	}
+	gameHUD = hud.New(fonts.Face(fonts.Medium), layout)
+	// 後処理のシェーダーが使えなくても後処理なしで起動する
+	if err := loadCRTShader(); err != nil {
+		slog.Warn("後処理なしで起動します", "file", crtShaderFile, "err", err)
+	}
+	ebiten.SetWindowSize(displaySize())
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	setupWindow()
+	if *bench {
+		// 実際にかかった時間を測れるように、垂直同期とフレームレートの上限を外す
+		settings.VSync, settings.FPSCap = false, 0
+	}
+	applyFrameSettings()
+	ebiten.SetWindowClosingHandled(true)
+	// フォーカスを失ってもUpdateを呼ばせ、ゲームと一緒に音を止める
+	ebiten.SetRunnableOnUnfocused(true)
+
+	var game *Game
+	if *bench {
+		game = newSilentGame()
+		game.startBench()
+	} else {
+		game = NewGame()
+	}
+	if err := runGame(game); err != nil {
 		panic(err)
 	}
 }
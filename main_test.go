@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"testing"
+
+	"SimpleShootingStar/i18n"
+)
+
+// TestMain はゲームと同じデータを読み込み、音も保存もしない状態でテストを動かします
+func TestMain(m *testing.M) {
+	headless = true
+	if err := loadTestData(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+// loadTestData はテストで使う調整値・パーティクル・言語・ステージ・自機を読み込みます
+func loadTestData() error {
+	if err := loadTuning(); err != nil {
+		return err
+	}
+	if err := loadEmitters(); err != nil {
+		return err
+	}
+	if err := i18n.Load("lang", i18n.DefaultLanguage); err != nil {
+		return err
+	}
+	if err := loadStages(); err != nil {
+		return err
+	}
+	return loadShips()
+}
+
+// newTestGame は何も操作しない入力と無音の出力を持つ、敵もウェーブもないプレイ中のゲームを作ります
+func newTestGame(t *testing.T) *Game {
+	t.Helper()
+	g := newSilentGame()
+	g.input = newSimInput(rand.New(rand.NewSource(1)), false)
+	g.gameState = GameStatePlaying
+	g.enemies = []Enemy{}
+	g.setWaves(nil)
+	return g
+}
+
+func TestEnemyKillPoints(t *testing.T) {
+	tests := []struct {
+		name       string
+		enemyType  int
+		mount      *Mount
+		multiplier int
+		want       int
+	}{
+		{"straight", EnemyTypeStraight, nil, 1, 100},
+		{"sine with multiplier", EnemyTypeSine, nil, 3, 300},
+		{"carrier bonus", EnemyTypeCarrier, nil, 1, 100 + carrierBonus},
+		{"boss", EnemyTypeBoss, nil, 1, 1000},
+		{"mounted turret", EnemyTypeTurret, &Mount{score: 50}, 2, 100},
+		{"turret without mount", EnemyTypeTurret, nil, 1, turretScore},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(t)
+			g.multiplier = tt.multiplier
+			e := g.newEnemy(tt.enemyType, 100, 100, 2)
+			e.mount = tt.mount
+			before := g.score
+			g.onEnemyKilled(e)
+			if got := g.score - before; got != tt.want {
+				t.Errorf("points = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComboCountsConsecutiveKills(t *testing.T) {
+	tests := []struct {
+		kills int
+		want  int
+	}{
+		{1, 1},
+		{2, 2},
+		{5, 5},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.kills), func(t *testing.T) {
+			g := newTestGame(t)
+			for i := 0; i < tt.kills; i++ {
+				g.onEnemyKilled(g.newEnemy(EnemyTypeStraight, 100, 100, 2))
+			}
+			if g.combo != tt.want {
+				t.Errorf("combo = %d, want %d", g.combo, tt.want)
+			}
+			if g.comboTimer != comboWindow {
+				t.Errorf("comboTimer = %d, want %d", g.comboTimer, comboWindow)
+			}
+		})
+	}
+}
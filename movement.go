@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// 自機の操作感（ship/ships.jsonのhandling）
+const (
+	HandlingInstant  = "instant"  // キーを押した瞬間に最高速で動き、離すとすぐ止まる（省略時）
+	HandlingInertial = "inertial" // accelで加速・decelで減速し、慣性が残る
+)
+
+// validateHandling は自機の操作感の設定を確かめます
+func validateHandling(s Ship) error {
+	switch s.Handling {
+	case "", HandlingInstant:
+	case HandlingInertial:
+		if s.Accel <= 0 || s.Decel <= 0 {
+			return fmt.Errorf("%s: inertialの自機はaccelとdecelを0より大きくしてください", s.Name)
+		}
+	default:
+		return fmt.Errorf("%s: 不明なhandlingです: %s", s.Name, s.Handling)
+	}
+	return nil
+}
+
+// inputDirection は方向キーの入力を長さ1以下の向きにします。
+// 斜めに入れても縦横と同じ速さになるよう正規化します
+func (g *Game) inputDirection() (dx, dy float64) {
+	if g.input.Pressed(ebiten.KeyLeft) {
+		dx--
+	}
+	if g.input.Pressed(ebiten.KeyRight) {
+		dx++
+	}
+	if g.input.Pressed(ebiten.KeyUp) {
+		dy--
+	}
+	if g.input.Pressed(ebiten.KeyDown) {
+		dy++
+	}
+	if l := math.Hypot(dx, dy); l > 1 {
+		dx, dy = dx/l, dy/l
+	}
+	return dx, dy
+}
+
+// movePlayer は入力に合わせて自機を動かします。操作感がinertialなら目標の速度へ
+// 少しずつ近づけ、画面端で止まった向きの速度は捨てます
+func (g *Game) movePlayer() {
+	dx, dy := g.inputDirection()
+	speed := g.moveSpeed()
+	s := g.ship()
+	if s.Handling == HandlingInertial {
+		g.playerVX = approachVelocity(g.playerVX, dx*speed, s.Accel, s.Decel)
+		g.playerVY = approachVelocity(g.playerVY, dy*speed, s.Accel, s.Decel)
+	} else {
+		g.playerVX, g.playerVY = dx*speed, dy*speed
+	}
+
+	// 左右の端で止めるか反対側へ回り込ませる（プレイエリアの設定による）
+	x := g.playerX + g.playerVX
+	g.playerX = playArea.constrainPlayerX(x)
+	if !playArea.wrap && g.playerX != x {
+		g.playerVX = 0
+	}
+	y := g.playerY + g.playerVY
+	g.playerY = min(max(y, 40), playArea.height-20)
+	if g.playerY != y {
+		g.playerVY = 0
+	}
+}
+
+// stopPlayer は自機に残っている慣性をなくします
+func (g *Game) stopPlayer() {
+	g.playerVX, g.playerVY = 0, 0
+}
+
+// approachVelocity は速度vを目標targetへ近づけます。
+// 同じ向きにさらに速くするときはaccel、それ以外（減速・切り返し）はdecelずつ変えます
+func approachVelocity(v, target, accel, decel float64) float64 {
+	rate := decel
+	if v*target >= 0 && math.Abs(target) > math.Abs(v) {
+		rate = accel
+	}
+	if v < target {
+		return math.Min(v+rate, target)
+	}
+	return math.Max(v-rate, target)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"image/color"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// Overlay は一定時間だけ画面に重ねて表示するUI要素です
+type Overlay struct {
+	text     string
+	y        int        // テキストのベースライン位置
+	timer    int        // 残り表示フレーム数
+	color    color.RGBA // テキストの色
+	band     color.RGBA // 背景の帯の色（アルファ0なら帯なし）
+	flashing bool       // 点滅させるかどうか
+}
+
+// addOverlay はオーバーレイを追加します
+func (g *Game) addOverlay(o Overlay) {
+	g.overlays = append(g.overlays, o)
+}
+
+// updateOverlays は表示時間の切れたオーバーレイを取り除きます
+func (g *Game) updateOverlays() {
+	newOverlays := g.overlays[:0]
+	for _, o := range g.overlays {
+		o.timer--
+		if o.timer > 0 {
+			newOverlays = append(newOverlays, o)
+		}
+	}
+	g.overlays = newOverlays
+}
+
+// drawOverlays はオーバーレイを描画します
+func (g *Game) drawOverlays(screen *ebiten.Image) {
+	for _, o := range g.overlays {
+		// 点滅は15フレームごとに表示・非表示を切り替える
+		if o.flashing && o.timer%30 < 15 {
+			continue
+		}
+		if o.band.A > 0 {
+			ebitenutil.DrawRect(screen, 0, float64(o.y-ui(30)), playArea.width, float64(ui(44)), o.band)
+		}
+		hud.DrawTextOutline(screen, o.text, fonts.Face(fonts.Large), int(playArea.width)/2, o.y, hud.AlignCenter, o.color, hud.OutlineColor)
+	}
+}
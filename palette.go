@@ -0,0 +1,133 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// パレットの名前（settings.jsonのpaletteに指定する）
+const (
+	PaletteStandard   = "standard"   // 既定の配色
+	PaletteRedGreen   = "redGreen"   // 赤と緑を見分けにくい人向け（1型・2型色覚）
+	PaletteBlueYellow = "blueYellow" // 青と黄を見分けにくい人向け（3型色覚）
+)
+
+// 敵弾の危険度の段階。速い弾ほど危険度が高く、色と形を変えて見分けやすくします
+const (
+	bulletTierSlow   = iota // 遅い弾：太い四角
+	bulletTierMedium        // 普通の弾：縦長の四角
+	bulletTierFast          // 速い弾：細長い針
+	bulletTierCount
+)
+
+// 敵弾の危険度を分ける速さ（1フレームあたりのピクセル数）
+const (
+	bulletTierMediumSpeed = 3.0
+	bulletTierFastSpeed   = 5.0
+)
+
+// Palette は敵と敵弾の配色です
+type Palette struct {
+	Enemies map[int]color.RGBA          // 敵の種類ごとの色
+	Bullets [bulletTierCount]color.RGBA // 危険度ごとの敵弾の色
+	Core    color.RGBA                  // 敵弾の芯の色（敵の色と混ざらないよう弾の中心に描く）
+}
+
+// palettes は選べるパレットの一覧です
+var palettes = map[string]Palette{
+	PaletteStandard: {
+		Enemies: map[int]color.RGBA{
+			EnemyTypeStraight: {255, 0, 0, 255},
+			EnemyTypeSine:     {255, 165, 0, 255},
+			EnemyTypeSpecial:  {255, 0, 255, 255},
+			EnemyTypeBoss:     {200, 0, 0, 255}, // ダークレッド
+			EnemyTypeMiner:    {0, 200, 255, 255},
+			EnemyTypeCarrier:  {140, 140, 170, 255},
+			EnemyTypeTurret:   {200, 200, 80, 255},
+		},
+		Bullets: [bulletTierCount]color.RGBA{{255, 110, 110, 255}, {255, 30, 30, 255}, {255, 0, 200, 255}},
+		Core:    color.RGBA{255, 255, 255, 255},
+	},
+	// 青・橙・黄・灰色を中心にし、赤と緑の組み合わせを避ける
+	PaletteRedGreen: {
+		Enemies: map[int]color.RGBA{
+			EnemyTypeStraight: {0, 114, 178, 255},
+			EnemyTypeSine:     {86, 180, 233, 255},
+			EnemyTypeSpecial:  {204, 121, 167, 255},
+			EnemyTypeBoss:     {213, 94, 0, 255},
+			EnemyTypeMiner:    {0, 158, 115, 255},
+			EnemyTypeCarrier:  {140, 140, 170, 255},
+			EnemyTypeTurret:   {170, 170, 170, 255},
+		},
+		Bullets: [bulletTierCount]color.RGBA{{240, 228, 66, 255}, {230, 159, 0, 255}, {255, 255, 255, 255}},
+		Core:    color.RGBA{0, 0, 0, 255},
+	},
+	// 赤・青緑・灰色を中心にし、青と黄の組み合わせを避ける
+	PaletteBlueYellow: {
+		Enemies: map[int]color.RGBA{
+			EnemyTypeStraight: {0, 150, 150, 255},
+			EnemyTypeSine:     {100, 200, 200, 255},
+			EnemyTypeSpecial:  {150, 150, 150, 255},
+			EnemyTypeBoss:     {180, 0, 60, 255},
+			EnemyTypeMiner:    {0, 110, 110, 255},
+			EnemyTypeCarrier:  {110, 110, 110, 255},
+			EnemyTypeTurret:   {200, 200, 200, 255},
+		},
+		Bullets: [bulletTierCount]color.RGBA{{255, 140, 160, 255}, {255, 40, 80, 255}, {255, 255, 255, 255}},
+		Core:    color.RGBA{0, 0, 0, 255},
+	},
+}
+
+// palette は今使っているパレットです
+var palette = palettes[PaletteStandard]
+
+// enemyColor は敵の種類に応じた色を返します
+func enemyColor(enemyType int) color.RGBA {
+	if c, ok := palette.Enemies[enemyType]; ok {
+		return c
+	}
+	return color.RGBA{255, 255, 255, 255}
+}
+
+// bulletTier は敵弾の速さから危険度の段階を返します
+func bulletTier(eb EnemyBullet) int {
+	speed := math.Hypot(eb.vx, eb.vy)
+	switch {
+	case speed >= bulletTierFastSpeed:
+		return bulletTierFast
+	case speed >= bulletTierMediumSpeed:
+		return bulletTierMedium
+	}
+	return bulletTierSlow
+}
+
+// drawEnemyBullet は敵弾を危険度に応じた色と形で描画します。faded なら淡く描きます
+func drawEnemyBullet(field *ebiten.Image, eb EnemyBullet, faded bool) {
+	tier := bulletTier(eb)
+	c, core := palette.Bullets[tier], palette.Core
+	if faded {
+		c, core = fadeColor(c), fadeColor(core)
+	}
+	drawBulletTrail(field, eb, c)
+	// 当たり判定の位置を変えないよう、これまでの6x12の弾の中心を基準に描く
+	cx, cy := eb.x+3, eb.y+6
+	switch tier {
+	case bulletTierSlow:
+		ebitenutil.DrawRect(field, cx-4, cy-4, 8, 8, c)
+		ebitenutil.DrawRect(field, cx-2, cy-2, 4, 4, core)
+	case bulletTierMedium:
+		ebitenutil.DrawRect(field, cx-3, cy-6, 6, 12, c)
+		ebitenutil.DrawRect(field, cx-1, cy-3, 2, 6, core)
+	case bulletTierFast:
+		ebitenutil.DrawRect(field, cx-2, cy-8, 4, 16, c)
+		ebitenutil.DrawRect(field, cx-1, cy-5, 2, 10, core)
+	}
+}
+
+// fadeColor は色を白に半分近づけて淡くします
+func fadeColor(c color.RGBA) color.RGBA {
+	return color.RGBA{c.R + (255-c.R)/2, c.G + (255-c.G)/2, c.B + (255-c.B)/2, c.A}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// 設定のmaxParticlesとして受け付ける範囲
+const (
+	minMaxParticles = 100
+	maxMaxParticles = 20000
+)
+
+// ParticlePool は爆発などのパーティクルを入れておく、最初に大きさを決めた入れ物です。
+// 連鎖する爆発でパーティクルがいくら出てもメモリの確保が起きないよう、配列を輪のように使い回します。
+// いっぱいのときは最も古い粒を消して新しい粒を入れます（粒が減って見た目が少し寂しくなるだけで、
+// フレームレートは落ちない）
+type ParticlePool struct {
+	items []Particle
+	start int // 最も古い粒の位置
+	count int // 入っている粒の数
+}
+
+// newParticlePool はsize個までの粒を入れられる入れ物を作ります
+func newParticlePool(size int) ParticlePool {
+	return ParticlePool{items: make([]Particle, size)}
+}
+
+// at は古い方からi番目の粒を返します
+func (pp *ParticlePool) at(i int) *Particle {
+	return &pp.items[(pp.start+i)%len(pp.items)]
+}
+
+// len は入っている粒の数を返します
+func (pp *ParticlePool) len() int {
+	return pp.count
+}
+
+// add は粒を1つ加えます。いっぱいなら最も古い粒と入れ替えます
+func (pp *ParticlePool) add(p Particle) {
+	if len(pp.items) == 0 {
+		return
+	}
+	if pp.count == len(pp.items) {
+		pp.items[pp.start] = p
+		pp.start = (pp.start + 1) % len(pp.items)
+		return
+	}
+	*pp.at(pp.count) = p
+	pp.count++
+}
+
+// update は粒を動かし、寿命の切れた粒を古い順のまま詰めて取り除きます
+func (pp *ParticlePool) update() {
+	alive := 0
+	for i := 0; i < pp.count; i++ {
+		p := *pp.at(i)
+		if p.ptype != 1 {
+			p.updateShape()
+		}
+		p.alpha -= 1.0 / float64(p.lifetime)
+		p.lifetime--
+		if p.lifetime > 0 && p.alpha > 0 {
+			*pp.at(alive) = p
+			alive++
+		}
+	}
+	pp.count = alive
+}
+
+// draw は粒を古い順に描画します（新しい粒が手前になる）
+func (pp *ParticlePool) draw(field *ebiten.Image) {
+	for i := 0; i < pp.count; i++ {
+		p := pp.at(i)
+		if p.ptype == 1 {
+			norm := math.Hypot(p.vx, p.vy)
+			if norm == 0 {
+				norm = 1
+			}
+			length := 1000.0 // 画面端まで
+			dx := p.vx / norm * length
+			dy := p.vy / norm * length
+			ebitenutil.DrawLine(field, p.x, p.y, p.x+dx, p.y+dy, color.RGBA{255, 255, 0, uint8(p.alpha * 255)})
+		} else if !p.drawShape(field) {
+			c := p.rampColor()
+			c.A = uint8(float64(c.A) * p.alpha)
+			ebitenutil.DrawRect(field, p.x, p.y, p.size, p.size, c)
+		}
+	}
+}
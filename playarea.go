@@ -0,0 +1,94 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"SimpleShootingStar/stagedef"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// PlayArea は画面のうち実際にゲームが行われる領域を表す構造体。
+// 敵や弾の座標はこの領域の左上を原点とするワールド座標で扱います
+type PlayArea struct {
+	x, y          float64 // 画面上での左上の位置
+	width, height float64 // ワールド座標での広さ
+	wrap          bool    // 自機が左右の端から反対側へ抜けられるか
+}
+
+// stageBaseWidth はstages.jsonのx座標が基準にしている画面の幅です
+const stageBaseWidth = stagedef.BaseWidth
+
+var playArea = newPlayArea(PlayAreaClamp)
+
+// newPlayArea は設定のモードと内部解像度に応じたプレイエリアを作成します
+func newPlayArea(mode string) PlayArea {
+	w, h := float64(resolution.Width), float64(resolution.Height)
+	switch mode {
+	case PlayAreaWrap:
+		return PlayArea{width: w, height: h, wrap: true}
+	case PlayAreaLetterbox:
+		// 高さいっぱいの縦長3:4を画面中央に置き、左右をHUDパネルにする。
+		// 縦長の解像度で幅が足りなければ画面全体を使う
+		width := math.Min(h*3/4, w)
+		return PlayArea{x: (w - width) / 2, width: width, height: h}
+	}
+	return PlayArea{width: w, height: h}
+}
+
+// letterboxed は左右にHUDパネルがあるかどうかを返します
+func (a PlayArea) letterboxed() bool {
+	return a.width < float64(resolution.Width)
+}
+
+// stageX はstages.jsonのx座標（幅640の画面基準）をプレイエリアの座標に変換します
+func (a PlayArea) stageX(x int) float64 {
+	return float64(x) * a.width / stageBaseWidth
+}
+
+// constrainPlayerX は自機のx座標を端で止めるか、反対側へ回り込ませます
+func (a PlayArea) constrainPlayerX(x float64) float64 {
+	if a.wrap {
+		// 自機の中心が端を越えたら反対側へ
+		center := x + 10
+		if center < 0 {
+			return x + a.width
+		}
+		if center >= a.width {
+			return x - a.width
+		}
+		return x
+	}
+	if x < 20 {
+		return 20
+	}
+	if x > a.width-40 {
+		return a.width - 40
+	}
+	return x
+}
+
+// drawPanels はレターボックス時に左右のHUDパネルを描画します
+func (a PlayArea) drawPanels(screen *ebiten.Image) {
+	if !a.letterboxed() {
+		return
+	}
+	panelColor := color.RGBA{20, 20, 40, 255}
+	borderColor := color.RGBA{80, 80, 140, 255}
+	w, h := float64(resolution.Width), float64(resolution.Height)
+	ebitenutil.DrawRect(screen, 0, 0, a.x, h, panelColor)
+	ebitenutil.DrawRect(screen, a.x+a.width, 0, w-a.x-a.width, h, panelColor)
+	ebitenutil.DrawRect(screen, a.x-2, 0, 2, h, borderColor)
+	ebitenutil.DrawRect(screen, a.x+a.width, 0, 2, h, borderColor)
+}
+
+// fieldImage はプレイエリアを描画するための画像を返します
+func (g *Game) fieldImage() *ebiten.Image {
+	w, h := int(playArea.width), int(playArea.height)
+	if g.field == nil || g.field.Bounds().Dx() != w || g.field.Bounds().Dy() != h {
+		g.field = ebiten.NewImage(w, h)
+	}
+	return g.field
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	respawnClearRadius = 160 // 復活位置からこの距離以内の敵弾を消す
+	respawnCeaseFire   = 90  // 復活後に敵が弾を撃たないフレーム数
+	invincibleBlinkCyc = 8   // 無敵中の点滅周期
+	focusSpreadScale   = 0.4 // 低速移動中のショットの広がりの倍率
+)
+
+// killPlayer は自機を爆発させます。無敵時間中は何もしません
+func (g *Game) killPlayer() {
+	if g.invincibleTimer > 0 || g.cheatInvincible {
+		return
+	}
+	g.emit(Event{Kind: EventPlayerHit, X: g.playerX + 10, Y: g.playerY + 12})
+	g.updateHighScore()
+	// プレイヤーの爆発エフェクト
+	g.createExplosion(g.playerX+10, g.playerY+12, color.RGBA{0, 255, 0, 255}, ExplosionPlayer)
+	g.gameState = GameStatePlayerExplosion
+	g.playerExplosionTimer = 0
+	g.startSlowMotion(6, 40)
+	g.emit(Event{Kind: EventPlayerDied, X: g.playerX + 10, Y: g.playerY + 12})
+}
+
+// respawnPlayer は残機を1つ使って自機を初期位置に復活させ、無敵時間を与えます。
+// 無敵が切れた直後にまたやられないよう、周りの敵弾を消して敵の攻撃もしばらく止めます
+func (g *Game) respawnPlayer() {
+	// ボス練習で残機無限にしているときやキャラバン中は減らさない
+	if !g.infiniteLives() {
+		g.lives--
+	}
+	g.playerX = playArea.width / 2
+	g.playerY = playArea.height / 2 * 1.7
+	g.stopPlayer()
+	g.invincibleTimer = tuning.Player.RespawnInvincible
+	g.playerTrail = PlayerTrail{}
+	g.ceaseFireTimer = respawnCeaseFire
+	g.clearBulletsAround(g.playerX+10, g.playerY+12, respawnClearRadius)
+	g.gameState = GameStatePlaying
+}
+
+// clearBulletsAround は(x, y)からradius以内の敵弾を小さな光に変えて消します
+func (g *Game) clearBulletsAround(x, y, radius float64) {
+	newEnemyBullets := g.enemyBullets[:0]
+	for _, eb := range g.enemyBullets {
+		if math.Hypot(eb.x-x, eb.y-y) < radius {
+			g.emitParticles("bulletFade", eb.x, eb.y)
+			continue
+		}
+		newEnemyBullets = append(newEnemyBullets, eb)
+	}
+	g.enemyBullets = newEnemyBullets
+}
+
+// moveSpeed は現在の移動速度を返します。低速移動中は半分の速さになります
+func (g *Game) moveSpeed() float64 {
+	if g.focused {
+		return g.ship().Speed * tuning.Player.FocusSpeedScale
+	}
+	return g.ship().Speed
+}
+
+// shotAngle は現在のショットの発射角度（度）を返します。低速移動中は広がりを狭めます
+func (g *Game) shotAngle(deg float64) float64 {
+	if g.focused {
+		return deg * focusSpreadScale
+	}
+	return deg
+}
+
+// drawPlayer は自機を残像と一緒に描画します。無敵中は点滅し、
+// 低速移動中は正確な当たり判定を表示します
+func (g *Game) drawPlayer(screen *ebiten.Image) {
+	g.drawAfterimages(screen)
+	if g.invincibleTimer == 0 || g.invincibleTimer%invincibleBlinkCyc < invincibleBlinkCyc/2 {
+		drawShipShape(screen, g.playerX, g.playerY, color.RGBA{0, 255, 0, 255})
+	}
+
+	if g.focused {
+		hx, hy, hw, hh := g.playerHitbox()
+		ebitenutil.DrawRect(screen, hx, hy, hw, hh, color.RGBA{255, 0, 0, 120})
+		ebitenutil.DrawRect(screen, hx+hw/2-2, hy+hh/2-2, 4, 4, color.RGBA{255, 255, 255, 255})
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+	"SimpleShootingStar/i18n"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// BossPractice はボス練習モードの状態です
+type BossPractice struct {
+	stage         int  // 練習しているボスのステージ（0始まり）
+	infiniteLives bool // やられても残機が減らない
+	timer         int  // ボスが出現してからのフレーム数
+	bossSpawned   bool // ボスが出現したか
+}
+
+// bossSelect はボス選択画面のカーソルと設定です
+type bossSelect struct {
+	cursor        int
+	infiniteLives bool
+}
+
+// bossWave はステージの最初のボスのウェーブを返します
+func bossWave(stage int) (Wave, bool) {
+	for _, w := range stages[stage].Waves {
+		if w.EnemyType == EnemyTypeBoss {
+			return w, true
+		}
+	}
+	return Wave{}, false
+}
+
+// markBossSeen はボスに出会ったステージを記録し、練習モードで選べるようにします
+func markBossSeen(stage int) {
+	r := stageRecords()
+	for _, s := range r.BossesSeen {
+		if s == stage {
+			return
+		}
+	}
+	r.BossesSeen = append(r.BossesSeen, stage)
+	sort.Ints(r.BossesSeen)
+}
+
+// practiceStages は練習モードで選べるステージの一覧を返します
+func practiceStages() []int {
+	var list []int
+	for _, s := range stageRecords().BossesSeen {
+		if s >= 0 && s < len(stages) {
+			if _, ok := bossWave(s); ok {
+				list = append(list, s)
+			}
+		}
+	}
+	return list
+}
+
+// updateBossSelect はボス選択画面の入力を処理します
+func (g *Game) updateBossSelect() {
+	list := practiceStages()
+	if g.input.JustPressed(ebiten.KeyEscape) || len(list) == 0 {
+		g.sound.Play("menuCancel")
+		g.startTransition(TransitionFade, func() {
+			g.gameState = GameStateTitle
+		}, nil)
+		return
+	}
+	sel := &g.bossSelect
+	if g.input.JustPressed(ebiten.KeyUp) {
+		sel.cursor = (sel.cursor + len(list) - 1) % len(list)
+		g.sound.Play("menuCursor")
+	}
+	if g.input.JustPressed(ebiten.KeyDown) {
+		sel.cursor = (sel.cursor + 1) % len(list)
+		g.sound.Play("menuCursor")
+	}
+	sel.cursor %= len(list)
+	if g.input.JustPressed(ebiten.KeyLeft) {
+		g.selectedShip = (g.selectedShip + len(ships) - 1) % len(ships)
+		g.sound.Play("menuCursor")
+	}
+	if g.input.JustPressed(ebiten.KeyRight) {
+		g.selectedShip = (g.selectedShip + 1) % len(ships)
+		g.sound.Play("menuCursor")
+	}
+	if g.input.JustPressed(ebiten.KeyL) {
+		sel.infiniteLives = !sel.infiniteLives
+		g.sound.Play("menuCursor")
+	}
+	if g.input.JustPressed(ebiten.KeySpace) {
+		g.sound.Play("menuConfirm")
+		stage, infinite := list[sel.cursor], sel.infiniteLives
+		g.startTransition(TransitionIris, func() {
+			g.startBossPractice(stage, infinite)
+		}, nil)
+	}
+}
+
+// startBossPractice はステージの途中を飛ばし、ボスのウェーブだけでステージを始めます
+func (g *Game) startBossPractice(stage int, infiniteLives bool) {
+	w, _ := bossWave(stage)
+	w.Delay = 0
+	g.score = 0
+	g.lives = tuning.Player.InitialLives
+	g.bombs = tuning.Player.InitialBombs
+	g.resetMultiplier()
+	g.rank = 0
+	g.practice = &BossPractice{stage: stage, infiniteLives: infiniteLives}
+	g.startStage(stage)
+	g.setWaves([]Wave{w})
+}
+
+// updateBossPractice はボスの撃破タイムを計り、Escキーで練習をやめてボス選択へ戻ります
+func (g *Game) updateBossPractice() {
+	p := g.practice
+	if p == nil {
+		return
+	}
+	if g.input.JustPressed(ebiten.KeyEscape) {
+		g.endBossPractice()
+		return
+	}
+	if !p.bossSpawned {
+		for _, e := range g.enemies {
+			if e.boss != nil {
+				p.bossSpawned = true
+				break
+			}
+		}
+	}
+	if p.bossSpawned {
+		p.timer++
+	}
+}
+
+// onBossPracticeCleared はボスを倒したときにタイムを記録します
+func (g *Game) onBossPracticeCleared() {
+	p := g.practice
+	r := stageRecords()
+	if best, ok := r.BossBestFrames[p.stage]; !ok || p.timer < best {
+		if r.BossBestFrames == nil {
+			r.BossBestFrames = map[int]int{}
+		}
+		r.BossBestFrames[p.stage] = p.timer
+		saveStats()
+	}
+}
+
+// endBossPractice は練習を終えてボス選択画面へ戻ります
+func (g *Game) endBossPractice() {
+	g.startTransition(TransitionFade, func() {
+		g.practice = nil
+		g.bossApproach = BossApproach{}
+		g.backgroundOverride = nil
+		g.enemies = []Enemy{}
+		g.enemyBullets = []EnemyBullet{}
+		g.gameState = GameStateBossSelect
+	}, nil)
+}
+
+// formatFrames はフレーム数を「秒.百分の一秒」の形にします
+func formatFrames(frames int) string {
+	return fmt.Sprintf("%d.%02d", frames/60, frames%60*100/60)
+}
+
+// drawBossSelect はボス選択画面を描画します
+func (g *Game) drawBossSelect(screen *ebiten.Image) {
+	hud.DrawTextOutline(screen, i18n.T("practice.title"), fonts.Face(fonts.Large), resolution.Width/2, ui(56), hud.AlignCenter, color.White, hud.OutlineColor)
+	for i, stage := range practiceStages() {
+		clr := color.Color(color.White)
+		if i == g.bossSelect.cursor {
+			clr = color.RGBA{255, 255, 0, 255}
+		}
+		line := stages[stage].Name
+		if best, ok := stageRecords().BossBestFrames[stage]; ok {
+			line += "  " + i18n.Tf("practice.best", formatFrames(best))
+		}
+		hud.DrawTextShadow(screen, line, fonts.Face(fonts.Medium), resolution.Width/2-ui(200), ui(110+i*28), hud.AlignLeft, clr)
+	}
+
+	lives := i18n.T("practice.livesNormal")
+	if g.bossSelect.infiniteLives {
+		lives = i18n.T("practice.livesInfinite")
+	}
+	hud.DrawTextShadow(screen, i18n.Tf("practice.ship", g.ship().Name), fonts.Face(fonts.Medium), resolution.Width/2, resolution.Height-ui(100), hud.AlignCenter, color.White)
+	hud.DrawTextShadow(screen, lives, fonts.Face(fonts.Medium), resolution.Width/2, resolution.Height-ui(72), hud.AlignCenter, color.White)
+	hud.DrawTextShadow(screen, i18n.T("practice.guide"), fonts.Face(fonts.Small), resolution.Width/2, resolution.Height-ui(20), hud.AlignCenter, color.White)
+}
+
+// drawBossPracticeTimer はボスの撃破タイムを画面上部に描画します
+func (g *Game) drawBossPracticeTimer(screen *ebiten.Image) {
+	if g.practice == nil {
+		return
+	}
+	text := i18n.Tf("practice.time", formatFrames(g.practice.timer))
+	hud.DrawTextOutline(screen, text, fonts.Face(fonts.Medium), resolution.Width/2, ui(60), hud.AlignCenter, color.White, hud.OutlineColor)
+}
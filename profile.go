@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	_ "net/http/pprof" // /debug/pprof/ のハンドラを登録する
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const profileDir = "profiles" // プロファイルの保存先
+
+// profiling は-pprofを指定して起動し、プロファイルを取るキー操作が有効かを表します
+var profiling bool
+
+// cpuProfile は記録中のCPUプロファイルのファイルです。記録していなければnil
+var cpuProfile *os.File
+
+// startPprofServer はpprofのHTTPエンドポイントを別のゴルーチンで開きます
+func startPprofServer(addr string) {
+	profiling = true
+	go func() {
+		slog.Info("pprofのエンドポイントを開きました", "url", "http://"+addr+"/debug/pprof/")
+		if err := http.ListenAndServe(addr, nil); err != nil {
+			slog.Error("pprofのエンドポイントを開けませんでした", "addr", addr, "err", err)
+		}
+	}()
+}
+
+// updateProfiling はF10キーでCPUプロファイルの記録を始めたり止めたりし、
+// F11キーでヒーププロファイルを書き出します
+func (g *Game) updateProfiling() {
+	if !profiling {
+		return
+	}
+	if g.input.JustPressed(ebiten.KeyF10) {
+		if cpuProfile == nil {
+			if err := startCPUProfile(); err != nil {
+				slog.Error("CPUプロファイルの記録を始められませんでした", "err", err)
+			}
+		} else {
+			stopCPUProfile()
+		}
+	}
+	if g.input.JustPressed(ebiten.KeyF11) {
+		if err := writeHeapProfile(); err != nil {
+			slog.Error("ヒーププロファイルの書き出しに失敗", "err", err)
+		}
+	}
+}
+
+// profilePath はprofilesフォルダに日時の付いたファイル名を作ります
+func profilePath(kind string) (string, error) {
+	if err := os.MkdirAll(profileDir, 0755); err != nil {
+		return "", fmt.Errorf("フォルダの作成に失敗: %v", err)
+	}
+	return filepath.Join(profileDir, kind+"-"+time.Now().Format("20060102-150405")+".pprof"), nil
+}
+
+// startCPUProfile はCPUプロファイルの記録を始めます
+func startCPUProfile() error {
+	path, err := profilePath("cpu")
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("ファイルの作成に失敗: %v", err)
+	}
+	if err := pprof.StartCPUProfile(file); err != nil {
+		file.Close()
+		return fmt.Errorf("記録の開始に失敗: %v", err)
+	}
+	cpuProfile = file
+	slog.Info("CPUプロファイルの記録を始めました", "path", path)
+	return nil
+}
+
+// stopCPUProfile は記録中のCPUプロファイルを止めてファイルを閉じます。記録していなければ何もしません
+func stopCPUProfile() {
+	if cpuProfile == nil {
+		return
+	}
+	pprof.StopCPUProfile()
+	slog.Info("CPUプロファイルを保存しました", "path", cpuProfile.Name())
+	cpuProfile.Close()
+	cpuProfile = nil
+}
+
+// writeHeapProfile はその時点のヒーププロファイルを書き出します
+func writeHeapProfile() error {
+	path, err := profilePath("heap")
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("ファイルの作成に失敗: %v", err)
+	}
+	defer file.Close()
+	// 直近の割り当てまで反映させる
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		return fmt.Errorf("書き出しに失敗: %v", err)
+	}
+	slog.Info("ヒーププロファイルを保存しました", "path", path)
+	return nil
+}
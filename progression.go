@@ -0,0 +1,100 @@
+package main
+
+import "log/slog"
+
+// StageProgression はステージの進み方（今のステージのやり直し・次のステージや次の周への移動・
+// 全ステージクリア）をまとめて扱います。ステージクリア画面の自動進行とスペースキーでの進行は、
+// どちらもAdvanceStageを通ります
+type StageProgression struct {
+	g *Game
+}
+
+// progression はゲームのステージの進み方を扱う値を返します
+func (g *Game) progression() StageProgression {
+	return StageProgression{g: g}
+}
+
+// IsFinalStage は今のステージが周の最後のステージかを返します
+func (p StageProgression) IsFinalStage() bool {
+	return p.g.currentStage+1 >= len(stages)
+}
+
+// AdvanceStage は暗転を挟んで次のステージへ進みます。最終ステージの後は次の周へ進み、
+// 最後の周を終えたらゲームオーバー画面へ移ります。ボス練習・タイムアタック・デイリーチャレンジは
+// 次のステージへは進まずにそれぞれの終わり方をします
+func (p StageProgression) AdvanceStage() {
+	g := p.g
+	if g.practice != nil {
+		g.endBossPractice()
+		return
+	}
+	if g.timeAttack != nil {
+		g.endTimeAttack()
+		return
+	}
+	if g.daily != nil {
+		g.finishDaily(true)
+		return
+	}
+	g.startTransition(TransitionFade, func() {
+		if !p.IsFinalStage() {
+			g.startStage(g.currentStage + 1)
+			return
+		}
+		if g.canStartNextLoop() {
+			g.startNextLoop()
+			return
+		}
+		slog.Info("全ステージクリア", "score", g.score)
+		g.currentStage++
+		g.gameState = GameStateGameOver
+		g.updateHighScore()
+		saveStats()
+	}, nil)
+}
+
+// ResetStage は画面上の敵や弾を消して今のステージを最初から始めます
+func (p StageProgression) ResetStage() {
+	g := p.g
+	waves := g.stage().Waves
+	if s := g.stage().Shuffle; s != nil {
+		waves = shuffleWaves(waves, *s, s.Seed+int64(g.loop))
+	}
+	g.setWaves(waves)
+	slog.Info("ステージ開始", "stage", g.currentStage+1, "name", g.stage().Name, "score", g.score, "lives", g.lives)
+	g.currentSpawn = 0
+	g.framesSinceSpawn = 0
+	g.stageFrames = 0
+	g.stageStartScore = g.score
+	g.stageMisses = 0
+	g.stageEscaped = 0
+	g.escapedByWave = nil
+	g.tally = nil
+	g.waveTimer = 0
+	g.enemies = []Enemy{}
+	g.bullets = []Bullet{}
+	g.enemyBullets = []EnemyBullet{}
+	g.mines = []Mine{}
+	g.beams = []Beam{}
+	g.tokens = []StarToken{}
+	g.scoreItems = []ScoreItem{}
+	g.magnets = nil
+	g.dyingEnemies = nil
+	g.deathQueue = nil
+	g.floatingTexts = nil
+	g.playerTrail = PlayerTrail{}
+	g.stopPlayer()
+	g.formations = nil
+	g.openFormations = nil
+	g.bossWarned = false
+	g.bossWarningTimer = 0
+	g.bossApproach = BossApproach{}
+	g.nextStageEvent = 0
+	g.backgroundOverride = nil
+	g.shakeTimer, g.shakeStrength = 0, 0
+	g.bulletTimeTimer = 0
+	g.ceaseFireTimer = 0
+	g.gameState = GameStatePlaying
+	g.applyBackground()
+	g.startStageIntro()
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+func init() {
+	registerCommand("rank", ConsoleCommand{
+		Usage: "<0〜10>",
+		Help:  "ランクを設定する（10で最大）",
+		Run: func(g *Game, args []string) (string, error) {
+			if len(args) != 1 {
+				return "", fmt.Errorf("引数の数が違います")
+			}
+			v, err := strconv.ParseFloat(args[0], 64)
+			if err != nil || v < 0 || v > 10 {
+				return "", fmt.Errorf("ランクの値が不正です: %s", args[0])
+			}
+			g.rank = v / 10
+			return fmt.Sprintf("rank: %.2f (x%.2f)", g.rank, g.rankMultiplier()), nil
+		},
+	})
+	subscribe(EventEnemyKilled, func(g *Game, e Event) { g.raiseRankByScore(e.Points) })
+	subscribe(EventPlayerDied, func(g *Game, _ Event) { g.dropRank() })
+}
+
+// updateRank は生き延びた時間に応じてランクを上げます
+func (g *Game) updateRank() {
+	g.addRank(1 / tuning.Rank.SurviveFrames)
+}
+
+// raiseRankByScore は得点に応じてランクを上げます
+func (g *Game) raiseRankByScore(points int) {
+	g.addRank(float64(points) * tuning.Rank.ScoreRate)
+}
+
+// dropRank は自機がやられたときにランクを下げます
+func (g *Game) dropRank() {
+	g.addRank(-tuning.Rank.DeathDrop)
+}
+
+// addRank はランクを0〜1の範囲で増減させます
+func (g *Game) addRank(delta float64) {
+	g.rank += delta
+	if g.rank < 0 {
+		g.rank = 0
+	}
+	if g.rank > 1 {
+		g.rank = 1
+	}
+}
+
+// rankMultiplier は敵の弾速と発射頻度に掛ける倍率を返します。
+// 設定でランクを無効にしているときは常に1です
+func (g *Game) rankMultiplier() float64 {
+	if !settings.Rank {
+		return 1
+	}
+	return 1 + g.rank*(tuning.Rank.MaxMultiplier-1)
+}
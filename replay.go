@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const replayDir = "replays" // タイムアタックの自己ベストのリプレイの保存先
+
+// ghostColor はゴーストの自機の色です（半透明の水色）
+var ghostColor = color.RGBA{40, 90, 120, 120}
+
+// Replay はタイムアタックの1回分の自機の動きです。
+// Positionsはタイムが進んだフレームごとの自機の位置で、やられて自機がいないフレームは[-1, -1]です
+type Replay struct {
+	Stage     int      `json:"stage"`     // ステージ（0始まり）
+	Ship      int      `json:"ship"`      // 自機
+	Frames    int      `json:"frames"`    // クリアタイム（フレーム数）
+	Positions [][2]int `json:"positions"` // フレームごとの自機の位置
+}
+
+// replayPath は選んでいるステージパックのステージのリプレイのファイル名を返します
+func replayPath(stage int) string {
+	return filepath.Join(replayDir, fmt.Sprintf("%s-stage%d.json", stagePacks[currentPack].Hash, stage+1))
+}
+
+// loadReplay はステージの自己ベストのリプレイを読み込みます。なければnilを返します
+func loadReplay(stage int) *Replay {
+	file, err := os.ReadFile(replayPath(stage))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	var r Replay
+	if err == nil {
+		err = json.Unmarshal(file, &r)
+	}
+	if err != nil {
+		slog.Warn("リプレイの読み込みに失敗", "file", replayPath(stage), "err", err)
+		return nil
+	}
+	return &r
+}
+
+// saveReplay はリプレイを書き出します
+func saveReplay(r *Replay) error {
+	if headless {
+		return nil
+	}
+	if err := os.MkdirAll(replayDir, 0755); err != nil {
+		return fmt.Errorf("リプレイのフォルダの作成に失敗: %v", err)
+	}
+	file, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("リプレイの変換に失敗: %v", err)
+	}
+	if err := os.WriteFile(replayPath(r.Stage), file, 0644); err != nil {
+		return fmt.Errorf("リプレイの書き込みに失敗: %v", err)
+	}
+	return nil
+}
+
+// recordReplay は今のフレームの自機の位置をリプレイに加えます
+func (g *Game) recordReplay() {
+	pos := [2]int{-1, -1}
+	if g.gameState == GameStatePlaying {
+		pos = [2]int{int(math.Round(g.playerX)), int(math.Round(g.playerY))}
+	}
+	g.timeAttack.replay.Positions = append(g.timeAttack.replay.Positions, pos)
+}
+
+// drawGhost はタイムアタック中に、自己ベストのリプレイの同じタイムの位置へ半透明の自機を描画します
+func (g *Game) drawGhost(field *ebiten.Image) {
+	t := g.timeAttack
+	if g.gameState != GameStatePlaying && g.gameState != GameStatePlayerExplosion {
+		return
+	}
+	if t == nil || t.ghost == nil || t.frames == 0 || t.frames > len(t.ghost.Positions) {
+		return
+	}
+	pos := t.ghost.Positions[t.frames-1]
+	if pos[0] < 0 {
+		return
+	}
+	drawShipShape(field, float64(pos[0]), float64(pos[1]), ghostColor)
+}
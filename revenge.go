@@ -0,0 +1,35 @@
+package main
+
+import "math"
+
+// 撃ち返し弾の出し方（settings.jsonのrevengeBullets）
+const (
+	RevengeOff    = "off"    // 撃ち返し弾を出さない
+	RevengeLoop   = "loop"   // 2周目以降だけ出す
+	RevengeAlways = "always" // 1周目から出す
+)
+
+// revengeEnabled は今の周回で撃ち返し弾を出すかを返します
+func (g *Game) revengeEnabled() bool {
+	switch settings.RevengeBullets {
+	case RevengeAlways:
+		return true
+	case RevengeLoop:
+		return g.loop > 0
+	}
+	return false
+}
+
+// fireRevengeBullet は自機の弾で倒した敵から、自機を狙った弾を1発撃ち返します。
+// ボムで倒した敵は撃ち返さないよう、当たり判定の撃破の処理からだけ呼び出すこと。
+// ボスと砲台は撃ち返しません
+func (g *Game) fireRevengeBullet(e Enemy) {
+	if !g.revengeEnabled() || e.enemyType == EnemyTypeBoss || e.enemyType == EnemyTypeTurret {
+		return
+	}
+	x, y := e.x+10, e.y+10
+	speed := tuning.EnemyShot.BulletSpeed * g.rankMultiplier() * g.loopSpeedScale()
+	dx, dy := g.playerX-x, g.playerY-y
+	dist := math.Max(math.Hypot(dx, dy), 1)
+	g.enemyBullets = append(g.enemyBullets, EnemyBullet{x: x, y: y, vx: dx / dist * speed, vy: dy / dist * speed})
+}
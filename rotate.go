@@ -0,0 +1,63 @@
+package main
+
+import (
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// rotated は画面を90度か270度回しているかを返します。縦置きのモニター向けにモニターごと回すので、
+// プレイヤーから見た上下左右は変わらず、キー入力や効果音の左右はそのままにします
+func rotated() bool {
+	return settings.Rotation == 90 || settings.Rotation == 270
+}
+
+// displaySize は回転後の画面（ウィンドウ）の大きさを返します
+func displaySize() (int, int) {
+	if rotated() {
+		return resolution.Height, resolution.Width
+	}
+	return resolution.Width, resolution.Height
+}
+
+// Draw はゲームの描画を行います。回転や後処理、整数倍の拡大の設定があれば、
+// いったん内部解像度の画像に描いてから後処理をかけ、回して画面に転写します
+func (g *Game) Draw(screen *ebiten.Image) {
+	if g.skipFrame(screen) {
+		return
+	}
+	if g.bench != nil {
+		defer g.bench.measureDraw(time.Now())
+	}
+	if !rotated() && crtShader == nil && !integerScaling() {
+		g.drawScreen(screen)
+		return
+	}
+	if g.canvas == nil {
+		g.canvas = ebiten.NewImage(resolution.Width, resolution.Height)
+	}
+	g.canvas.Clear()
+	g.drawScreen(g.canvas)
+
+	img := g.canvas
+	if crtShader != nil {
+		img = g.applyCRT(img)
+	}
+	op := &ebiten.DrawImageOptions{}
+	if rotated() {
+		op.GeoM.Rotate(float64(settings.Rotation) * math.Pi / 180)
+		if settings.Rotation == 90 {
+			// 時計回りに90度：左上が右上へ来る
+			op.GeoM.Translate(float64(resolution.Height), 0)
+		} else {
+			// 時計回りに270度：左上が左下へ来る
+			op.GeoM.Translate(0, float64(resolution.Width))
+		}
+	}
+	if integerScaling() {
+		presentScaled(screen, img, op)
+		return
+	}
+	screen.DrawImage(img, op)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	rumbleMinDuration = 80 * time.Millisecond  // 最も弱い振動の長さ
+	rumbleMaxDuration = 400 * time.Millisecond // 最も強い振動の長さ
+)
+
+// 出来事ごとの振動の強さ（0〜1）
+const (
+	rumblePlayerHit   = 1.0
+	rumbleBomb        = 0.7
+	rumbleBossPhase   = 0.35
+	rumbleWeakPortion = 0.6 // 高い周波数のモーターを低い周波数のモーターに比べてどれだけ震わせるか
+)
+
+func init() {
+	subscribe(EventPlayerHit, func(*Game, Event) { rumbleGamepads(rumblePlayerHit) })
+	subscribe(EventBombUsed, func(*Game, Event) { rumbleGamepads(rumbleBomb) })
+	subscribe(EventBossPhaseChanged, func(*Game, Event) { rumbleGamepads(rumbleBossPhase) })
+}
+
+// rumbleGamepads はつながっているゲームパッドを、強さ（0〜1）に応じた強さと長さで振動させます。
+// 設定で切っているときやシミュレーション中は何もしません
+func rumbleGamepads(intensity float64) {
+	if !settings.Rumble || headless {
+		return
+	}
+	opts := &ebiten.VibrateGamepadOptions{
+		Duration:        rumbleMinDuration + time.Duration(float64(rumbleMaxDuration-rumbleMinDuration)*intensity),
+		StrongMagnitude: intensity,
+		WeakMagnitude:   intensity * rumbleWeakPortion,
+	}
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		ebiten.VibrateGamepad(id, opts)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ウィンドウへの拡大のしかた
+const (
+	ScalingInteger = "integer" // 整数倍にだけ拡大してドットをくっきり保ち、余りは黒帯にする
+	ScalingFit     = "fit"     // ウィンドウに収まる大きさへ端数の倍率でも滑らかに拡大する
+)
+
+// uiBaseHeight は文字を等倍で表示する内部解像度の高さです
+const uiBaseHeight = 480
+
+// integerScaling は画面を整数倍で拡大するかを返します
+func integerScaling() bool {
+	return settings.Scaling == ScalingInteger
+}
+
+// layoutSize はウィンドウの大きさに対する画面の大きさを返します。
+// 整数倍で拡大するときは、ウィンドウの実際のピクセル数をそのまま画面にして自分で拡大します
+func layoutSize(outsideWidth, outsideHeight int) (int, int) {
+	if !integerScaling() {
+		return displaySize()
+	}
+	scale := ebiten.DeviceScaleFactor()
+	return max(int(float64(outsideWidth)*scale), 1), max(int(float64(outsideHeight)*scale), 1)
+}
+
+// presentScaled は回転や後処理を済ませた画像imgを、画面に収まる最大の整数倍で中央に描きます。
+// 画面が内部解像度より小さいときだけは縮小し、ドットが抜け落ちないよう滑らかに縮めます。
+// 周りは消去されたままの黒帯になります
+func presentScaled(screen, img *ebiten.Image, op *ebiten.DrawImageOptions) {
+	w, h := displaySize()
+	sw, sh := screen.Bounds().Dx(), screen.Bounds().Dy()
+	scale := math.Min(float64(sw)/float64(w), float64(sh)/float64(h))
+	op.Filter = ebiten.FilterLinear
+	if scale >= 1 {
+		scale = math.Floor(scale)
+		op.Filter = ebiten.FilterNearest
+	}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(math.Floor((float64(sw)-float64(w)*scale)/2), math.Floor((float64(sh)-float64(h)*scale)/2))
+	screen.DrawImage(img, op)
+}
+
+// uiScale はすべての画面の文字と、文字の位置や行間の倍率を返します。内部解像度を上げても
+// 文字が小さくなりすぎないよう、高さに合わせて大きくします（等倍より小さくはしません）
+func uiScale() float64 {
+	return max(float64(resolution.Height)/uiBaseHeight, 1)
+}
+
+// ui は等倍で決めた文字の位置や行間のピクセル数を、uiScaleの倍率に合わせます
+func ui(px int) int {
+	return int(math.Round(float64(px) * uiScale()))
+}
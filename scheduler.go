@@ -0,0 +1,72 @@
+package main
+
+import "fmt"
+
+// setWaves は遊ぶウェーブを設定し、ステージの開始から何フレーム目に出すか（出現フレーム）を1度だけ計算します
+func (g *Game) setWaves(waves []Wave) {
+	g.waves = waves
+	g.spawnFrames = scheduleWaves(waves)
+}
+
+// scheduleWaves はウェーブごとの出現フレーム（delayの累積）を返します
+func scheduleWaves(waves []Wave) []int {
+	frames := make([]int, len(waves))
+	total := 0
+	for i, w := range waves {
+		total += w.Delay
+		frames[i] = total
+	}
+	return frames
+}
+
+// spawnDueWaves は出現フレームに達したウェーブを順に、同じフレームにいくつでも出します。
+// ボスの警告や入れ替えの間隔待ちで保留したウェーブがあれば、その後ろのウェーブも順番を守って待ちます
+func (g *Game) spawnDueWaves() {
+	for g.currentSpawn < len(g.waves) && g.waveTimer >= g.spawnFrames[g.currentSpawn] {
+		wave := g.waves[g.currentSpawn]
+		if !g.beforeSpawn(wave) {
+			return
+		}
+		if startsWaveGroup(g.waves, g.currentSpawn) {
+			g.framesSinceSpawn = 0
+		}
+		g.spawnWave(wave)
+		g.currentSpawn++
+		if wave.EnemyType == EnemyTypeBoss {
+			g.bossWarned = false
+		}
+	}
+}
+
+// WaveInfo はデバッグ表示などに出す、ウェーブの進み具合です
+type WaveInfo struct {
+	Spawned   int // 出したウェーブの数
+	Total     int // ステージのウェーブの数
+	Timer     int // ウェーブのタイマー（フレーム数）
+	Next      int // 次に出すウェーブの番号（1始まり、出し切ったら0）
+	NextFrame int // 次のウェーブの出現フレーム
+	NextCount int // 次のウェーブと同じフレームに出るウェーブの数（次のウェーブを含む）
+}
+
+// waveInfo は今のウェーブの進み具合を返します
+func (g *Game) waveInfo() WaveInfo {
+	info := WaveInfo{Spawned: g.currentSpawn, Total: len(g.waves), Timer: g.waveTimer}
+	if g.currentSpawn >= len(g.spawnFrames) {
+		return info
+	}
+	info.Next = g.currentSpawn + 1
+	info.NextFrame = g.spawnFrames[g.currentSpawn]
+	for i := g.currentSpawn; i < len(g.spawnFrames) && g.spawnFrames[i] == info.NextFrame; i++ {
+		info.NextCount++
+	}
+	return info
+}
+
+// String はウェーブの進み具合を1行で表します
+func (w WaveInfo) String() string {
+	if w.Next == 0 {
+		return fmt.Sprintf("Wave: %d/%d  timer %d  (all spawned)", w.Spawned, w.Total, w.Timer)
+	}
+	return fmt.Sprintf("Wave: %d/%d  timer %d  next #%d x%d at %d (in %d)",
+		w.Spawned, w.Total, w.Timer, w.Next, w.NextCount, w.NextFrame, max(w.NextFrame-w.Timer, 0))
+}
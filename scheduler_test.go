@@ -0,0 +1,65 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScheduleWaves(t *testing.T) {
+	tests := []struct {
+		name   string
+		delays []int
+		want   []int
+	}{
+		{"empty", nil, []int{}},
+		{"cumulative", []int{10, 20, 30}, []int{10, 30, 60}},
+		{"same frame", []int{0, 30, 0, 0, 60}, []int{0, 30, 30, 30, 90}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waves := make([]Wave, len(tt.delays))
+			for i, d := range tt.delays {
+				waves[i].Delay = d
+			}
+			if got := scheduleWaves(waves); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scheduleWaves = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpawnDueWaves(t *testing.T) {
+	straight := func(delay int) Wave { return Wave{EnemyType: EnemyTypeStraight, X: 100, Delay: delay} }
+	boss := func(delay int) Wave { return Wave{EnemyType: EnemyTypeBoss, X: 300, Delay: delay} }
+	tests := []struct {
+		name        string
+		waves       []Wave
+		timer       int
+		wantSpawned int
+		wantWarning bool
+	}{
+		{"first wave at frame 0", []Wave{straight(0), straight(30)}, 0, 1, false},
+		{"not yet due", []Wave{straight(0), straight(30)}, 29, 1, false},
+		{"due on its frame", []Wave{straight(0), straight(30)}, 30, 2, false},
+		{"every due wave in one frame", []Wave{straight(0), straight(30), straight(0), straight(60)}, 30, 3, false},
+		{"late timer catches up", []Wave{straight(0), straight(30), straight(0), straight(60)}, 500, 4, false},
+		{"boss waits for the warning", []Wave{straight(0), boss(10), straight(0)}, 100, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(t)
+			g.setWaves(tt.waves)
+			g.waveTimer = tt.timer
+			g.spawnDueWaves()
+			if g.currentSpawn != tt.wantSpawned {
+				t.Errorf("currentSpawn = %d, want %d", g.currentSpawn, tt.wantSpawned)
+			}
+			if len(g.enemies) != tt.wantSpawned {
+				t.Errorf("enemies = %d, want %d", len(g.enemies), tt.wantSpawned)
+			}
+			if warning := g.bossWarningTimer > 0; warning != tt.wantWarning {
+				t.Errorf("boss warning = %v, want %v", warning, tt.wantWarning)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const screenshotDir = "screenshots" // スクリーンショットの保存先
+
+// screenshotPending は次の描画でスクリーンショットを撮るかを表します
+var screenshotPending bool
+
+func init() {
+	registerCommand("screenshot", ConsoleCommand{
+		Help: "コンソールを除いた画面をPNGで保存する",
+		Run: func(g *Game, args []string) (string, error) {
+			screenshotPending = true
+			return "screenshot: " + screenshotDir + "/", nil
+		},
+	})
+}
+
+// captureScreenshot は予約されていれば画面をPNGファイルに保存します。
+// デバッグ表示やコンソールを描く前に呼び出します
+func captureScreenshot(screen *ebiten.Image) {
+	if !screenshotPending {
+		return
+	}
+	screenshotPending = false
+	if err := saveScreenshot(screen); err != nil {
+		slog.Error("スクリーンショットの保存に失敗", "err", err)
+	}
+}
+
+// saveScreenshot は画面をscreenshotsフォルダにPNGで保存します
+func saveScreenshot(screen *ebiten.Image) error {
+	img := image.NewRGBA(screen.Bounds())
+	screen.ReadPixels(img.Pix)
+	if err := os.MkdirAll(screenshotDir, 0755); err != nil {
+		return fmt.Errorf("フォルダの作成に失敗: %v", err)
+	}
+	path := filepath.Join(screenshotDir, "shot-"+time.Now().Format("20060102-150405.000")+".png")
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("ファイルの作成に失敗: %v", err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, img); err != nil {
+		return fmt.Errorf("PNGの書き出しに失敗: %v", err)
+	}
+	slog.Info("スクリーンショットを保存しました", "path", path)
+	return nil
+}
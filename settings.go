@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"SimpleShootingStar/audio"
+	"SimpleShootingStar/i18n"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// プレイエリアの動作モード
+const (
+	PlayAreaClamp     = "clamp"     // 画面全体を使い、自機は端で止まる
+	PlayAreaWrap      = "wrap"      // 画面全体を使い、自機は左右の端から反対側へ抜けられる
+	PlayAreaLetterbox = "letterbox" // 縦長3:4のプレイエリアと左右のHUDパネル
+)
+
+// 内部解像度として受け付ける最小の大きさ
+const (
+	minResolutionWidth  = 480
+	minResolutionHeight = 480
+)
+
+const settingsFile = "settings.json" // ユーザー設定のファイル名
+
+// Resolution は内部解像度（論理的な画面の大きさ）です。
+// 横長の640x480や960x720、縦長（縦画面）の480x640などを指定できます
+type Resolution struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// resolution は今の内部解像度です。画面の大きさに関わる計算はすべてこれを使います
+var resolution = defaultResolution()
+
+// defaultResolution は既定の内部解像度（640x480）を返します
+func defaultResolution() Resolution {
+	return Resolution{Width: 640, Height: 480}
+}
+
+// Settings はsettings.jsonから読み込むユーザー設定の構造体
+type Settings struct {
+	PlayArea        string          `json:"playArea"`        // プレイエリアの動作モード
+	Language        string          `json:"language"`        // 表示言語（lang/<language>.json を使う）
+	Rank            bool            `json:"rank"`            // ランク（難易度の自動調整）を有効にする
+	HUDLayout       json.RawMessage `json:"hudLayout"`       // HUDの配置（指定した項目だけ既定値を上書き）
+	Resolution      Resolution      `json:"resolution"`      // 内部解像度
+	Rotation        int             `json:"rotation"`        // 画面の回転（0・90・270度）。縦置きのモニター向け
+	CRT             bool            `json:"crt"`             // ブラウン管風の後処理（走査線・ゆがみ・にじみ）をかける
+	Palette         string          `json:"palette"`         // 敵と敵弾の配色（色覚の特性に合わせて選ぶ）
+	DamageNumbers   bool            `json:"damageNumbers"`   // 敵に当てたときにダメージの数字を表示する
+	ConsoleKey      ebiten.Key      `json:"consoleKey"`      // デバッグコンソールを開閉するキー（Ebitenのキー名）
+	Volume          float64         `json:"volume"`          // 全体の音量（0〜1）。-/+キーで変えると書き換わる
+	Muted           bool            `json:"muted"`           // ミュート中か。Mキーで切り替えると書き換わる
+	Rumble          bool            `json:"rumble"`          // 被弾・ボム・ボスの行動の切り替わりでゲームパッドを振動させる
+	RevengeBullets  string          `json:"revengeBullets"`  // 倒した敵が撃ち返し弾を出すか（off・loop・always）
+	VSync           bool            `json:"vsync"`           // 垂直同期を有効にする
+	FPSCap          int             `json:"fpsCap"`          // フレームレートの上限（0なら制限なし）
+	ShowFPS         bool            `json:"showFPS"`         // 画面右下にフレームレートを表示する
+	MaxParticles    int             `json:"maxParticles"`    // 同時に出せるパーティクルの数（超えたら古い粒から消す）
+	Scaling         string          `json:"scaling"`         // ウィンドウへの拡大のしかた（integer・fit）
+	AudioSampleRate int             `json:"audioSampleRate"` // 音声の出力のサンプリング周波数（Hz）
+	AudioBufferMs   int             `json:"audioBufferMs"`   // 音声の再生バッファの長さ（ミリ秒、0なら既定）。音が途切れるときは長くする
+}
+
+var settings = defaultSettings()
+
+// defaultSettings は設定ファイルがないときの既定値を返します
+func defaultSettings() Settings {
+	return Settings{
+		PlayArea:        PlayAreaClamp,
+		Language:        i18n.DefaultLanguage,
+		Resolution:      defaultResolution(),
+		Palette:         PaletteStandard,
+		RevengeBullets:  RevengeLoop,
+		ConsoleKey:      ebiten.KeyBackquote,
+		Volume:          1,
+		Rumble:          true,
+		VSync:           true,
+		MaxParticles:    2000,
+		Scaling:         ScalingInteger,
+		AudioSampleRate: audio.DefaultConfig().SampleRate,
+	}
+}
+
+// loadSettings は設定ファイルを読み込みます。ファイルがなければ既定値のままにします
+func loadSettings() error {
+	file, err := os.ReadFile(settingsFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("設定ファイルの読み込みに失敗: %v", err)
+	}
+
+	s := defaultSettings()
+	if err := json.Unmarshal(file, &s); err != nil {
+		return fmt.Errorf("JSONのパースに失敗: %v", err)
+	}
+
+	switch s.PlayArea {
+	case PlayAreaClamp, PlayAreaWrap, PlayAreaLetterbox:
+	default:
+		return fmt.Errorf("playAreaの値が不正です: %q", s.PlayArea)
+	}
+
+	if s.Resolution.Width < minResolutionWidth || s.Resolution.Height < minResolutionHeight {
+		return fmt.Errorf("resolutionの値が不正です: %dx%d（%dx%d以上にしてください）",
+			s.Resolution.Width, s.Resolution.Height, minResolutionWidth, minResolutionHeight)
+	}
+
+	switch s.Rotation {
+	case 0, 90, 270:
+	default:
+		return fmt.Errorf("rotationの値が不正です: %d（0・90・270のいずれかにしてください）", s.Rotation)
+	}
+
+	if s.Volume < 0 || s.Volume > 1 {
+		return fmt.Errorf("volumeの値が不正です: %v（0〜1にしてください）", s.Volume)
+	}
+
+	if _, ok := palettes[s.Palette]; !ok {
+		return fmt.Errorf("paletteの値が不正です: %q", s.Palette)
+	}
+
+	switch s.RevengeBullets {
+	case RevengeOff, RevengeLoop, RevengeAlways:
+	default:
+		return fmt.Errorf("revengeBulletsの値が不正です: %q（off・loop・alwaysのいずれかにしてください）", s.RevengeBullets)
+	}
+
+	if s.FPSCap != 0 && (s.FPSCap < minFPSCap || s.FPSCap > maxFPSCap) {
+		return fmt.Errorf("fpsCapの値が不正です: %d（0か%d〜%dにしてください）", s.FPSCap, minFPSCap, maxFPSCap)
+	}
+
+	if s.MaxParticles < minMaxParticles || s.MaxParticles > maxMaxParticles {
+		return fmt.Errorf("maxParticlesの値が不正です: %d（%d〜%dにしてください）", s.MaxParticles, minMaxParticles, maxMaxParticles)
+	}
+
+	switch s.Scaling {
+	case ScalingInteger, ScalingFit:
+	default:
+		return fmt.Errorf("scalingの値が不正です: %q（integer・fitのいずれかにしてください）", s.Scaling)
+	}
+
+	if err := s.audioConfig().Validate(); err != nil {
+		return fmt.Errorf("audioSampleRateかaudioBufferMsの値が不正です: %v", err)
+	}
+
+	settings = s
+	resolution = s.Resolution
+	palette = palettes[s.Palette]
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	shieldArcCos   = 0.5  // 正面からこの角度（cosで60度）以内に飛んできた弾を弾く
+	shieldTurnRate = 0.15 // 向きを進む方向へ合わせていく速さ（1フレームあたり）
+	shieldMinMove  = 0.1  // これより動きが小さいフレームは向きを変えない
+	shieldWidth    = 3    // 盾の厚さ
+)
+
+// FrontShield は進む向きの正面に盾を構えた敵のコンポーネントです。
+// 正面から来た自機弾は火花を散らして弾かれ、横や後ろから当てたときだけダメージが通ります
+type FrontShield struct {
+	faceX, faceY float64 // 正面の向き（単位ベクトル）
+}
+
+// newFrontShield は真下（出現したときに進む向き）を向いた盾を作ります
+func newFrontShield() *FrontShield {
+	return &FrontShield{faceX: 0, faceY: 1}
+}
+
+// turn はこのフレームで動いた量から、正面の向きを進む向きへ少しずつ合わせます。
+// サインカーブの揺れで盾が細かくぶれないよう、一気には向きを変えません
+func (s *FrontShield) turn(dx, dy float64) {
+	d := math.Hypot(dx, dy)
+	if d < shieldMinMove {
+		return
+	}
+	fx := s.faceX + (dx/d-s.faceX)*shieldTurnRate
+	fy := s.faceY + (dy/d-s.faceY)*shieldTurnRate
+	if n := math.Hypot(fx, fy); n > 0 {
+		s.faceX, s.faceY = fx/n, fy/n
+	}
+}
+
+// blocks は自機弾が盾のある正面から当たったかを返します。
+// 弾の進む向きの逆（弾が飛んできた向き）と正面の向きのなす角で判定します
+func (s *FrontShield) blocks(b *Bullet) bool {
+	speed := math.Hypot(b.vx, b.vy)
+	if speed == 0 {
+		return false
+	}
+	return (-b.vx*s.faceX-b.vy*s.faceY)/speed > shieldArcCos
+}
+
+// deflectBullet は盾に弾かれた弾の火花を、弾が跳ね返る向きに散らします
+func (g *Game) deflectBullet(b *Bullet) {
+	g.emitParticlesToward("shieldDeflect", b.x+2, b.y, math.Atan2(-b.vy, -b.vx))
+}
+
+// drawFrontShield は敵の正面側に盾を描画します
+func drawFrontShield(field *ebiten.Image, e Enemy) {
+	s := e.frontShield
+	if s == nil {
+		return
+	}
+	w, h := enemySize(e.enemyType)
+	cx, cy := e.x+w/2, e.y+h/2
+	// 正面の向きに垂直な線を、敵の外側に沿って厚さの分だけ重ねて描く
+	half := math.Max(w, h) / 2
+	px, py := -s.faceY, s.faceX
+	for i := 0; i < shieldWidth; i++ {
+		r := half + 2 + float64(i)
+		mx, my := cx+s.faceX*r, cy+s.faceY*r
+		ebitenutil.DrawLine(field, mx-px*half, my-py*half, mx+px*half, my+py*half, color.RGBA{120, 220, 255, 255})
+	}
+}
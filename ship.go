@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"os"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+	"SimpleShootingStar/i18n"
+	"SimpleShootingStar/tween"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const shipCursorFrames = 12 // 自機選択画面の枠が次の自機へ滑っていくフレーム数
+
+// Ship は選択できる自機の性能を表す構造体
+type Ship struct {
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	Speed        float64   `json:"speed"`        // 移動速度（ピクセル/フレーム）
+	Handling     string    `json:"handling"`     // 操作感（instant:即座に最高速, inertial:加速・減速あり、省略時はinstant）
+	Accel        float64   `json:"accel"`        // inertialのときの加速度（ピクセル/フレーム²）
+	Decel        float64   `json:"decel"`        // inertialのときの減速度（ピクセル/フレーム²）
+	ShotAngles   []float64 `json:"shotAngles"`   // 各弾の発射角度（度、0が真上）
+	ShotOffsets  []float64 `json:"shotOffsets"`  // 各弾の発射位置（自機左端からのx方向オフセット）
+	ShotSpeed    float64   `json:"shotSpeed"`    // 弾速
+	ShotCooldown int       `json:"shotCooldown"` // 発射間隔（フレーム）
+	ShotDamage   int       `json:"shotDamage"`   // 弾1発のダメージ（省略時は1）
+	ShotPierce   int       `json:"shotPierce"`   // 弾が貫通できる敵の数（0なら最初に当たった敵で消える）
+	ShotBounces  int       `json:"shotBounces"`  // 弾が画面端で跳ね返る回数
+	ShotBlast    float64   `json:"shotBlast"`    // 当たったときの爆風の半径（0なら爆風なし）
+	HitboxWidth  float64   `json:"hitboxWidth"`  // 当たり判定の幅
+	HitboxHeight float64   `json:"hitboxHeight"` // 当たり判定の高さ
+}
+
+// ShipData はJSONファイルから読み込む自機データの構造体
+type ShipData struct {
+	Ships []Ship `json:"ships"`
+}
+
+const shipFile = "ship/ships.json" // 自機の設定ファイル
+
+var ships []Ship
+
+// loadShips はJSONファイルから自機情報を読み込みます
+func loadShips() error {
+	file, err := os.ReadFile(shipFile)
+	if err != nil {
+		return fmt.Errorf("自機ファイルの読み込みに失敗: %v", err)
+	}
+
+	var shipData ShipData
+	if err := json.Unmarshal(file, &shipData); err != nil {
+		return fmt.Errorf("JSONのパースに失敗: %v", err)
+	}
+	if len(shipData.Ships) == 0 {
+		return fmt.Errorf("自機が1つも定義されていません")
+	}
+	for _, s := range shipData.Ships {
+		if s.Speed <= 0 || s.ShotCooldown <= 0 {
+			return fmt.Errorf("%s: speedとshotCooldownは1以上にしてください", s.Name)
+		}
+		if len(s.ShotAngles) != len(s.ShotOffsets) {
+			return fmt.Errorf("%s: shotAnglesとshotOffsetsの数が一致しません", s.Name)
+		}
+		if s.ShotPierce < 0 || s.ShotBlast < 0 {
+			return fmt.Errorf("%s: shotPierceとshotBlastは0以上にしてください", s.Name)
+		}
+		if err := validateHandling(s); err != nil {
+			return err
+		}
+	}
+
+	ships = shipData.Ships
+	return nil
+}
+
+// shotDamage は弾1発のダメージを返します
+func (s Ship) shotDamage() int {
+	if s.ShotDamage == 0 {
+		return 1
+	}
+	return s.ShotDamage
+}
+
+// ship は選択中の自機の性能を返します
+func (g *Game) ship() Ship {
+	return ships[g.selectedShip]
+}
+
+// playerHitbox は自機の当たり判定の矩形を返します。
+// 判定は自機の見た目（20x24）の中心に合わせて配置します
+func (g *Game) playerHitbox() (x, y, w, h float64) {
+	s := g.ship()
+	return g.playerX + 10 - s.HitboxWidth/2, g.playerY + 12 - s.HitboxHeight/2, s.HitboxWidth, s.HitboxHeight
+}
+
+// updateShipSelect は自機選択画面の入力を処理します
+func (g *Game) updateShipSelect() {
+	prev := g.selectedShip
+	if int(g.shipCursor.To) != prev {
+		// ほかの画面で自機を変えていたら、枠をすぐその位置に合わせる
+		g.shipCursor = tween.New(float64(prev), float64(prev), 0, nil)
+	}
+	if g.input.JustPressed(ebiten.KeyLeft) {
+		g.selectedShip = (g.selectedShip + len(ships) - 1) % len(ships)
+		g.sound.Play("menuCursor")
+	}
+	if g.input.JustPressed(ebiten.KeyRight) {
+		g.selectedShip = (g.selectedShip + 1) % len(ships)
+		g.sound.Play("menuCursor")
+	}
+	if g.selectedShip != prev {
+		g.shipCursor = tween.New(g.shipCursor.Value(), float64(g.selectedShip), shipCursorFrames, tween.OutBack)
+	}
+	g.shipCursor.Update()
+	if g.input.JustPressed(ebiten.KeySpace) {
+		g.sound.Play("menuConfirm")
+		g.startTransition(TransitionIris, func() {
+			g.gameState = GameStatePlaying
+			g.startStageIntro()
+			g.emit(Event{Kind: EventGameStarted})
+		}, func() {
+			g.sound.PlayBGM("stage")
+		})
+	}
+}
+
+// drawShipSelect は自機選択画面を描画します
+func (g *Game) drawShipSelect(screen *ebiten.Image) {
+	titleText := i18n.T("shipSelect.title")
+	hud.DrawTextOutline(screen, titleText, fonts.Face(fonts.Large), resolution.Width/2, resolution.Height/5, hud.AlignCenter, color.White, hud.OutlineColor)
+
+	// 自機のプレビューを横に並べ、選択中の自機を枠で囲む。枠は選び直すと滑って移動する
+	slotWidth := float64(resolution.Width) / float64(len(ships))
+	cursorX := slotWidth*g.shipCursor.Value() + slotWidth/2
+	ebitenutil.DrawRect(screen, cursorX-40, float64(resolution.Height)*0.45-40, 80, 80, color.RGBA{0, 255, 0, 60})
+	for i, s := range ships {
+		cx := slotWidth*float64(i) + slotWidth/2
+		cy := float64(resolution.Height) * 0.45
+
+		drawShipShape(screen, cx-10, cy-4, color.RGBA{0, 255, 0, 255})
+		// 当たり判定の大きさを半透明の赤で表示
+		ebitenutil.DrawRect(screen, cx-s.HitboxWidth/2, cy-s.HitboxHeight/2, s.HitboxWidth, s.HitboxHeight, color.RGBA{255, 0, 0, 120})
+
+		hud.DrawTextShadow(screen, s.Name, fonts.Face(fonts.Medium), int(cx), int(cy)+ui(64), hud.AlignCenter, color.White)
+	}
+
+	s := g.ship()
+	// 説明文は言語ファイルに訳があればそちらを使う
+	description, ok := i18n.Lookup("ship." + s.Name)
+	if !ok {
+		description = s.Description
+	}
+	statsText := i18n.Tf("shipSelect.stats", s.Speed, len(s.ShotAngles), 60/s.ShotCooldown, s.shotDamage())
+	guideText := i18n.T("shipSelect.guide")
+	hud.DrawTextShadow(screen, description, fonts.Face(fonts.Medium), resolution.Width/2, resolution.Height*3/4-ui(20), hud.AlignCenter, color.White)
+	hud.DrawTextShadow(screen, statsText, fonts.Face(fonts.Small), resolution.Width/2, resolution.Height*3/4+ui(10), hud.AlignCenter, color.White)
+	hud.DrawTextShadow(screen, guideText, fonts.Face(fonts.Small), resolution.Width/2, resolution.Height*7/8, hud.AlignCenter, color.White)
+}
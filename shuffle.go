@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// WaveShuffle はステージのウェーブの並びを入れ替える設定です。
+// ステージに指定すると、編隊や同時に出るウェーブをひとまとまりにしたまま順番と横位置を入れ替えます。
+// ボスのウェーブとそれより後ろのウェーブは入れ替えません
+type WaveShuffle struct {
+	Seed       int64 `json:"seed"`       // 乱数の種。同じ種なら毎回同じ並びになる（周回ごとに1ずつずらす）
+	XRange     int   `json:"xRange"`     // 横位置をずらす最大の幅（ピクセル）
+	MinDelay   int   `json:"minDelay"`   // まとまりの出現から次のまとまりの出現までの最小フレーム数
+	MaxEnemies int   `json:"maxEnemies"` // 画面にこの数の敵がいる間は次のまとまりを出さない（0なら制限なし）
+}
+
+// validateShuffle はウェーブの入れ替えの設定を確認します
+func validateShuffle(s *WaveShuffle) error {
+	if s == nil {
+		return nil
+	}
+	if s.XRange < 0 || s.MinDelay < 0 || s.MaxEnemies < 0 {
+		return fmt.Errorf("shuffleのxRange・minDelay・maxEnemiesは0以上にしてください")
+	}
+	return nil
+}
+
+// waveGroups はウェーブを、一緒に出すまとまりの範囲に分けます。
+// 同じ編隊のウェーブと、delayが0で直前のウェーブと同時に出るウェーブは同じまとまりです
+func waveGroups(waves []Wave) [][2]int {
+	var groups [][2]int
+	for i := range waves {
+		if !startsWaveGroup(waves, i) {
+			groups[len(groups)-1][1] = i + 1
+			continue
+		}
+		groups = append(groups, [2]int{i, i + 1})
+	}
+	return groups
+}
+
+// startsWaveGroup はi番目のウェーブが新しいまとまりの先頭かを返します
+func startsWaveGroup(waves []Wave, i int) bool {
+	if i == 0 {
+		return true
+	}
+	w, prev := waves[i], waves[i-1]
+	return w.Delay != 0 && (w.Formation == 0 || w.Formation != prev.Formation)
+}
+
+// shuffleWaves は種から決まる順番にまとまりを並べ替え、横位置をずらしたウェーブを返します。
+// まとまりの間のdelayは元の並びの位置のものを使い、minDelayより短くはしません
+func shuffleWaves(waves []Wave, s WaveShuffle, seed int64) []Wave {
+	r := rand.New(rand.NewSource(seed))
+	groups := waveGroups(waves)
+	fixed := len(groups)
+	for i, grp := range groups {
+		if waves[grp[0]].EnemyType == EnemyTypeBoss {
+			fixed = i
+			break
+		}
+	}
+	delays := make([]int, fixed)
+	for i := range delays {
+		delays[i] = waves[groups[i][0]].Delay
+	}
+	order := r.Perm(fixed)
+
+	result := make([]Wave, 0, len(waves))
+	for i, gi := range order {
+		grp := groups[gi]
+		shift := 0
+		if s.XRange > 0 {
+			shift = r.Intn(2*s.XRange+1) - s.XRange
+		}
+		for j := grp[0]; j < grp[1]; j++ {
+			w := waves[j]
+			w.X = min(max(w.X+shift, 0), stageBaseWidth-20)
+			if j == grp[0] {
+				w.Delay = delays[i]
+				if i > 0 {
+					w.Delay = max(w.Delay, s.MinDelay)
+				}
+			}
+			result = append(result, w)
+		}
+	}
+	if fixed < len(groups) {
+		result = append(result, waves[groups[fixed][0]:]...)
+	}
+	return result
+}
+
+// shuffleAllows は入れ替えの設定があるステージで、次のまとまりを出してよいかを返します。
+// 前のまとまりからminDelay経っていないか、画面の敵がmaxEnemiesに達している間は出しません。
+// まとまりの2つ目以降のウェーブは、先頭のウェーブに続けてそのまま出します
+func (g *Game) shuffleAllows() bool {
+	s := g.stage().Shuffle
+	if s == nil || g.currentSpawn == 0 || !startsWaveGroup(g.waves, g.currentSpawn) {
+		return true
+	}
+	if g.framesSinceSpawn < s.MinDelay {
+		return false
+	}
+	if s.MaxEnemies > 0 {
+		count := 0
+		for _, e := range g.enemies {
+			if e.parentID == 0 {
+				count++
+			}
+		}
+		if count >= s.MaxEnemies {
+			return false
+		}
+	}
+	return true
+}
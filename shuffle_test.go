@@ -0,0 +1,77 @@
+package main
+
+import "testing"
+
+func TestStartsWaveGroup(t *testing.T) {
+	waves := []Wave{
+		{Delay: 60},
+		{Delay: 0},                // 直前と同時
+		{Delay: 30, Formation: 1}, // 新しい編隊
+		{Delay: 10, Formation: 1}, // 同じ編隊の続き
+		{Delay: 10, Formation: 2}, // 別の編隊
+		{Delay: 10},
+	}
+	want := []bool{true, false, true, false, true, true}
+	for i := range waves {
+		if got := startsWaveGroup(waves, i); got != want[i] {
+			t.Errorf("startsWaveGroup(%d) = %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestShuffleWavesKeepsStageCoordinates(t *testing.T) {
+	waves := []Wave{{X: 0, Delay: 60}, {X: 300, Delay: 60}, {X: stageBaseWidth - 20, Delay: 60}}
+	tests := []struct {
+		name   string
+		xRange int
+	}{
+		{"no shift", 0},
+		{"wide shift", stageBaseWidth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(0); seed < 20; seed++ {
+				for _, w := range shuffleWaves(waves, WaveShuffle{XRange: tt.xRange}, seed) {
+					if w.X < 0 || w.X > stageBaseWidth-20 {
+						t.Fatalf("seed %d: x = %d, want 0..%d", seed, w.X, stageBaseWidth-20)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestShuffleMinDelayOnlyAtGroupBoundaries(t *testing.T) {
+	// 編隊の2体目はまとまりの続きなので、minDelayを待たずに出る
+	waves := []Wave{
+		{EnemyType: EnemyTypeStraight, X: 100, Delay: 10, Formation: 1},
+		{EnemyType: EnemyTypeStraight, X: 140, Delay: 5, Formation: 1},
+		{EnemyType: EnemyTypeStraight, X: 300, Delay: 5},
+	}
+	tests := []struct {
+		name  string
+		spawn int
+		since int
+		want  bool
+	}{
+		{"first wave", 0, 0, true},
+		{"formation member", 1, 5, true},
+		{"next group too soon", 2, 10, false},
+		{"next group after minDelay", 2, 60, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(t)
+			stage := g.stage()
+			prev := stage.Shuffle
+			stage.Shuffle = &WaveShuffle{MinDelay: 60}
+			t.Cleanup(func() { stage.Shuffle = prev })
+			g.setWaves(waves)
+			g.currentSpawn = tt.spawn
+			g.framesSinceSpawn = tt.since
+			if got := g.shuffleAllows(); got != tt.want {
+				t.Errorf("shuffleAllows = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// headless はウィンドウも音も使わずにゲームを進めるシミュレーション中かを表します
+var headless bool
+
+// SimConfig はシミュレーションの設定です
+type SimConfig struct {
+	Frames     int    // 最大で進めるフレーム数
+	Stage      int    // 開始するステージ（0始まり）
+	Ship       int    // 使用する自機
+	Seed       int64  // 乱数の種
+	InputMode  string // "random"（ランダムに操作）または "idle"（何もしない）
+	Invincible bool   // 自機を無敵にしてステージの最後まで進める
+}
+
+// simInput はシミュレーション用の自動操縦の入力です。
+// 移動とショットのキーをランダムな長さだけ押し続けます
+type simInput struct {
+	rng     *rand.Rand
+	enabled bool
+	held    map[ebiten.Key]int // 押し続ける残りフレーム数
+	prev    map[ebiten.Key]bool
+	now     map[ebiten.Key]bool
+}
+
+var simKeys = []ebiten.Key{ebiten.KeyLeft, ebiten.KeyRight, ebiten.KeyUp, ebiten.KeyDown, ebiten.KeyShift, ebiten.KeyX, ebiten.KeyC}
+
+func newSimInput(rng *rand.Rand, enabled bool) *simInput {
+	return &simInput{
+		rng:     rng,
+		enabled: enabled,
+		held:    map[ebiten.Key]int{},
+		prev:    map[ebiten.Key]bool{},
+		now:     map[ebiten.Key]bool{},
+	}
+}
+
+// advance は1フレーム分の操作を決めます
+func (in *simInput) advance() {
+	in.prev, in.now = in.now, map[ebiten.Key]bool{}
+	if !in.enabled {
+		return
+	}
+	for _, k := range simKeys {
+		if in.held[k] > 0 {
+			in.held[k]--
+		} else if in.rng.Intn(30) == 0 {
+			in.held[k] = 5 + in.rng.Intn(30)
+		}
+		in.now[k] = in.held[k] > 0
+	}
+	// ボムとバレットタイムはめったに使わない
+	for _, k := range []ebiten.Key{ebiten.KeyX, ebiten.KeyC} {
+		if in.now[k] && in.rng.Intn(20) != 0 {
+			in.now[k] = false
+		}
+	}
+	// ショットは撃ちっぱなし
+	in.now[ebiten.KeySpace] = true
+}
+
+func (in *simInput) Pressed(key ebiten.Key) bool {
+	return in.now[key]
+}
+
+func (in *simInput) JustPressed(key ebiten.Key) bool {
+	return in.now[key] && !in.prev[key]
+}
+
+// newSilentGame はシミュレーションと同じく、音を鳴らさず統計にも記録しないゲームを作ります
+func newSilentGame() *Game {
+	prev := headless
+	headless = true
+	defer func() { headless = prev }()
+	return NewGame()
+}
+
+// SimReport はシミュレーションの結果です
+type SimReport struct {
+	Frames        int
+	Result        string
+	Spawned       int
+	Killed        int
+	Escaped       int
+	Deaths        int
+	Score         int
+	MaxBullets    int
+	bulletSamples int
+	bulletTotal   int
+}
+
+func init() {
+	subscribe(EventEnemyKilled, func(g *Game, _ Event) {
+		if g.simReport != nil {
+			g.simReport.Killed++
+		}
+	})
+	subscribe(EventEnemyEscaped, func(g *Game, _ Event) {
+		if g.simReport != nil {
+			g.simReport.Escaped++
+		}
+	})
+	subscribe(EventPlayerDied, func(g *Game, _ Event) {
+		if g.simReport != nil {
+			g.simReport.Deaths++
+		}
+	})
+}
+
+// runSimulation はウィンドウを開かずにGame.Updateを繰り返し、バランス調整用の結果を返します
+func runSimulation(cfg SimConfig) (SimReport, error) {
+	if cfg.Stage < 0 || cfg.Stage >= len(stages) {
+		return SimReport{}, fmt.Errorf("ステージ番号が不正です: %d", cfg.Stage)
+	}
+	if cfg.Ship < 0 || cfg.Ship >= len(ships) {
+		return SimReport{}, fmt.Errorf("自機の番号が不正です: %d", cfg.Ship)
+	}
+
+	headless = true
+	rand.Seed(cfg.Seed)
+	input := newSimInput(rand.New(rand.NewSource(cfg.Seed)), cfg.InputMode != "idle")
+
+	g := NewGame()
+	g.input = input
+	g.selectedShip = cfg.Ship
+	g.startStage(cfg.Stage)
+
+	report := &SimReport{Result: "timeout"}
+	g.simReport = report
+
+	for report.Frames < cfg.Frames {
+		input.advance()
+		if cfg.Invincible {
+			g.invincibleTimer = 2 // 更新の最初に1減るため2にしておく
+		}
+		if err := g.tick(); err != nil {
+			return *report, err
+		}
+		report.Frames++
+
+		if g.gameState == GameStatePlaying {
+			n := len(g.enemyBullets)
+			report.bulletTotal += n
+			report.bulletSamples++
+			if n > report.MaxBullets {
+				report.MaxBullets = n
+			}
+		}
+		if g.gameState == GameStateStageClear {
+			report.Result = "clear"
+			break
+		}
+		if g.gameState == GameStateGameOver {
+			report.Result = "gameover"
+			break
+		}
+	}
+	report.Spawned = g.currentSpawn
+	report.Score = g.score
+	return *report, nil
+}
+
+// AverageBullets はプレイ中の画面内の敵弾の平均数を返します
+func (r SimReport) AverageBullets() float64 {
+	if r.bulletSamples == 0 {
+		return 0
+	}
+	return float64(r.bulletTotal) / float64(r.bulletSamples)
+}
+
+// String は結果を人が読める形にします
+func (r SimReport) String() string {
+	return fmt.Sprintf(
+		"result: %s at frame %d (%.1fs)\nwaves spawned: %d\nenemies killed: %d\nenemies escaped: %d\ndeaths: %d\nscore: %d\nenemy bullets: avg %.1f, max %d",
+		r.Result, r.Frames, float64(r.Frames)/60, r.Spawned, r.Killed, r.Escaped, r.Deaths, r.Score, r.AverageBullets(), r.MaxBullets)
+}
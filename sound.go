@@ -0,0 +1,46 @@
+package main
+
+import (
+	"time"
+
+	"SimpleShootingStar/audio"
+)
+
+// Sound はゲームが鳴らす効果音とBGMです。
+// 通常はaudio.SoundManagerを使い、シミュレーションなど音を出さない場面では無音に差し替えます
+type Sound interface {
+	Play(name string)                           // 効果音を鳴らす
+	PlayAt(name string, x, screenWidth float64) // x座標に応じた左右の位置で効果音を鳴らす
+	PlayBGM(name string)                        // BGMを切り替える
+	PauseAll()                                  // 鳴っている音をその位置で一時停止する
+	ResumeAll()                                 // 一時停止した音を再開する
+	SetMasterVolume(volume float64, muted bool) // 全体の音量（0〜1）とミュートを設定する
+}
+
+func init() {
+	subscribe(EventEnemyKilled, func(g *Game, e Event) {
+		g.sound.PlayAt("explosion", e.X, playArea.width)
+		if e.EnemyType == EnemyTypeBoss {
+			g.sound.PlayBGM("stage")
+		}
+	})
+	subscribe(EventBombUsed, func(g *Game, _ Event) { g.sound.Play("bomb") })
+}
+
+// audioConfig は設定ファイルの値から音声の出力の設定を作ります
+func (s Settings) audioConfig() audio.Config {
+	return audio.Config{
+		SampleRate: s.AudioSampleRate,
+		BufferSize: time.Duration(s.AudioBufferMs) * time.Millisecond,
+	}
+}
+
+// silentSound は何も鳴らさないSoundです
+type silentSound struct{}
+
+func (silentSound) Play(string)                     {}
+func (silentSound) PlayAt(string, float64, float64) {}
+func (silentSound) PlayBGM(string)                  {}
+func (silentSound) PauseAll()                       {}
+func (silentSound) ResumeAll()                      {}
+func (silentSound) SetMasterVolume(float64, bool)   {}
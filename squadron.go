@@ -0,0 +1,95 @@
+package main
+
+import (
+	"image/color"
+
+	"SimpleShootingStar/i18n"
+)
+
+const squadronBonusPerMember = 300 // 編隊を全滅させたときの1機あたりのボーナス（倍率を掛ける前）
+
+// Formation は出現した1組の編隊です。stages.jsonで同じformation番号を書いたウェーブの敵が1組になります
+type Formation struct {
+	size     int  // 編隊の機数
+	spawned  int  // 出現した機数
+	resolved int  // 倒したか画面外へ逃した機数
+	broken   bool // 1機でも画面外へ逃したか
+}
+
+func init() {
+	subscribe(EventEnemyKilled, func(g *Game, e Event) { g.onFormationMemberKilled(e) })
+	subscribe(EventEnemyEscaped, func(g *Game, e Event) { g.onFormationMemberEscaped(e) })
+}
+
+// joinFormation はウェーブのformation番号に応じて、出現する敵を編隊に加え、編隊の番号を返します。
+// 同じ番号の編隊がすべて出現し終わっていれば（キャラバンでウェーブを繰り返したときなど）新しい編隊にします
+func (g *Game) joinFormation(wave Wave) int {
+	if wave.Formation == 0 {
+		return 0
+	}
+	if g.formations == nil {
+		g.formations = map[int]*Formation{}
+		g.openFormations = map[int]int{}
+	}
+	id, ok := g.openFormations[wave.Formation]
+	f := g.formations[id]
+	if !ok || f == nil || f.spawned >= f.size {
+		id = g.newEntityID()
+		f = &Formation{size: g.formationSize(wave.Formation)}
+		g.formations[id] = f
+		g.openFormations[wave.Formation] = id
+	}
+	f.spawned++
+	return id
+}
+
+// formationSize は今のステージでその番号の編隊に属するウェーブの数を返します
+func (g *Game) formationSize(number int) int {
+	size := 0
+	for _, w := range g.waves {
+		if w.Formation == number {
+			size++
+		}
+	}
+	return size
+}
+
+// resolveFormationMember は編隊の敵が1機片付いたことを数え、全機が片付いた編隊を返します。
+// まだ残っている機があればnilを返します
+func (g *Game) resolveFormationMember(id int) *Formation {
+	f := g.formations[id]
+	if f == nil {
+		return nil
+	}
+	f.resolved++
+	if f.resolved < f.size {
+		return nil
+	}
+	delete(g.formations, id)
+	return f
+}
+
+// onFormationMemberKilled は編隊の敵を倒した数を数え、1機も逃さずに全滅させたらボーナスを与えます
+func (g *Game) onFormationMemberKilled(e Event) {
+	f := g.resolveFormationMember(e.Formation)
+	if f == nil || f.broken {
+		return
+	}
+	bonus := squadronBonusPerMember * f.size * g.multiplier
+	g.score += bonus
+	g.addFloatingText(FloatingText{
+		x:     e.X,
+		y:     e.Y - 16,
+		text:  i18n.Tf("squadron.perfect", bonus),
+		timer: floatingTextFrames * 2,
+		color: color.RGBA{255, 215, 0, 255},
+	})
+}
+
+// onFormationMemberEscaped は編隊の敵が画面外へ逃げたら、その編隊のボーナスをなくします
+func (g *Game) onFormationMemberEscaped(e Event) {
+	if f := g.formations[e.Formation]; f != nil {
+		f.broken = true
+	}
+	g.resolveFormationMember(e.Formation)
+}
@@ -0,0 +1,20 @@
+// Package stagedef はゲーム本体とステージ関連のツール（cmd/wavepreviewなど）で共有する、
+// stages.jsonの座標系と敵の種類の定義をまとめています
+package stagedef
+
+import "fmt"
+
+// BaseWidth はstages.jsonのx座標が基準にしている画面の幅です
+const BaseWidth = 640
+
+// EnemyTypeNames は敵の種類の名前（enemyTypeの順）です。統計や言語ファイルのキー（enemy.<名前>）に使います
+var EnemyTypeNames = []string{"straight", "sine", "special", "boss", "miner", "carrier", "turret"}
+
+// ValidateDelay はウェーブのdelay（前のウェーブからのフレーム数）が負でないかを確かめます。
+// 負の値を許すと出現フレームが前のウェーブより前やステージ開始前になってしまいます
+func ValidateDelay(delay int) error {
+	if delay < 0 {
+		return fmt.Errorf("delayの値が不正です: %d（0以上にしてください）", delay)
+	}
+	return nil
+}
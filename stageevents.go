@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math/rand"
+	"path/filepath"
+	"sort"
+)
+
+// ステージイベントの種類
+const (
+	StageEventText       = "text"       // 画面にテキストの帯を出す
+	StageEventBGM        = "bgm"        // BGMを切り替える
+	StageEventBackground = "background" // 背景を切り替える
+	StageEventShake      = "shake"      // 画面を揺らす
+)
+
+const (
+	defaultEventTextFrames  = 120 // textの表示フレーム数の既定値
+	defaultEventShakeFrames = 30  // shakeのフレーム数の既定値
+	defaultEventShakePower  = 4   // shakeの揺れ幅（ピクセル）の既定値
+)
+
+// StageEvent はステージの途中で起きる演出です。stages.jsonのステージに"events"として並べると、
+// ウェーブの出現と同じタイマーでframeに達したときに実行します
+type StageEvent struct {
+	Frame      int         `json:"frame"`      // 実行するフレーム（ウェーブのdelayを足し合わせたものと同じ時間）
+	Type       string      `json:"type"`       // 種類（text・bgm・background・shake）
+	Text       string      `json:"text"`       // text: 表示する文字
+	Color      string      `json:"color"`      // text: 文字の色（#RRGGBB。省略時は白）
+	BGM        string      `json:"bgm"`        // bgm: 切り替えるBGMの名前（stage・bossなど）
+	Background *Background `json:"background"` // background: 切り替える背景（ステージのbackgroundと同じ書き方）
+	Duration   int         `json:"duration"`   // text: 表示フレーム数, shake: 揺らすフレーム数
+	Strength   float64     `json:"strength"`   // shake: 揺れ幅（ピクセル）
+
+	color color.RGBA
+}
+
+// prepareStageEvents はステージイベントを確かめて時間順に並べ、背景の画像を読み込みます。
+// ステージパックの背景の画像はパックのフォルダ（dir）からの相対パスで書けます
+func prepareStageEvents(stage *Stage, dir string) error {
+	for i := range stage.Events {
+		ev := &stage.Events[i]
+		switch ev.Type {
+		case StageEventText:
+			ev.color = color.RGBA{255, 255, 255, 255}
+			if ev.Color != "" {
+				c, err := parseHexColor(ev.Color)
+				if err != nil {
+					return err
+				}
+				ev.color = c
+			}
+			if ev.Duration == 0 {
+				ev.Duration = defaultEventTextFrames
+			}
+		case StageEventBGM:
+			if ev.BGM == "" {
+				return fmt.Errorf("bgmのイベントにBGMの名前がありません")
+			}
+		case StageEventBackground:
+			b := ev.Background
+			if b == nil {
+				return fmt.Errorf("backgroundのイベントに背景の設定がありません")
+			}
+			if b.Image != "" && dir != "" && !filepath.IsAbs(b.Image) {
+				b.Image = filepath.Join(dir, b.Image)
+			}
+			if err := b.prepare(); err != nil {
+				return err
+			}
+		case StageEventShake:
+			if ev.Duration == 0 {
+				ev.Duration = defaultEventShakeFrames
+			}
+			if ev.Strength == 0 {
+				ev.Strength = defaultEventShakePower
+			}
+		default:
+			return fmt.Errorf("イベントのtypeの値が不正です: %q", ev.Type)
+		}
+	}
+	sort.SliceStable(stage.Events, func(i, j int) bool { return stage.Events[i].Frame < stage.Events[j].Frame })
+	return nil
+}
+
+// updateStageEvents はウェーブのタイマーが達したステージイベントを順に実行します
+func (g *Game) updateStageEvents() {
+	// ボス練習ではボスのウェーブしか出さないので、ステージの途中の演出も起こさない
+	if g.practice != nil {
+		return
+	}
+	events := g.stage().Events
+	for g.nextStageEvent < len(events) && events[g.nextStageEvent].Frame <= g.waveTimer {
+		g.runStageEvent(events[g.nextStageEvent])
+		g.nextStageEvent++
+	}
+}
+
+// runStageEvent はステージイベントを1つ実行します
+func (g *Game) runStageEvent(ev StageEvent) {
+	switch ev.Type {
+	case StageEventText:
+		g.addOverlay(Overlay{
+			text:  ev.Text,
+			y:     int(playArea.height / 3),
+			timer: ev.Duration,
+			color: ev.color,
+			band:  color.RGBA{0, 0, 0, 140},
+		})
+	case StageEventBGM:
+		g.sound.PlayBGM(ev.BGM)
+	case StageEventBackground:
+		g.backgroundOverride = ev.Background
+		g.applyBackground()
+	case StageEventShake:
+		g.startShake(ev.Duration, ev.Strength)
+	}
+}
+
+// startShake は画面を指定したフレーム数だけ揺らします。揺れている途中なら強い方・長い方を残します
+func (g *Game) startShake(frames int, strength float64) {
+	g.shakeTimer = max(g.shakeTimer, frames)
+	g.shakeStrength = max(g.shakeStrength, strength)
+}
+
+// updateShake は揺れの残りフレーム数を減らします
+func (g *Game) updateShake() {
+	if g.shakeTimer > 0 {
+		g.shakeTimer--
+		if g.shakeTimer == 0 {
+			g.shakeStrength = 0
+		}
+	}
+}
+
+// shakeOffset はプレイエリアを描くときにずらす量を返します。揺れは終わりに近づくほど小さくなります
+func (g *Game) shakeOffset() (float64, float64) {
+	if g.shakeTimer == 0 {
+		return 0, 0
+	}
+	power := g.shakeStrength * min(1, float64(g.shakeTimer)/10)
+	return (rand.Float64()*2 - 1) * power, (rand.Float64()*2 - 1) * power
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+	"SimpleShootingStar/i18n"
+	"SimpleShootingStar/stagedef"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	stageFile = "stage/stages.json" // 標準のステージファイル
+	modsDir   = "mods"              // ステージパックを置くディレクトリ
+)
+
+// StagePack は一続きで遊ぶステージのまとまりです。
+// 標準のステージと、modsディレクトリのサブフォルダごとのパックがあります
+type StagePack struct {
+	Name   string  // タイトル画面に表示する名前
+	Dir    string  // modsの下のフォルダ名（標準のステージなら空）
+	Stages []Stage // 遊ぶステージ（appendのパックは標準のステージの後ろに続く）
+	Hash   string  // ステージデータのハッシュ。記録はこのハッシュごとに分けて残す
+}
+
+// stagePacks は読み込んだステージパックの一覧です。先頭は標準のステージです
+var stagePacks []StagePack
+
+// currentPack は選んでいるステージパックの番号です
+var currentPack int
+
+// stagePackSelect はステージパック選択画面のカーソルです
+type stagePackSelect struct {
+	cursor int
+}
+
+// stageHash はステージデータのハッシュ（SHA-256の先頭16桁）を返します。
+// 中身が1文字でも違うステージデータは別のハッシュになります
+func stageHash(data ...[]byte) string {
+	h := sha256.New()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// loadStageFile はステージファイルを読み込み、ファイルの中身もそのまま返します。
+// 背景画像のパスはdirからの相対パスとして扱います（dirが空ならカレントディレクトリから）
+func loadStageFile(path, dir string) (StageData, []byte, error) {
+	var stageData StageData
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return stageData, nil, fmt.Errorf("ステージファイルの読み込みに失敗: %v", err)
+	}
+	if err := json.Unmarshal(file, &stageData); err != nil {
+		return stageData, nil, fmt.Errorf("JSONのパースに失敗: %v", err)
+	}
+	if len(stageData.Stages) == 0 {
+		return stageData, nil, fmt.Errorf("%sにステージが1つも定義されていません", path)
+	}
+
+	for i := range stageData.Stages {
+		for _, w := range stageData.Stages[i].Waves {
+			if err := stagedef.ValidateDelay(w.Delay); err != nil {
+				return stageData, nil, fmt.Errorf("%sのウェーブの設定が不正です: %v", stageData.Stages[i].Name, err)
+			}
+			if err := validateEnemyType(w); err != nil {
+				return stageData, nil, fmt.Errorf("%sのウェーブの設定が不正です: %v", stageData.Stages[i].Name, err)
+			}
+			if err := validateBehavior(w); err != nil {
+				return stageData, nil, fmt.Errorf("%sのウェーブの設定が不正です: %v", stageData.Stages[i].Name, err)
+			}
+			if err := validateBossAttack(w); err != nil {
+				return stageData, nil, fmt.Errorf("%sのウェーブの設定が不正です: %v", stageData.Stages[i].Name, err)
+			}
+			if err := validateDrop(w); err != nil {
+				return stageData, nil, fmt.Errorf("%sのウェーブの設定が不正です: %v", stageData.Stages[i].Name, err)
+			}
+		}
+		if err := validateGrade(stageData.Stages[i].Grade); err != nil {
+			return stageData, nil, fmt.Errorf("%sの設定が不正です: %v", stageData.Stages[i].Name, err)
+		}
+		if err := validateShuffle(stageData.Stages[i].Shuffle); err != nil {
+			return stageData, nil, fmt.Errorf("%sの設定が不正です: %v", stageData.Stages[i].Name, err)
+		}
+		b := &stageData.Stages[i].Background
+		if b.Image != "" && dir != "" && !filepath.IsAbs(b.Image) {
+			b.Image = filepath.Join(dir, b.Image)
+		}
+		if err := b.prepare(); err != nil {
+			return stageData, nil, fmt.Errorf("%sの背景の設定に失敗: %v", stageData.Stages[i].Name, err)
+		}
+		if err := prepareStageEvents(&stageData.Stages[i], dir); err != nil {
+			return stageData, nil, fmt.Errorf("%sのイベントの設定が不正です: %v", stageData.Stages[i].Name, err)
+		}
+	}
+	return stageData, file, nil
+}
+
+// loadStages は標準のステージと、modsディレクトリにあるステージパックを読み込みます。
+// 壊れたパックは読み飛ばし、標準のステージを選んだ状態にします
+func loadStages() error {
+	builtin, builtinFile, err := loadStageFile(stageFile, "")
+	if err != nil {
+		return err
+	}
+	stagePacks = []StagePack{{Name: i18n.T("pack.builtin"), Stages: builtin.Stages, Hash: stageHash(builtinFile)}}
+	slog.Info("ステージを読み込みました", "file", stageFile, "stages", len(builtin.Stages), "hash", stagePacks[0].Hash)
+
+	entries, err := os.ReadDir(modsDir)
+	if err != nil && !os.IsNotExist(err) {
+		slog.Warn("ステージパックの一覧の読み込みに失敗", "dir", modsDir, "err", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(modsDir, entry.Name())
+		data, file, err := loadStageFile(filepath.Join(dir, "stages.json"), dir)
+		if err != nil {
+			slog.Warn("ステージパックを読み飛ばします", "pack", entry.Name(), "err", err)
+			continue
+		}
+		pack := StagePack{Name: data.Name, Dir: entry.Name(), Stages: data.Stages, Hash: stageHash(file)}
+		if pack.Name == "" {
+			pack.Name = entry.Name()
+		}
+		if data.Append {
+			// 標準のステージをクリアした後に続けて遊ぶ
+			pack.Stages = append(append([]Stage{}, builtin.Stages...), data.Stages...)
+			pack.Hash = stageHash(builtinFile, file)
+		}
+		stagePacks = append(stagePacks, pack)
+		slog.Info("ステージパックを読み込みました", "pack", pack.Dir, "name", pack.Name, "stages", len(pack.Stages), "hash", pack.Hash)
+	}
+
+	selectStagePack(0)
+	return nil
+}
+
+// selectStagePack は遊ぶステージパックを切り替えます
+func selectStagePack(i int) {
+	if i != currentPack {
+		slog.Info("ステージパックを切り替えました", "pack", stagePacks[i].Dir, "name", stagePacks[i].Name)
+	}
+	currentPack = i
+	stages = stagePacks[i].Stages
+}
+
+// findStagePack はフォルダ名からステージパックの番号を探します
+func findStagePack(dir string) (int, bool) {
+	for i, p := range stagePacks {
+		if p.Dir == dir {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// updateStagePackSelect はステージパック選択画面の入力を処理します
+func (g *Game) updateStagePackSelect() {
+	sel := &g.stagePackSelect
+	if g.input.JustPressed(ebiten.KeyUp) {
+		sel.cursor = (sel.cursor + len(stagePacks) - 1) % len(stagePacks)
+		g.sound.Play("menuCursor")
+	}
+	if g.input.JustPressed(ebiten.KeyDown) {
+		sel.cursor = (sel.cursor + 1) % len(stagePacks)
+		g.sound.Play("menuCursor")
+	}
+	if g.input.JustPressed(ebiten.KeySpace) {
+		selectStagePack(sel.cursor)
+		g.sound.Play("menuConfirm")
+	} else if g.input.JustPressed(ebiten.KeyEscape) {
+		g.sound.Play("menuCancel")
+	}
+	if g.input.JustPressed(ebiten.KeySpace) || g.input.JustPressed(ebiten.KeyEscape) {
+		g.startTransition(TransitionFade, func() {
+			// 選んだパックの最初のステージの背景にする
+			g.currentStage = 0
+			g.setWaves(stages[0].Waves)
+			g.applyBackground()
+			g.highScore = stageRecords().HighScore
+			g.gameState = GameStateTitle
+		}, nil)
+	}
+}
+
+// drawStagePackSelect はステージパック選択画面を描画します
+func (g *Game) drawStagePackSelect(screen *ebiten.Image) {
+	hud.DrawTextOutline(screen, i18n.T("pack.title"), fonts.Face(fonts.Large), resolution.Width/2, ui(56), hud.AlignCenter, color.White, hud.OutlineColor)
+	for i, p := range stagePacks {
+		clr := color.Color(color.White)
+		if i == g.stagePackSelect.cursor {
+			clr = color.RGBA{255, 255, 0, 255}
+		}
+		line := i18n.Tf("pack.entry", p.Name, len(p.Stages))
+		if i == currentPack {
+			line = "* " + line
+		}
+		hud.DrawTextShadow(screen, line, fonts.Face(fonts.Medium), resolution.Width/2-ui(220), ui(110+i*28), hud.AlignLeft, clr)
+	}
+	hud.DrawTextShadow(screen, i18n.T("pack.guide"), fonts.Face(fonts.Small), resolution.Width/2, resolution.Height-ui(20), hud.AlignCenter, color.White)
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"log/slog"
+	"os"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+	"SimpleShootingStar/i18n"
+	"SimpleShootingStar/stagedef"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const saveFile = "save.json" // セーブデータのファイル名
+
+// 統計で使う敵の種類の名前（enemyTypeの順）
+var enemyTypeNames = stagedef.EnemyTypeNames
+
+// Stats はプレイをまたいで累計する統計です
+type Stats struct {
+	PlayFrames    int            `json:"playFrames"`    // 累計プレイ時間（フレーム数）
+	GamesPlayed   int            `json:"gamesPlayed"`   // プレイ回数
+	ShotsFired    int            `json:"shotsFired"`    // ショットを撃った回数
+	EnemiesKilled map[string]int `json:"enemiesKilled"` // 敵の種類ごとの撃破数
+	Deaths        int            `json:"deaths"`        // やられた回数
+	BombsUsed     int            `json:"bombsUsed"`     // ボムを使った回数
+}
+
+// StageRecords はステージデータごとに分けて残す記録です。
+// ステージファイルを書き換えたりステージパックを使ったりしたときの記録は、別の記録として残ります
+type StageRecords struct {
+	HighScore      int                      `json:"highScore"`      // ハイスコア
+	BossesSeen     []int                    `json:"bossesSeen"`     // ボスに出会ったステージ（ボス練習で選べる）
+	BossBestFrames map[int]int              `json:"bossBestFrames"` // ステージごとのボス練習の最速撃破タイム（フレーム数）
+	TimeAttack     map[int]TimeAttackRecord `json:"timeAttack"`     // ステージごとのタイムアタックの自己ベスト
+	CaravanScores  []int                    `json:"caravanScores"`  // キャラバンのスコアの上位（高い順）
+	DailyScores    []int                    `json:"dailyScores"`    // デイリーチャレンジのその日のスコアの上位（高い順）
+	BestGrades     map[int]string           `json:"bestGrades"`     // ステージごとのクリア時の最高評価
+}
+
+// SaveData はsave.jsonに保存する内容です
+type SaveData struct {
+	Stats        Stats                    `json:"stats"`
+	Records      map[string]*StageRecords `json:"records"`      // ステージデータのハッシュごとの記録
+	TutorialDone bool                     `json:"tutorialDone"` // チュートリアルを終えたか（初めて遊ぶときだけチュートリアルを挟む）
+
+	// ステージデータごとに分ける前の記録。読み込んだら標準のステージの記録へ移す
+	BossesSeen     []int                    `json:"bossesSeen,omitempty"`
+	BossBestFrames map[int]int              `json:"bossBestFrames,omitempty"`
+	TimeAttack     map[int]TimeAttackRecord `json:"timeAttack,omitempty"`
+	CaravanScores  []int                    `json:"caravanScores,omitempty"`
+}
+
+var saveData = SaveData{Stats: Stats{EnemiesKilled: map[string]int{}}}
+
+// loadSaveData はセーブデータを読み込みます。ファイルがなければ空のままにします
+func loadSaveData() error {
+	file, err := os.ReadFile(saveFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("セーブデータの読み込みに失敗: %v", err)
+	}
+	if err := json.Unmarshal(file, &saveData); err != nil {
+		return fmt.Errorf("セーブデータのパースに失敗: %v", err)
+	}
+	if saveData.Stats.EnemiesKilled == nil {
+		saveData.Stats.EnemiesKilled = map[string]int{}
+	}
+	migrateRecords()
+	if saveData.Stats.GamesPlayed > 0 {
+		// チュートリアルを入れる前から遊んでいた人には初回のチュートリアルを挟まない
+		saveData.TutorialDone = true
+	}
+	return nil
+}
+
+// migrateRecords はステージデータごとに分ける前の記録を、今の標準のステージとキャラバンの記録へ移します
+func migrateRecords() {
+	r := recordsFor(stagePacks[0].Hash)
+	if len(r.BossesSeen) == 0 {
+		r.BossesSeen = saveData.BossesSeen
+	}
+	if r.BossBestFrames == nil {
+		r.BossBestFrames = saveData.BossBestFrames
+	}
+	if r.TimeAttack == nil {
+		r.TimeAttack = saveData.TimeAttack
+	}
+	if c := recordsFor(caravanHash); len(c.CaravanScores) == 0 {
+		c.CaravanScores = saveData.CaravanScores
+	}
+	saveData.BossesSeen, saveData.BossBestFrames, saveData.TimeAttack, saveData.CaravanScores = nil, nil, nil, nil
+}
+
+// recordsFor はステージデータのハッシュに対応する記録を返します。なければ作ります
+func recordsFor(hash string) *StageRecords {
+	if saveData.Records == nil {
+		saveData.Records = map[string]*StageRecords{}
+	}
+	r, ok := saveData.Records[hash]
+	if !ok {
+		r = &StageRecords{}
+		saveData.Records[hash] = r
+	}
+	return r
+}
+
+// stageRecords は選んでいるステージパックの記録を返します
+func stageRecords() *StageRecords {
+	if len(stagePacks) == 0 {
+		return &StageRecords{}
+	}
+	return recordsFor(stagePacks[currentPack].Hash)
+}
+
+// normalRun は本編を遊んでいるか（練習やタイムアタックなどのモードでないか）を返します
+func (g *Game) normalRun() bool {
+	return g.practice == nil && g.timeAttack == nil && g.caravan == nil && g.daily == nil && g.tutorial == nil
+}
+
+// updateHighScore は本編のスコアが遊んでいるステージデータの記録を超えていれば記録を更新します。
+// ほかのモードのスコアはハイスコアにしません
+func (g *Game) updateHighScore() {
+	if !g.normalRun() {
+		return
+	}
+	r := stageRecords()
+	if g.score > r.HighScore {
+		r.HighScore = g.score
+	}
+	g.highScore = r.HighScore
+}
+
+// writeSaveData はセーブデータを書き出します
+func writeSaveData() error {
+	file, err := json.MarshalIndent(saveData, "", "    ")
+	if err != nil {
+		return fmt.Errorf("セーブデータの作成に失敗: %v", err)
+	}
+	if err := os.WriteFile(saveFile, file, 0644); err != nil {
+		return fmt.Errorf("セーブデータの書き込みに失敗: %v", err)
+	}
+	return nil
+}
+
+// saveStats はセーブデータを書き出します。失敗してもゲームは続けます
+func saveStats() {
+	if headless {
+		return
+	}
+	if err := writeSaveData(); err != nil {
+		slog.Error("統計の保存に失敗", "err", err)
+	}
+}
+
+func init() {
+	for _, kind := range []EventKind{EventGameStarted, EventPlayFrame, EventShotFired, EventEnemyKilled, EventPlayerDied, EventBombUsed} {
+		subscribe(kind, recordStats)
+	}
+}
+
+// recordStats はゲーム中の出来事を統計に加えます
+func recordStats(g *Game, e Event) {
+	if !g.recordsStats {
+		return
+	}
+	s := &saveData.Stats
+	switch e.Kind {
+	case EventGameStarted:
+		s.GamesPlayed++
+	case EventPlayFrame:
+		s.PlayFrames++
+	case EventShotFired:
+		s.ShotsFired++
+	case EventEnemyKilled:
+		if e.EnemyType >= 0 && e.EnemyType < len(enemyTypeNames) {
+			s.EnemiesKilled[enemyTypeNames[e.EnemyType]]++
+		}
+	case EventPlayerDied:
+		s.Deaths++
+	case EventBombUsed:
+		s.BombsUsed++
+	}
+}
+
+// updateStats は統計画面の入力を処理します
+func (g *Game) updateStats() {
+	if g.input.JustPressed(ebiten.KeyEscape) || g.input.JustPressed(ebiten.KeyS) {
+		g.sound.Play("menuCancel")
+		g.startTransition(TransitionFade, func() {
+			g.gameState = GameStateTitle
+		}, nil)
+	}
+}
+
+// drawStats は統計画面を描画します
+func (g *Game) drawStats(screen *ebiten.Image) {
+	s := saveData.Stats
+	seconds := s.PlayFrames / 60
+	lines := []string{
+		i18n.Tf("stats.playTime", seconds/3600, seconds/60%60, seconds%60),
+		i18n.Tf("stats.gamesPlayed", s.GamesPlayed),
+		i18n.Tf("stats.shotsFired", s.ShotsFired),
+		i18n.Tf("stats.deaths", s.Deaths),
+		i18n.Tf("stats.bombsUsed", s.BombsUsed),
+	}
+	total := 0
+	for _, name := range enemyTypeNames {
+		total += s.EnemiesKilled[name]
+	}
+	lines = append(lines, i18n.Tf("stats.enemiesKilled", total))
+	for _, name := range enemyTypeNames {
+		lines = append(lines, fmt.Sprintf("  %s: %d", i18n.T("enemy."+name), s.EnemiesKilled[name]))
+	}
+
+	hud.DrawTextOutline(screen, i18n.T("stats.title"), fonts.Face(fonts.Large), resolution.Width/2, ui(56), hud.AlignCenter, color.White, hud.OutlineColor)
+	for i, line := range lines {
+		hud.DrawTextShadow(screen, line, fonts.Face(fonts.Medium), resolution.Width/2-ui(160), ui(100+i*24), hud.AlignLeft, color.White)
+	}
+	hud.DrawTextShadow(screen, i18n.T("stats.back"), fonts.Face(fonts.Small), resolution.Width/2, resolution.Height-ui(20), hud.AlignCenter, color.White)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const suspendFile = "suspend.json" // 中断セーブのファイル名
+
+// SuspendData はプレイを中断したときの状態です。再開すると削除します
+type SuspendData struct {
+	Pack       string  `json:"pack"`       // 遊んでいたステージパックのフォルダ名（標準のステージなら空）
+	StageHash  string  `json:"stageHash"`  // 遊んでいたステージデータのハッシュ
+	Stage      int     `json:"stage"`      // 再開するステージ（0始まり）
+	Ship       int     `json:"ship"`       // 選んでいた自機
+	Score      int     `json:"score"`      // スコア
+	Lives      int     `json:"lives"`      // 残機
+	Bombs      int     `json:"bombs"`      // ボムの残り数
+	Multiplier int     `json:"multiplier"` // スコア倍率
+	TokenGauge int     `json:"tokenGauge"` // 次の倍率までに集めたトークンの数
+	Rank       float64 `json:"rank"`       // ランク
+	Loop       int     `json:"loop"`       // 周回（0が1周目）
+	Magnet     int     `json:"magnet"`     // マグネットの段階
+}
+
+// suspended は読み込んだ中断セーブです。なければnil
+var suspended *SuspendData
+
+// loadSuspendData は中断セーブを読み込みます。ファイルがなければ何もしません
+func loadSuspendData() error {
+	file, err := os.ReadFile(suspendFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("中断セーブの読み込みに失敗: %v", err)
+	}
+	var data SuspendData
+	if err := json.Unmarshal(file, &data); err != nil {
+		return fmt.Errorf("中断セーブのパースに失敗: %v", err)
+	}
+	pack, ok := findStagePack(data.Pack)
+	if !ok {
+		return fmt.Errorf("中断セーブのステージパック(%s)が見つかりません", data.Pack)
+	}
+	if data.StageHash != stagePacks[pack].Hash {
+		// ステージファイルが書き換えられていたら、途中から再開すると辻褄が合わなくなる
+		return fmt.Errorf("中断セーブの後にステージデータが変わっています")
+	}
+	if data.Stage < 0 || data.Stage >= len(stagePacks[pack].Stages) || data.Ship < 0 || data.Ship >= len(ships) || data.Loop < 0 || data.Loop >= maxLoops {
+		return fmt.Errorf("中断セーブのステージか自機が範囲外です")
+	}
+	suspended = &data
+	return nil
+}
+
+// suspendData はプレイ中なら今の状態を中断セーブの内容にして返します。
+// プレイ中でない、またはゲームオーバーが確定しているときはnilを返します
+func (g *Game) suspendData() *SuspendData {
+	data := &SuspendData{
+		Pack:       stagePacks[currentPack].Dir,
+		StageHash:  stagePacks[currentPack].Hash,
+		Stage:      g.currentStage,
+		Ship:       g.selectedShip,
+		Score:      g.score,
+		Lives:      g.lives,
+		Bombs:      g.bombs,
+		Multiplier: g.multiplier,
+		TokenGauge: g.tokenGauge,
+		Rank:       g.rank,
+		Loop:       g.loop,
+		Magnet:     g.magnetLevel,
+	}
+	if !g.normalRun() {
+		return nil
+	}
+	switch g.gameState {
+	case GameStatePlaying:
+	case GameStatePlayerExplosion:
+		// やられた分の残機を減らしておく
+		if g.lives == 0 {
+			return nil
+		}
+		data.Lives--
+	case GameStateStageClear:
+		// クリアしたステージの次から再開する。最終ステージなら次の周の最初から
+		if g.progression().IsFinalStage() {
+			if !g.canStartNextLoop() {
+				return nil
+			}
+			data.Stage, data.Loop = -1, g.loop+1
+		}
+		data.Stage++
+	default:
+		return nil
+	}
+	return data
+}
+
+// suspendRun はプレイ中なら中断セーブを書き出します。書き出したらtrueを返します
+func (g *Game) suspendRun() bool {
+	data := g.suspendData()
+	if data == nil || headless {
+		return false
+	}
+	file, err := json.MarshalIndent(data, "", "    ")
+	if err == nil {
+		err = os.WriteFile(suspendFile, file, 0644)
+	}
+	if err != nil {
+		slog.Error("中断セーブの書き込みに失敗", "file", suspendFile, "err", err)
+		return false
+	}
+	suspended = data
+	slog.Info("中断セーブを書き出しました", "stage", data.Stage+1, "score", data.Score)
+	return true
+}
+
+// updateSuspend はプレイ中のEscキーで中断セーブを書き出してタイトルへ戻ります
+func (g *Game) updateSuspend() {
+	if !g.input.JustPressed(ebiten.KeyEscape) || !g.suspendRun() {
+		return
+	}
+	saveStats()
+	g.updateHighScore()
+	g.startTransition(TransitionFade, func() {
+		// 作り直したゲームでも暗転から明けるまでの演出は続ける。ハイスコアは記録から読み直す
+		ship, input, transition := g.selectedShip, g.input, g.transition
+		*g = *NewGame()
+		g.selectedShip, g.input, g.transition = ship, input, transition
+	}, nil)
+}
+
+// resumeRun は中断セーブから再開し、中断セーブを削除します
+func (g *Game) resumeRun() {
+	data := suspended
+	suspended = nil
+	if err := os.Remove(suspendFile); err != nil && !os.IsNotExist(err) {
+		slog.Warn("中断セーブの削除に失敗", "file", suspendFile, "err", err)
+	}
+	g.startTransition(TransitionIris, func() {
+		pack, _ := findStagePack(data.Pack)
+		selectStagePack(pack)
+		g.highScore = stageRecords().HighScore
+		g.selectedShip = data.Ship
+		g.startStage(data.Stage)
+		g.score = data.Score
+		g.stageStartScore = data.Score
+		g.scoreRoll.snap(data.Score)
+		g.lives = data.Lives
+		g.bombs = data.Bombs
+		g.multiplier = data.Multiplier
+		g.tokenGauge = data.TokenGauge
+		g.rank = data.Rank
+		g.loop = data.Loop
+		g.magnetLevel = min(max(data.Magnet, 0), maxMagnetLevel)
+	}, func() {
+		g.sound.PlayBGM("stage")
+	})
+}
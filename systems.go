@@ -0,0 +1,191 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+)
+
+// updateEnemies は敵ごとに、持っているコンポーネントに応じた処理を順に実行します
+func (g *Game) updateEnemies() {
+	var launched []Enemy // このフレームでキャリアから発進した子機
+	for i := range g.enemies {
+		e := &g.enemies[i]
+		if e.dead {
+			continue
+		}
+		prevX, prevY := e.x, e.y
+		e.time += 0.05
+		if e.flashTimer > 0 {
+			e.flashTimer--
+		}
+		if e.hpBarTimer > 0 {
+			e.hpBarTimer--
+		}
+
+		if !e.has(systemKamikaze) || g.updateKamikaze(e) {
+			g.moveEnemy(e)
+		}
+		if e.has(systemEvader) {
+			g.updateEvader(e)
+		}
+		if e.has(systemBoss) {
+			g.updateBossBrain(e)
+		}
+		if e.has(systemMineLayer) {
+			g.updateMineLayer(e)
+		}
+		if e.has(systemCarrier) {
+			if child, ok := g.updateCarrier(e); ok {
+				launched = append(launched, child)
+			}
+		}
+		// 復活直後は撃たない
+		if e.has(systemShooter) && g.ceaseFireTimer == 0 {
+			g.updateShooter(e)
+		}
+		if e.has(systemFrontShield) {
+			e.frontShield.turn(e.x-prevX, e.y-prevY)
+		}
+	}
+
+	// 発進した子機を追加（移動処理中に追加するとポインタが無効になるため後でまとめて追加）
+	for _, child := range launched {
+		g.addEnemy(child)
+	}
+}
+
+// moveEnemy は敵の種類ごとの動きで敵を移動させます。ボスの移動はupdateBossBrainで行います
+func (g *Game) moveEnemy(e *Enemy) {
+	switch e.enemyType {
+	case EnemyTypeStraight, EnemyTypeMiner, EnemyTypeCarrier:
+		e.y += e.speed
+	case EnemyTypeSine:
+		e.y += e.speed
+		e.x += math.Sin(e.time) * 3
+	case EnemyTypeSpecial:
+		switch e.phase {
+		case 0: // 上昇
+			e.y += e.speed
+			if e.y > playArea.height/2 {
+				e.phase = 1
+			}
+		case 1: // 横移動
+			e.x += e.speed * float64(e.turnDirection)
+			if (e.turnDirection == 1 && e.x > playArea.width-40) || (e.turnDirection == -1 && e.x < 20) {
+				e.phase = 2
+			}
+		case 2: // 下降
+			e.y += e.speed
+		}
+	case EnemyTypeTurret:
+		// 親に合わせて移動し、弾は通常の弾発射処理で自機を狙う
+		g.followParent(e)
+	}
+}
+
+// updateBossBrain はボスの行動パターンを進めます
+func (g *Game) updateBossBrain(e *Enemy) {
+	b := e.boss
+	bt := tuning.Boss
+	b.timer++
+
+	switch b.state {
+	case 0: // 移動状態
+		// 画面上部の定位置まで減速しながら降りてくる
+		if !b.entrance.Done() {
+			b.entrance.Update()
+			e.y = b.entrance.Value()
+		} else {
+			// 左右に移動
+			e.x += e.speed * float64(b.moveDirection)
+
+			// 端に到達したら方向転換
+			if e.x <= 50 {
+				b.moveDirection = 1
+			} else if e.x >= playArea.width-90 {
+				b.moveDirection = -1
+			}
+
+			// 一定時間移動したら攻撃準備へ
+			if b.timer > bt.MoveFrames {
+				g.setBossPhase(e, 1)
+			}
+		}
+	case 1: // 攻撃準備（前振り）
+		// 攻撃の前振りで一時停止
+		if b.timer > bt.WindupFrames {
+			g.setBossPhase(e, 2)
+		}
+	case 2: // 攻撃中
+		// ステージで決めた攻撃の命令を順に実行し、最後まで終えたら休憩へ
+		if g.runBossAttack(e) {
+			g.setBossPhase(e, 3)
+		}
+	case 3: // 休憩状態
+		// 次の攻撃まで休憩
+		if b.timer > bt.RestFrames {
+			g.setBossPhase(e, 0)
+		}
+	}
+}
+
+// setBossPhase はボスの行動状態を切り替えて、イベントバスに通知します
+func (g *Game) setBossPhase(e *Enemy, phase int) {
+	e.boss.state = phase
+	e.boss.timer = 0
+	if phase == 2 {
+		// 攻撃の命令を先頭からやり直す
+		e.boss.step, e.boss.stepTimer, e.boss.fired, e.boss.shotSounded = 0, 0, 0, false
+	}
+	g.emit(Event{Kind: EventBossPhaseChanged, EntityID: e.id, EnemyType: e.enemyType, X: e.x, Y: e.y, Phase: phase})
+}
+
+// updateMineLayer は一定間隔で機雷を置いていきます
+func (g *Game) updateMineLayer(e *Enemy) {
+	e.mineLayer.timer--
+	if e.mineLayer.timer <= 0 && e.y > 0 {
+		g.dropMine(e.x+5, e.y+10)
+		e.mineLayer.timer = mineDropTime
+	}
+}
+
+// updateShooter は発射間隔を進め、時間になったら弾を撃ちます。
+// 弾速と発射間隔はランクに応じて変わります
+func (g *Game) updateShooter(e *Enemy) {
+	s := e.shooter
+	s.cooldown--
+	if s.cooldown > 0 {
+		return
+	}
+
+	rank := g.rankMultiplier()
+	base := tuning.EnemyShot.BulletSpeed
+	speed := base * rank * g.loopSpeedScale()
+	switch s.bulletType {
+	case 0: // 主人公狙い
+		dx := g.playerX - e.x
+		dy := g.playerY - e.y
+		dist := math.Hypot(dx, dy)
+		vx := dx / dist * speed
+		vy := dy / dist * speed
+		g.enemyBullets = append(g.enemyBullets, EnemyBullet{x: e.x + 10, y: e.y + 20, vx: vx, vy: vy, ownerID: e.id})
+		g.particles.add(Particle{x: e.x + 10, y: e.y + 20, vx: vx, vy: vy, size: 80, alpha: 1.0, lifetime: 5, ptype: 1})
+	case 1: // 真下
+		g.enemyBullets = append(g.enemyBullets, EnemyBullet{x: e.x + 10, y: e.y + 20, vx: 0, vy: speed, ownerID: e.id})
+		g.particles.add(Particle{x: e.x + 10, y: e.y + 20, vx: 0, vy: base, size: 80, alpha: 1.0, lifetime: 5, ptype: 1})
+	case 2: // 斜め右下
+		g.enemyBullets = append(g.enemyBullets, EnemyBullet{x: e.x + 10, y: e.y + 20, vx: speed / 2, vy: speed, ownerID: e.id})
+		g.particles.add(Particle{x: e.x + 10, y: e.y + 20, vx: base / 2, vy: base, size: 80, alpha: 1.0, lifetime: 5, ptype: 1})
+	case 3: // 斜め左下
+		g.enemyBullets = append(g.enemyBullets, EnemyBullet{x: e.x + 10, y: e.y + 20, vx: -speed / 2, vy: speed, ownerID: e.id})
+		g.particles.add(Particle{x: e.x + 10, y: e.y + 20, vx: -base / 2, vy: base, size: 80, alpha: 1.0, lifetime: 5, ptype: 1})
+	case 4: // レーザー（予告線の後に照射）
+		g.fireBeam(e.x+10, e.y+20)
+	}
+	s.cooldown = int(float64(tuning.EnemyShot.CooldownMin+rand.Intn(tuning.EnemyShot.CooldownRange)) / rank)
+	if s.bulletType == 4 {
+		s.cooldown += beamCooldown
+	} else {
+		g.sound.PlayAt("enemyShot", e.x+10, playArea.width)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+func TestCarrierChildrenDoNotLaunch(t *testing.T) {
+	tests := []struct {
+		name      string
+		childType int
+	}{
+		{"straight child", EnemyTypeStraight},
+		{"carrier child", EnemyTypeCarrier},
+		{"miner child", EnemyTypeMiner},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(t)
+			carrier := g.newEnemy(EnemyTypeCarrier, 300, 100, 0)
+			carrier.carrier.childType = tt.childType
+			carrier.carrier.spawnTimer = 1
+			g.addEnemy(carrier)
+
+			g.updateEnemies()
+			if len(g.enemies) != 2 {
+				t.Fatalf("enemies = %d, want carrier and one child", len(g.enemies))
+			}
+			child := g.enemies[1]
+			if child.parentID != carrier.id || child.enemyType != tt.childType {
+				t.Fatalf("child = parent %d type %d, want parent %d type %d", child.parentID, child.enemyType, carrier.id, tt.childType)
+			}
+			if child.has(systemCarrier) {
+				t.Errorf("child launches its own children")
+			}
+
+			// 子機がいくら進んでも孫は出てこない
+			g.enemies[0].carrier.spawnTimer = defaultSpawnInterval * 10
+			for i := 0; i < defaultSpawnInterval*2; i++ {
+				g.updateEnemies()
+			}
+			if got := g.countChildren(child.id); got != 0 {
+				t.Errorf("child launched %d enemies, want 0", got)
+			}
+		})
+	}
+}
+
+func TestResolveSystems(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(e *Enemy)
+		want  enemySystems
+	}{
+		{"plain", func(e *Enemy) {}, 0},
+		{"shooter", func(e *Enemy) { e.shooter = newShooter(1) }, systemShooter},
+		{"evasive shield", func(e *Enemy) {
+			e.evader = &Evader{}
+			e.frontShield = newFrontShield()
+		}, systemEvader | systemFrontShield},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(t)
+			e := g.newEnemy(EnemyTypeStraight, 100, 100, 2)
+			tt.setup(&e)
+			g.addEnemy(e)
+			if got := g.enemies[0].systems; got != tt.want {
+				t.Errorf("systems = %b, want %b", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+	"SimpleShootingStar/i18n"
+	"SimpleShootingStar/tween"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	bombBonus        = 500  // ステージクリア時に残っているボム1つあたりのボーナス
+	noMissBonus      = 3000 // やられずにステージをクリアしたときのボーナス
+	tallyLineFrames  = 40   // 集計の行を1行ずつ出す間隔
+	tallyCountFrames = 30   // 集計の行の数字を数え上げるフレーム数
+	tallyHoldFrames  = 60   // 集計を出し終えてから次のステージへ自動で進むまでのフレーム数
+	scoreRollFrames  = 30   // HUDのスコアの表示が新しいスコアに追いつくまでのフレーム数
+)
+
+// TallyLine は集計の1行です
+type TallyLine struct {
+	label string // 表示する項目名（i18nで変換済み）
+	value int
+	count tween.Tween // 数字を0から数え上げる動き
+	total bool        // 合計の行か
+}
+
+// Tally はステージクリア時のボーナスの集計の演出です
+type Tally struct {
+	lines   []TallyLine
+	shown   int  // 表示している行の数
+	timer   int  // 次の行を出すまでのフレーム数
+	counted bool // 合計の行まで数え上げ終えたか
+	hold    int  // 数え上げ終えてからのフレーム数
+}
+
+// RollingScore は実際のスコアを追いかけるように数字を回して表示するためのカウンターです
+type RollingScore struct {
+	tween  tween.Tween
+	target int
+}
+
+func init() {
+	subscribe(EventPlayerDied, func(g *Game, _ Event) { g.stageMisses++ })
+}
+
+// startTally はステージクリアのボーナスをスコアに加え、集計の演出を始めます。
+// ボス練習とタイムアタックではボーナスを出しません
+func (g *Game) startTally() {
+	g.tally = nil
+	if g.practice != nil || g.timeAttack != nil {
+		return
+	}
+	t := &Tally{}
+	add := func(label string, value int, total bool) {
+		line := TallyLine{label: label, value: value, count: tween.New(0, float64(value), tallyCountFrames, tween.OutCubic), total: total}
+		if total {
+			line.count.OnComplete = func() { t.counted = true }
+		}
+		t.lines = append(t.lines, line)
+	}
+	add(i18n.T("tally.stageScore"), g.score-g.stageStartScore, false)
+	bonus := g.bombs * bombBonus * g.multiplier
+	add(i18n.Tf("tally.bombs", g.bombs), bonus, false)
+	if g.stageMisses == 0 {
+		add(i18n.T("tally.noMiss"), noMissBonus*g.multiplier, false)
+		bonus += noMissBonus * g.multiplier
+	}
+	if g.stageEscaped > 0 {
+		// 逃した敵の数と、最も多く逃したウェーブを出す
+		deduction := g.escapeDeduction(bonus)
+		wave, _ := g.worstEscapeWave()
+		label := i18n.Tf("tally.escaped", g.stageEscaped)
+		if wave > 0 {
+			label = i18n.Tf("tally.escapedWave", g.stageEscaped, wave)
+		}
+		add(label, -deduction, false)
+		bonus -= deduction
+	}
+	g.score += bonus
+	g.updateHighScore()
+	add(i18n.T("tally.total"), g.score-g.stageStartScore, true)
+	g.tally = t
+}
+
+// updateTally は集計の行を1行ずつ出して数字を数え上げます。出し終えたらtrueを返します
+func (g *Game) updateTally() bool {
+	t := g.tally
+	if t == nil {
+		return true
+	}
+	if t.shown < len(t.lines) {
+		t.timer--
+		if t.timer <= 0 {
+			t.shown++
+			t.timer = tallyLineFrames
+			if t.lines[t.shown-1].total {
+				g.sound.Play("tallyTotal")
+			} else {
+				g.sound.Play("tally")
+			}
+		}
+	}
+	for i := 0; i < t.shown; i++ {
+		t.lines[i].count.Update()
+	}
+	if t.counted {
+		t.hold++
+	}
+	return t.hold > tallyHoldFrames
+}
+
+// skipTally は集計の演出を飛ばしてすべての行を出し終えた状態にします。
+// すでに出し終えていればfalseを返します
+func (g *Game) skipTally() bool {
+	t := g.tally
+	if t == nil || t.counted {
+		return false
+	}
+	t.shown = len(t.lines)
+	for i := range t.lines {
+		t.lines[i].count.Finish()
+	}
+	return true
+}
+
+// drawTally は集計の行を描画します
+func (g *Game) drawTally(screen *ebiten.Image) {
+	t := g.tally
+	if t == nil {
+		return
+	}
+	y := resolution.Height/2 + ui(56)
+	for i := 0; i < t.shown; i++ {
+		line := t.lines[i]
+		clr := color.Color(color.White)
+		if line.total {
+			clr = color.RGBA{255, 255, 0, 255}
+			y += ui(6)
+		}
+		hud.DrawTextShadow(screen, line.label, fonts.Face(fonts.Small), resolution.Width/2-ui(150), y, hud.AlignLeft, clr)
+		hud.DrawTextShadow(screen, fmt.Sprint(int(line.count.Value()+0.5)), fonts.Face(fonts.Small), resolution.Width/2+ui(150), y, hud.AlignRight, clr)
+		y += ui(22)
+	}
+}
+
+// update は目標のスコアが変わっていれば、今の表示から新しいスコアへ動き直します
+func (r *RollingScore) update(target int) {
+	if target != r.target {
+		r.tween = tween.New(r.tween.Value(), float64(target), scoreRollFrames, tween.OutCubic)
+		r.target = target
+	}
+	r.tween.Update()
+}
+
+// value は表示するスコアを返します
+func (r *RollingScore) value() int {
+	return int(r.tween.Value() + 0.5)
+}
+
+// snap は表示を目標のスコアにすぐ合わせます
+func (r *RollingScore) snap(target int) {
+	r.target = target
+	r.tween = tween.New(float64(target), float64(target), 0, nil)
+}
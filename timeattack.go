@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"log/slog"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+	"SimpleShootingStar/i18n"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// timeAttackSplits はスプリットを取る区切りの数です。
+// ステージのウェーブの敵を1/4片付けるごとにその時点のタイムを記録します
+const timeAttackSplits = 4
+
+// TimeAttack はタイムアタックの状態です
+type TimeAttack struct {
+	stage    int   // 挑戦しているステージ（0始まり）
+	frames   int   // ステージ開始のバナーが消えてからのフレーム数
+	resolved int   // 倒したか画面外へ逃したウェーブの敵の数
+	splits   []int // 区切りごとのタイム（フレーム数）
+
+	previous    TimeAttackRecord // クリア時に比べる、今回より前の自己ベスト
+	hasPrevious bool             // 比べる自己ベストがあるか
+
+	replay Replay  // 今回の自機の動き
+	ghost  *Replay // ゴーストとして重ねる自己ベストのリプレイ（なければnil）
+}
+
+// TimeAttackRecord はステージごとのタイムアタックの自己ベストです
+type TimeAttackRecord struct {
+	Frames int   `json:"frames"` // クリアタイム（フレーム数）
+	Splits []int `json:"splits"` // 区切りごとのタイム（フレーム数）
+}
+
+// timeAttackSelect はステージ選択画面のカーソルです
+type timeAttackSelect struct {
+	cursor int
+}
+
+func init() {
+	// 親のいない敵（ウェーブで出現した敵）が片付くたびにスプリットを進める
+	resolve := func(g *Game, e Event) {
+		if g.timeAttack != nil && e.ParentID == 0 {
+			g.timeAttack.resolve()
+		}
+	}
+	subscribe(EventEnemyKilled, resolve)
+	subscribe(EventEnemyEscaped, resolve)
+}
+
+// resolve はウェーブの敵が1体片付いたことを数え、区切りに達したらタイムを記録します
+func (t *TimeAttack) resolve() {
+	t.resolved++
+	total := len(stages[t.stage].Waves)
+	for next := len(t.splits) + 1; next < timeAttackSplits && t.resolved*timeAttackSplits >= total*next; next++ {
+		t.splits = append(t.splits, t.frames)
+	}
+}
+
+// updateTimeAttackSelect はステージ選択画面の入力を処理します
+func (g *Game) updateTimeAttackSelect() {
+	if g.input.JustPressed(ebiten.KeyEscape) {
+		g.sound.Play("menuCancel")
+		g.startTransition(TransitionFade, func() {
+			g.gameState = GameStateTitle
+		}, nil)
+		return
+	}
+	sel := &g.timeAttackSelect
+	if g.input.JustPressed(ebiten.KeyUp) {
+		sel.cursor = (sel.cursor + len(stages) - 1) % len(stages)
+		g.sound.Play("menuCursor")
+	}
+	if g.input.JustPressed(ebiten.KeyDown) {
+		sel.cursor = (sel.cursor + 1) % len(stages)
+		g.sound.Play("menuCursor")
+	}
+	if g.input.JustPressed(ebiten.KeyLeft) {
+		g.selectedShip = (g.selectedShip + len(ships) - 1) % len(ships)
+		g.sound.Play("menuCursor")
+	}
+	if g.input.JustPressed(ebiten.KeyRight) {
+		g.selectedShip = (g.selectedShip + 1) % len(ships)
+		g.sound.Play("menuCursor")
+	}
+	if g.input.JustPressed(ebiten.KeySpace) {
+		g.sound.Play("menuConfirm")
+		stage := sel.cursor
+		g.startTransition(TransitionIris, func() {
+			g.startTimeAttack(stage)
+		}, func() {
+			g.sound.PlayBGM("stage")
+		})
+	}
+}
+
+// startTimeAttack は選んだステージを最初からタイムアタックで始めます
+func (g *Game) startTimeAttack(stage int) {
+	g.score = 0
+	g.lives = tuning.Player.InitialLives
+	g.bombs = tuning.Player.InitialBombs
+	g.resetMultiplier()
+	g.rank = 0
+	g.timeAttack = &TimeAttack{stage: stage, replay: Replay{Stage: stage, Ship: g.selectedShip}, ghost: loadReplay(stage)}
+	g.startStage(stage)
+}
+
+// updateTimeAttack はステージ開始のバナーが消えてからタイムを進めます。
+// やられて爆発している間もタイムは進みます
+func (g *Game) updateTimeAttack() {
+	t := g.timeAttack
+	if t == nil {
+		return
+	}
+	if g.gameState == GameStatePlaying && g.input.JustPressed(ebiten.KeyEscape) {
+		g.endTimeAttack()
+		return
+	}
+	if g.stageIntroTimer <= 0 {
+		t.frames++
+		g.recordReplay()
+	}
+}
+
+// onTimeAttackCleared はクリアタイムを確定させ、自己ベストなら記録します
+func (g *Game) onTimeAttackCleared() {
+	t := g.timeAttack
+	t.splits = append(t.splits, t.frames)
+	r := stageRecords()
+	if best, ok := r.TimeAttack[t.stage]; !ok || t.frames < best.Frames {
+		if r.TimeAttack == nil {
+			r.TimeAttack = map[int]TimeAttackRecord{}
+		}
+		// 比較用に今回の前の記録を残しておく
+		t.previous, t.hasPrevious = best, ok
+		r.TimeAttack[t.stage] = TimeAttackRecord{Frames: t.frames, Splits: t.splits}
+		saveStats()
+		t.replay.Frames = t.frames
+		if err := saveReplay(&t.replay); err != nil {
+			slog.Error("リプレイの保存に失敗", "err", err)
+		}
+		return
+	}
+	t.previous, t.hasPrevious = r.TimeAttack[t.stage], true
+}
+
+// endTimeAttack はタイムアタックを終えてステージ選択画面へ戻ります
+func (g *Game) endTimeAttack() {
+	g.startTransition(TransitionFade, func() {
+		g.timeAttack = nil
+		g.bossApproach = BossApproach{}
+		g.backgroundOverride = nil
+		g.enemies = []Enemy{}
+		g.enemyBullets = []EnemyBullet{}
+		g.gameState = GameStateTimeAttackSelect
+	}, nil)
+}
+
+// formatMillis はフレーム数を「分:秒.ミリ秒」の形にします
+func formatMillis(frames int) string {
+	ms := frames * 1000 / baseTPS
+	return fmt.Sprintf("%d:%02d.%03d", ms/60000, ms/1000%60, ms%1000)
+}
+
+// formatSplitDiff は自己ベストとの差を「+秒.ミリ秒」の形にし、速ければ緑、遅ければ赤の色を返します
+func formatSplitDiff(frames, record int) (string, color.Color) {
+	diff := (frames - record) * 1000 / baseTPS
+	if diff <= 0 {
+		return fmt.Sprintf("-%d.%03d", -diff/1000, -diff%1000), color.RGBA{80, 255, 120, 255}
+	}
+	return fmt.Sprintf("+%d.%03d", diff/1000, diff%1000), color.RGBA{255, 90, 90, 255}
+}
+
+// drawTimeAttackSelect はステージ選択画面を描画します
+func (g *Game) drawTimeAttackSelect(screen *ebiten.Image) {
+	hud.DrawTextOutline(screen, i18n.T("timeAttack.title"), fonts.Face(fonts.Large), resolution.Width/2, ui(56), hud.AlignCenter, color.White, hud.OutlineColor)
+	for i, s := range stages {
+		clr := color.Color(color.White)
+		if i == g.timeAttackSelect.cursor {
+			clr = color.RGBA{255, 255, 0, 255}
+		}
+		line := s.Name
+		if best, ok := stageRecords().TimeAttack[i]; ok {
+			line += "  " + i18n.Tf("timeAttack.best", formatMillis(best.Frames))
+		}
+		if grade, ok := stageRecords().BestGrades[i]; ok {
+			line += "  " + i18n.Tf("grade.best", grade)
+		}
+		hud.DrawTextShadow(screen, line, fonts.Face(fonts.Medium), resolution.Width/2-ui(220), ui(110+i*28), hud.AlignLeft, clr)
+	}
+	hud.DrawTextShadow(screen, i18n.Tf("practice.ship", g.ship().Name), fonts.Face(fonts.Medium), resolution.Width/2, resolution.Height-ui(72), hud.AlignCenter, color.White)
+	hud.DrawTextShadow(screen, i18n.T("timeAttack.guide"), fonts.Face(fonts.Small), resolution.Width/2, resolution.Height-ui(20), hud.AlignCenter, color.White)
+}
+
+// drawTimeAttackTimer はタイムをHUDの下に描画します
+func (g *Game) drawTimeAttackTimer(screen *ebiten.Image) {
+	if g.timeAttack == nil {
+		return
+	}
+	hud.DrawTextOutline(screen, formatMillis(g.timeAttack.frames), fonts.Face(fonts.Medium), resolution.Width/2, ui(60), hud.AlignCenter, color.White, hud.OutlineColor)
+}
+
+// drawTimeAttackResult はステージクリア画面に区切りごとのタイムと自己ベストとの差を描画します
+func (g *Game) drawTimeAttackResult(screen *ebiten.Image) {
+	t := g.timeAttack
+	y := resolution.Height/2 + ui(56)
+	for i, split := range t.splits {
+		label := i18n.Tf("timeAttack.split", i+1)
+		if i == len(t.splits)-1 {
+			label = i18n.T("timeAttack.total")
+		}
+		hud.DrawTextShadow(screen, label, fonts.Face(fonts.Small), resolution.Width/2-ui(150), y, hud.AlignLeft, color.White)
+		hud.DrawTextShadow(screen, formatMillis(split), fonts.Face(fonts.Small), resolution.Width/2+ui(40), y, hud.AlignRight, color.White)
+		if t.hasPrevious && i < len(t.previous.Splits) {
+			diff, clr := formatSplitDiff(split, t.previous.Splits[i])
+			hud.DrawTextShadow(screen, diff, fonts.Face(fonts.Small), resolution.Width/2+ui(150), y, hud.AlignRight, clr)
+		}
+		y += ui(20)
+	}
+}
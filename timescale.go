@@ -0,0 +1,121 @@
+package main
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+const (
+	slowMotionScale = 0.3 // スローモーション中の時間の進み方
+
+	baseTPS           = 60 // ゲームの移動量などの定数は1秒に60ステップ進む前提
+	maxStepsPerUpdate = 8  // 1回のUpdateで処理する最大ステップ数（処理落ち時に追いつこうとし続けないため）
+)
+
+// logicTPS は1秒あたりに進めるステップ数です。-tpsで変えるとスローや早送りになります
+var logicTPS = baseTPS
+
+// Update はEbitenのTPSに関係なく1秒にlogicTPSステップだけゲームを進めます
+func (g *Game) Update() error {
+	g.updatedSinceDraw = true
+	g.justPolled = nil
+
+	// ウィンドウを閉じるときは統計と、プレイ中なら中断セーブを保存してから終了する
+	if ebiten.IsWindowBeingClosed() {
+		g.suspendRun()
+		saveStats()
+		stopCPUProfile()
+		return ebiten.Termination
+	}
+
+	// ベンチマーク中は1回のUpdateで1ステップだけ進め、かかった時間を測る
+	if g.bench != nil {
+		return g.updateBench()
+	}
+
+	// ウィンドウがフォーカスを失っている間はゲームも音も止め、ためていた押した瞬間も捨てる
+	in, buffered := g.input.(bufferedInput)
+	if !ebiten.IsFocused() {
+		if buffered {
+			in.consume()
+		}
+		g.setAudioPaused(true)
+		return nil
+	}
+	if buffered {
+		g.justPolled = in.poll()
+	}
+
+	// デバッグコンソールを開いている間はゲームを止め、キー入力もコンソールだけが受け取る
+	if g.updateConsole() {
+		if buffered {
+			in.consume()
+		}
+		g.setAudioPaused(true)
+		return nil
+	}
+
+	g.updateWindowTitle()
+
+	// 音量のキーはどの画面でも、一時停止中でも効く
+	g.updateVolumeKeys()
+	if g.volumeIndicatorTimer > 0 {
+		g.volumeIndicatorTimer--
+	}
+	rate := g.debugStepRate(float64(logicTPS) / float64(ebiten.TPS()))
+	// ゲームが止まっている間は音も止め、進み始めたら同じフレームで再開する
+	g.setAudioPaused(rate == 0)
+	g.stepAccumulator += rate
+	for steps := 0; g.stepAccumulator >= 1; steps++ {
+		if steps == maxStepsPerUpdate {
+			g.stepAccumulator = 0
+			break
+		}
+		g.stepAccumulator--
+		if err := g.tick(); err != nil {
+			return err
+		}
+		if buffered {
+			in.consume()
+		}
+	}
+	return nil
+}
+
+// setAudioPaused はゲームの一時停止に合わせて効果音とBGMを止めたり再開したりします
+func (g *Game) setAudioPaused(paused bool) {
+	if paused == g.audioPaused {
+		return
+	}
+	g.audioPaused = paused
+	if paused {
+		g.sound.PauseAll()
+	} else {
+		g.sound.ResumeAll()
+	}
+}
+
+// startSlowMotion は数フレームの完全停止（ヒットストップ）の後、
+// 指定フレーム数だけスローモーションにします
+func (g *Game) startSlowMotion(freezeFrames, slowFrames int) {
+	g.hitStopTimer = freezeFrames
+	g.slowMotionTimer = slowFrames
+	g.timeAccumulator = 0
+}
+
+// advanceTime はタイムスケールに従って時間を進め、
+// このフレームでエンティティを更新するかどうかを返します。
+// メニューなどの入力処理はこの結果に関係なく毎フレーム行います
+func (g *Game) advanceTime() bool {
+	if g.hitStopTimer > 0 {
+		g.hitStopTimer--
+		return false
+	}
+	if g.slowMotionTimer > 0 {
+		g.slowMotionTimer--
+		g.timeAccumulator += slowMotionScale
+		if g.timeAccumulator < 1 {
+			return false
+		}
+		g.timeAccumulator--
+		return true
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"image/color"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// titleDemoDim はタイトル画面のデモの上に重ねて文字を読みやすくする暗さです
+var titleDemoDim = color.RGBA{0, 0, 0, 110}
+
+// newTitleDemo はタイトル画面の背景で動かすデモを作ります。
+// シミュレーションと同じく音も統計もない状態で、何も操作しない入力でゲームを進めます。
+// 自機は描かずに無敵にし、最初のステージのボス以外のウェーブを繰り返します
+func newTitleDemo() *Game {
+	d := newSilentGame()
+	d.demo = true
+	d.input = newSimInput(rand.New(rand.NewSource(1)), false)
+	d.cheatInvincible = true
+	d.gameState = GameStatePlaying
+	var waves []Wave
+	for _, w := range stages[0].Waves {
+		if w.EnemyType != EnemyTypeBoss {
+			waves = append(waves, w)
+		}
+	}
+	d.setWaves(waves)
+	// ステージの途中の演出は起こさない
+	d.nextStageEvent = len(d.stage().Events)
+	return d
+}
+
+// updateTitleDemo はタイトル画面の背景のデモを1フレーム進めます。
+// ウェーブを出し切ったら最初から繰り返し、ステージクリアにはしません
+func (g *Game) updateTitleDemo() {
+	if g.titleDemo == nil {
+		if len(stages) == 0 {
+			return
+		}
+		g.titleDemo = newTitleDemo()
+	}
+	d := g.titleDemo
+	if len(d.waves) == 0 {
+		return
+	}
+	if d.currentSpawn >= len(d.waves) {
+		d.currentSpawn = 0
+		d.waveTimer = 0
+	}
+	prev := headless
+	headless = true
+	defer func() { headless = prev }()
+	d.tick()
+}
+
+// drawTitleDemo はタイトル画面の背景のデモを描画し、文字が読めるように少し暗くします
+func (g *Game) drawTitleDemo(field *ebiten.Image) {
+	if g.titleDemo == nil {
+		return
+	}
+	g.titleDemo.drawField(field)
+	ebitenutil.DrawRect(field, 0, 0, playArea.width, playArea.height, titleDemoDim)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	tokenSize       = 8   // スタートークンの大きさ
+	tokenFallSpeed  = 1.2 // スタートークンが流れてくる速さ
+	tokenPickRange  = 24  // 自機の中心からこの距離以内のトークンを回収する
+	tokensPerKill   = 2   // 通常の敵が落とすトークンの数
+	tokensPerBoss   = 12  // ボスが落とすトークンの数
+	tokensPerLevel  = 10  // 倍率を1段上げるのに必要なトークンの数
+	maxMultiplier   = 5   // スコア倍率の上限
+	tokenSwayFactor = 0.6 // 横揺れの大きさ
+)
+
+// StarToken は倒した敵が落とす、スコア倍率を上げるアイテムです
+type StarToken struct {
+	id     int
+	x, y   float64
+	vx, vy float64 // vyは自機に引き寄せられている間だけ使う
+	phase  float64 // 横揺れの位相
+}
+
+func init() {
+	subscribe(EventPlayerDied, func(g *Game, _ Event) { g.resetMultiplier() })
+}
+
+// dropTokens は倒した敵の位置からスタートークンをばらまきます
+func (g *Game) dropTokens(e Enemy) {
+	n := tokensPerKill
+	if e.enemyType == EnemyTypeBoss {
+		n = tokensPerBoss
+	}
+	w, h := enemySize(e.enemyType)
+	for i := 0; i < n; i++ {
+		g.tokens = append(g.tokens, StarToken{
+			id:    g.newEntityID(),
+			x:     e.x + w/2 + (rand.Float64()-0.5)*w,
+			y:     e.y + h/2,
+			vx:    (rand.Float64() - 0.5) * 2,
+			phase: rand.Float64() * math.Pi * 2,
+		})
+	}
+}
+
+// updateTokens はスタートークンを流し、自機の近くのものを回収します。
+// マグネットの範囲に入ったものは横揺れをやめて自機へ引き寄せられます
+func (g *Game) updateTokens() {
+	px, py := g.playerX+10, g.playerY+12
+	newTokens := g.tokens[:0]
+	for _, t := range g.tokens {
+		t.phase += 0.1
+		if g.attract(t.x, t.y, &t.vx, &t.vy, px, py) {
+			t.x += t.vx
+			t.y += t.vy
+		} else {
+			t.vx *= 0.95
+			t.vy = 0
+			t.x += t.vx + math.Sin(t.phase)*tokenSwayFactor
+			t.y += tokenFallSpeed
+		}
+		if math.Hypot(t.x-px, t.y-py) < tokenPickRange {
+			g.collectToken()
+			g.emit(Event{Kind: EventPowerUpCollected, EntityID: t.id, X: t.x, Y: t.y})
+			continue
+		}
+		if t.y < playArea.height+tokenSize {
+			newTokens = append(newTokens, t)
+		}
+	}
+	g.tokens = newTokens
+}
+
+// collectToken はトークンを1つ回収してゲージを進め、満タンになったら倍率を上げます
+func (g *Game) collectToken() {
+	if g.multiplier >= maxMultiplier {
+		return
+	}
+	g.tokenGauge++
+	if g.tokenGauge >= tokensPerLevel {
+		g.tokenGauge = 0
+		g.multiplier++
+	}
+}
+
+// resetMultiplier はやられたときにスコア倍率とゲージを元に戻します
+func (g *Game) resetMultiplier() {
+	g.multiplier = 1
+	g.tokenGauge = 0
+}
+
+// multiplierGauge は次の倍率までのゲージの割合を0〜1で返します
+func (g *Game) multiplierGauge() float64 {
+	if g.multiplier >= maxMultiplier {
+		return 1
+	}
+	return float64(g.tokenGauge) / tokensPerLevel
+}
+
+// drawTokens はスタートークンを十字に光る星として描画します
+func (g *Game) drawTokens(field *ebiten.Image) {
+	c := color.RGBA{255, 230, 80, 255}
+	for _, t := range g.tokens {
+		// 位相に合わせて瞬くように大きさを変える
+		r := tokenSize/2 + math.Sin(t.phase*2)
+		ebitenutil.DrawLine(field, t.x-r, t.y, t.x+r, t.y, c)
+		ebitenutil.DrawLine(field, t.x, t.y-r, t.x, t.y+r, c)
+		ebitenutil.DrawRect(field, t.x-1, t.y-1, 2, 2, color.White)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// 画面切り替え演出の種類
+const (
+	TransitionFade = iota // 暗転
+	TransitionWipe        // 左から右へ黒い幕が横切る
+	TransitionIris        // 円が閉じて開く
+)
+
+const transitionFrames = 40 // 切り替え演出全体のフレーム数（前半で覆い、後半で開く）
+
+// Transition はゲームの状態を切り替えるときの演出です。
+// 画面が覆われきった時点でonCoveredを、演出が終わった時点でonDoneを呼び出します
+type Transition struct {
+	kind      int
+	timer     int
+	onCovered func()
+	onDone    func()
+}
+
+// startTransition は切り替え演出を開始します。演出中はゲームの状態を更新しません
+func (g *Game) startTransition(kind int, onCovered, onDone func()) {
+	if g.transition != nil {
+		return
+	}
+	g.transition = &Transition{kind: kind, onCovered: onCovered, onDone: onDone}
+}
+
+// updateTransition は切り替え演出を進めます。演出中ならtrueを返します
+func (g *Game) updateTransition() bool {
+	t := g.transition
+	if t == nil {
+		return false
+	}
+	t.timer++
+	if t.timer == transitionFrames/2 && t.onCovered != nil {
+		t.onCovered()
+	}
+	if t.timer >= transitionFrames {
+		g.transition = nil
+		if t.onDone != nil {
+			t.onDone()
+		}
+	}
+	return true
+}
+
+// coverage は画面が覆われている割合を0〜1で返します
+func (t *Transition) coverage() float64 {
+	half := float64(transitionFrames / 2)
+	if t.timer < transitionFrames/2 {
+		return float64(t.timer) / half
+	}
+	return float64(transitionFrames-t.timer) / half
+}
+
+// drawTransition は切り替え演出を画面全体に描画します
+func (g *Game) drawTransition(screen *ebiten.Image) {
+	t := g.transition
+	if t == nil {
+		return
+	}
+	rate := t.coverage()
+	sw, sh := float64(resolution.Width), float64(resolution.Height)
+	black := color.RGBA{0, 0, 0, 255}
+
+	switch t.kind {
+	case TransitionFade:
+		ebitenutil.DrawRect(screen, 0, 0, sw, sh, color.RGBA{0, 0, 0, uint8(255 * rate)})
+	case TransitionWipe:
+		// 前半は左から幕が伸び、後半は右へ抜けていく
+		w := sw * rate
+		if t.timer < transitionFrames/2 {
+			ebitenutil.DrawRect(screen, 0, 0, w, sh, black)
+		} else {
+			ebitenutil.DrawRect(screen, sw-w, 0, w, sh, black)
+		}
+	case TransitionIris:
+		// 画面中央の円の外側を横帯で塗りつぶす
+		cx, cy := sw/2, sh/2
+		r := math.Hypot(cx, cy) * (1 - rate)
+		const band = 2
+		for y := 0.0; y < sh; y += band {
+			dy := math.Abs(y + band/2 - cy)
+			if dy >= r {
+				ebitenutil.DrawRect(screen, 0, y, sw, band, black)
+				continue
+			}
+			half := math.Sqrt(r*r - dy*dy)
+			ebitenutil.DrawRect(screen, 0, y, cx-half, band, black)
+			ebitenutil.DrawRect(screen, cx+half, y, sw-(cx+half), band, black)
+		}
+	}
+}
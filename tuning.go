@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const tuningFile = "tuning.json" // ゲームバランスの調整値のファイル名
+
+// Tuning はtuning.jsonから読み込むゲームバランスの調整値です。
+// 再ビルドせずにバランスを調整できるよう、敵の耐久度やボスの行動の長さなどをまとめています。
+// 自機ごとの移動速度・ショットの速さ・連射間隔はship/ships.jsonで設定します
+type Tuning struct {
+	Player    PlayerTuning    `json:"player"`
+	EnemyHP   EnemyHPTuning   `json:"enemyHP"`
+	EnemyShot EnemyShotTuning `json:"enemyShot"`
+	Boss      BossTuning      `json:"boss"`
+	Rank      RankTuning      `json:"rank"`
+	Escape    EscapeTuning    `json:"escape"`
+}
+
+// PlayerTuning は自機の残機・ボム・復活の調整値です
+type PlayerTuning struct {
+	InitialLives      int     `json:"initialLives"`      // ゲーム開始時の残機（やられても復活できる回数）
+	InitialBombs      int     `json:"initialBombs"`      // ゲーム開始時のボム数
+	RespawnInvincible int     `json:"respawnInvincible"` // 復活後の無敵フレーム数
+	FocusSpeedScale   float64 `json:"focusSpeedScale"`   // 低速移動中の移動速度の倍率
+}
+
+// EnemyHPTuning は敵の種類ごとの耐久度です
+type EnemyHPTuning struct {
+	Straight int `json:"straight"`
+	Sine     int `json:"sine"`
+	Special  int `json:"special"`
+	Boss     int `json:"boss"`
+	Miner    int `json:"miner"`
+	Carrier  int `json:"carrier"`
+	Turret   int `json:"turret"` // stages.jsonで砲台の耐久度を省略したときの値
+}
+
+// EnemyShotTuning は雑魚敵の射撃の調整値です
+type EnemyShotTuning struct {
+	BulletSpeed   float64 `json:"bulletSpeed"`   // 敵弾の速さ（ランクの倍率を掛ける前）
+	CooldownMin   int     `json:"cooldownMin"`   // 発射間隔の最小フレーム数
+	CooldownRange int     `json:"cooldownRange"` // 発射間隔に足す乱数の幅
+}
+
+// BossTuning はボスの行動パターンの長さと弾幕の調整値です
+type BossTuning struct {
+	WarningFrames int     `json:"warningFrames"` // ボス出現前の警告表示フレーム数
+	MoveFrames    int     `json:"moveFrames"`    // 左右に移動するフレーム数
+	WindupFrames  int     `json:"windupFrames"`  // 攻撃の前振りのフレーム数
+	AttackFrames  int     `json:"attackFrames"`  // 弾幕を撃つフレーム数
+	ShotInterval  int     `json:"shotInterval"`  // 弾幕の発射間隔
+	RestFrames    int     `json:"restFrames"`    // 攻撃後の休憩のフレーム数
+	BulletSpeed   float64 `json:"bulletSpeed"`   // 弾幕の弾の速さ（ランクの倍率を掛ける前）
+	SpreadAngle   float64 `json:"spreadAngle"`   // 5way弾の隣り合う弾の角度の差（ラジアン）
+}
+
+// RankTuning はランク（難易度の自動調整）の上がり方と効き方です
+type RankTuning struct {
+	SurviveFrames float64 `json:"surviveFrames"` // 生き延びるだけでランクが最大になるフレーム数
+	ScoreRate     float64 `json:"scoreRate"`     // 得点1点あたりのランク上昇
+	DeathDrop     float64 `json:"deathDrop"`     // やられたときのランク低下
+	MaxMultiplier float64 `json:"maxMultiplier"` // ランク最大時の敵の弾速・発射頻度の倍率
+}
+
+// EscapeTuning は画面外へ敵を逃したときのペナルティです。0ならペナルティなし
+type EscapeTuning struct {
+	ScorePenalty int `json:"scorePenalty"` // 逃すたびにスコアから引く点数
+	BonusPenalty int `json:"bonusPenalty"` // 逃した1体ごとにステージクリアのボーナスから引く点数（スコア倍率を掛ける）
+}
+
+var tuning = defaultTuning()
+
+// defaultTuning は組み込みの調整値を返します。tuning.jsonがないときや、省略した項目はこの値になります
+func defaultTuning() Tuning {
+	return Tuning{
+		Player: PlayerTuning{
+			InitialLives:      2,
+			InitialBombs:      3,
+			RespawnInvincible: 120,
+			FocusSpeedScale:   0.5,
+		},
+		EnemyHP: EnemyHPTuning{
+			Straight: 2,
+			Sine:     3,
+			Special:  4,
+			Boss:     50,
+			Miner:    3,
+			Carrier:  12,
+			Turret:   8,
+		},
+		EnemyShot: EnemyShotTuning{
+			BulletSpeed:   4.0,
+			CooldownMin:   60,
+			CooldownRange: 60,
+		},
+		Boss: BossTuning{
+			WarningFrames: 120,
+			MoveFrames:    120,
+			WindupFrames:  60,
+			AttackFrames:  80,
+			ShotInterval:  8,
+			RestFrames:    90,
+			BulletSpeed:   3.0,
+			SpreadAngle:   0.3,
+		},
+		Rank: RankTuning{
+			SurviveFrames: 60 * 180,
+			ScoreRate:     1.0 / 100000,
+			DeathDrop:     0.3,
+			MaxMultiplier: 1.8,
+		},
+		Escape: EscapeTuning{
+			BonusPenalty: 200,
+		},
+	}
+}
+
+// loadTuning は調整値のファイルを読み込みます。ファイルがなければ組み込みの値のままにします
+func loadTuning() error {
+	file, err := os.ReadFile(tuningFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("調整値のファイルの読み込みに失敗: %v", err)
+	}
+
+	t := defaultTuning()
+	if err := json.Unmarshal(file, &t); err != nil {
+		return fmt.Errorf("JSONのパースに失敗: %v", err)
+	}
+
+	if t.Player.InitialLives < 0 || t.Player.InitialBombs < 0 || t.Player.RespawnInvincible < 0 || t.Player.FocusSpeedScale <= 0 {
+		return fmt.Errorf("playerの値が不正です")
+	}
+	hp := t.EnemyHP
+	for _, v := range []int{hp.Straight, hp.Sine, hp.Special, hp.Boss, hp.Miner, hp.Carrier, hp.Turret} {
+		if v < 1 {
+			return fmt.Errorf("enemyHPの値が不正です: %d（1以上にしてください）", v)
+		}
+	}
+	if t.EnemyShot.BulletSpeed <= 0 || t.EnemyShot.CooldownMin < 1 || t.EnemyShot.CooldownRange < 1 {
+		return fmt.Errorf("enemyShotの値が不正です")
+	}
+	b := t.Boss
+	if b.WarningFrames < 0 || b.MoveFrames < 1 || b.WindupFrames < 1 || b.AttackFrames < 1 || b.ShotInterval < 1 || b.RestFrames < 1 || b.BulletSpeed <= 0 {
+		return fmt.Errorf("bossの値が不正です")
+	}
+	if t.Rank.SurviveFrames <= 0 || t.Rank.ScoreRate < 0 || t.Rank.DeathDrop < 0 || t.Rank.MaxMultiplier < 1 {
+		return fmt.Errorf("rankの値が不正です")
+	}
+	if t.Escape.ScorePenalty < 0 || t.Escape.BonusPenalty < 0 {
+		return fmt.Errorf("escapeの値が不正です")
+	}
+
+	tuning = t
+	return nil
+}
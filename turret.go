@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"SimpleShootingStar/i18n"
+)
+
+const (
+	turretScore         = 200 // 砲台を破壊したときのスコア（砲台ごとにscoreで変えられる）
+	bossPartsMultiplier = 2   // 砲台をすべて壊してからボスを倒したときのボスのスコアの倍率
+)
+
+// TurretDef はボスに取り付ける砲台の定義です
+type TurretDef struct {
+	OffsetX float64 `json:"offsetX"` // ボス左上からの相対位置
+	OffsetY float64 `json:"offsetY"`
+	HP      int     `json:"hp"`
+	Armor   int     `json:"armor"`   // 自機弾のダメージを減らす装甲値
+	Score   int     `json:"score"`   // 破壊したときのスコア（省略時は200）
+	Exposed bool    `json:"exposed"` // trueなら残っていてもボス本体を守らない
+}
+
+// validateEnemyType はウェーブの敵とキャリアの子機の種類を確かめます。
+// 砲台はボスに取り付けるturretsでだけ出せるので、単独の敵としては出せません
+func validateEnemyType(w Wave) error {
+	if w.EnemyType < EnemyTypeStraight || w.EnemyType >= EnemyTypeTurret {
+		return fmt.Errorf("enemyTypeの値が不正です: %d（%d〜%dにしてください）", w.EnemyType, EnemyTypeStraight, EnemyTypeTurret-1)
+	}
+	if w.EnemyType == EnemyTypeCarrier && (w.ChildType < EnemyTypeStraight || w.ChildType >= EnemyTypeTurret) {
+		return fmt.Errorf("childTypeの値が不正です: %d（%d〜%dにしてください）", w.ChildType, EnemyTypeStraight, EnemyTypeTurret-1)
+	}
+	return nil
+}
+
+// turretPoints は砲台を破壊したときのスコアを返します。親に取り付けられていない砲台は既定のスコアです
+func turretPoints(e Enemy) int {
+	if e.mount == nil {
+		return turretScore
+	}
+	return e.mount.score
+}
+
+// spawnTurrets は親の敵に砲台を取り付けます
+func (g *Game) spawnTurrets(parent Enemy, defs []TurretDef) {
+	for _, def := range defs {
+		score := def.Score
+		if score == 0 {
+			score = turretScore
+		}
+		hp := def.HP
+		if hp == 0 {
+			hp = tuning.EnemyHP.Turret
+		}
+		turret := g.newEnemy(EnemyTypeTurret, parent.x+def.OffsetX, parent.y+def.OffsetY, 0)
+		turret.parentID = parent.id
+		turret.mount = &Mount{offsetX: def.OffsetX, offsetY: def.OffsetY, score: score}
+		turret.hp, turret.maxHP = hp, hp
+		turret.armor = def.Armor
+		turret.shooter = newShooter(0) // 主人公狙い
+		g.addEnemy(turret)
+	}
+}
+
+// findEnemy は番号から敵を探します。見つからなければnilを返します
+func (g *Game) findEnemy(id int) *Enemy {
+	for i := range g.enemies {
+		if g.enemies[i].id == id {
+			return &g.enemies[i]
+		}
+	}
+	return nil
+}
+
+// followParent は砲台を親の位置に合わせます。親がいなければ砲台も消滅させます
+func (g *Game) followParent(e *Enemy) {
+	parent := g.findEnemy(e.parentID)
+	if parent == nil {
+		e.hp = 0
+		return
+	}
+	e.x = parent.x + e.mount.offsetX
+	e.y = parent.y + e.mount.offsetY
+}
+
+// bulletDamage は装甲で減らした自機弾のダメージを返します。
+// 装甲がどれだけ厚くても最低1は通ります
+func (e *Enemy) bulletDamage(damage int) int {
+	if damage -= e.armor; damage < 1 {
+		return 1
+	}
+	return damage
+}
+
+// isShielded は砲台が残っていて本体にダメージが通らない状態かを返します
+func (e *Enemy) isShielded() bool {
+	return e.hasTurrets && !e.weakPointExposed
+}
+
+// shieldingTurrets は砲台の定義のうち、ボス本体を守るものがあるかを返します
+func shieldingTurrets(defs []TurretDef) bool {
+	for _, def := range defs {
+		if !def.Exposed {
+			return true
+		}
+	}
+	return false
+}
+
+// countParts は親に取り付けられたまま残っている砲台の数を返します
+func (g *Game) countParts(parentID int) int {
+	count := 0
+	for _, e := range g.enemies {
+		if e.parentID == parentID && e.mount != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// partsBonus はボスを倒したときに、取り付けられた砲台をすべて壊していればボスのスコアの倍率を返します
+func (g *Game) partsBonus(boss Enemy) int {
+	if boss.parts == 0 || g.countParts(boss.id) > 0 {
+		return 1
+	}
+	return bossPartsMultiplier
+}
+
+// onTurretDestroyed は砲台が破壊されたときに呼ばれ、
+// 親を守る砲台がすべてなくなったら弱点を露出させます
+func (g *Game) onTurretDestroyed(parentID int) {
+	if g.countParts(parentID) > 0 {
+		return
+	}
+	parent := g.findEnemy(parentID)
+	if parent == nil || !parent.hasTurrets || parent.weakPointExposed {
+		return
+	}
+	parent.weakPointExposed = true
+	g.addOverlay(Overlay{
+		text:  i18n.T("overlay.weakPoint"),
+		y:     int(playArea.height / 3),
+		timer: 90,
+		color: color.RGBA{255, 255, 0, 255},
+	})
+}
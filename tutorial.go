@@ -0,0 +1,206 @@
+package main
+
+import (
+	"image/color"
+	"log/slog"
+	"math"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+	"SimpleShootingStar/i18n"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const (
+	tutorialPraiseFrames = 45  // できたときの表示を出してから次の手順へ進むまでのフレーム数
+	tutorialEndFrames    = 120 // 最後の表示を出してから終えるまでのフレーム数
+	tutorialMoveDistance = 300 // 移動の手順で動かす距離（ピクセル）
+	tutorialShotFrames   = 45  // ショットの手順で撃ち続けるフレーム数
+	tutorialFocusFrames  = 60  // 低速移動の手順で押し続けるフレーム数
+)
+
+// tutorialStage はチュートリアルのステージです。敵はウェーブではなく手順ごとに出します
+var tutorialStage = Stage{Name: "Tutorial"}
+
+// TutorialStep はチュートリアルの手順です。promptを表示してspawnの敵を出し、doneがtrueになるまで待ちます
+type TutorialStep struct {
+	prompt string // 表示する案内（i18nのキー）
+	spawn  []Wave // 手順の始めに出す敵。すべていなくなったらもう一度出す
+	bomb   bool   // 手順の始めにボムが残っていなければ1つ渡す
+	done   func(g *Game, t *Tutorial) bool
+}
+
+// tutorialSteps はチュートリアルの手順の並びです
+var tutorialSteps = []TutorialStep{
+	{prompt: "tutorial.move", done: func(g *Game, t *Tutorial) bool { return t.moved >= tutorialMoveDistance }},
+	{prompt: "tutorial.shoot", done: func(g *Game, t *Tutorial) bool { return t.shotFrames >= tutorialShotFrames }},
+	{
+		prompt: "tutorial.destroy",
+		spawn:  []Wave{{EnemyType: EnemyTypeStraight, X: 310, Speed: 0.8}},
+		done:   func(g *Game, t *Tutorial) bool { return t.kills > 0 },
+	},
+	{prompt: "tutorial.focus", done: func(g *Game, t *Tutorial) bool { return t.focusFrames >= tutorialFocusFrames }},
+	{
+		prompt: "tutorial.bomb",
+		bomb:   true,
+		spawn: []Wave{
+			{EnemyType: EnemyTypeSine, X: 160, Speed: 0.8, ShootsBullet: true, BulletType: 1},
+			{EnemyType: EnemyTypeSine, X: 460, Speed: 0.8, ShootsBullet: true, BulletType: 1},
+		},
+		done: func(g *Game, t *Tutorial) bool { return t.bombed },
+	},
+}
+
+// Tutorial はチュートリアルの状態です
+type Tutorial struct {
+	firstRun bool // 初回の起動で始めたか（終えたら自機選択へ進む）
+	step     int  // 今の手順の番号（len(tutorialSteps)なら最後の表示中）
+	praise   int  // できたときの表示の残りフレーム数
+	endTimer int  // 最後の表示を出してからのフレーム数
+
+	// 今の手順の進み具合
+	moved        float64
+	shotFrames   int
+	focusFrames  int
+	kills        int
+	bombed       bool
+	lastX, lastY float64
+}
+
+func init() {
+	subscribe(EventEnemyKilled, func(g *Game, _ Event) {
+		if g.tutorial != nil {
+			g.tutorial.kills++
+		}
+	})
+	subscribe(EventBombUsed, func(g *Game, _ Event) {
+		if g.tutorial != nil {
+			g.tutorial.bombed = true
+		}
+	})
+}
+
+// startTutorial はチュートリアルを始めます。firstRunなら終えたあと自機選択へ進みます
+func (g *Game) startTutorial(firstRun bool) {
+	g.score = 0
+	g.lives = tuning.Player.InitialLives
+	g.bombs = tuning.Player.InitialBombs
+	g.resetMultiplier()
+	g.rank = 0
+	g.tutorial = &Tutorial{firstRun: firstRun}
+	g.startStage(0)
+	g.beginTutorialStep()
+}
+
+// beginTutorialStep は今の手順の進み具合を戻し、手順の敵を出します
+func (g *Game) beginTutorialStep() {
+	t := g.tutorial
+	t.moved, t.shotFrames, t.focusFrames, t.kills, t.bombed = 0, 0, 0, 0, false
+	t.lastX, t.lastY = g.playerX, g.playerY
+	if t.step >= len(tutorialSteps) {
+		return
+	}
+	step := tutorialSteps[t.step]
+	if step.bomb {
+		g.bombs = max(g.bombs, 1)
+	}
+	for _, w := range step.spawn {
+		g.spawnWave(w)
+	}
+}
+
+// updateTutorial は入力から今の手順の進み具合を数え、できたら次の手順へ進めます。
+// Escキーでいつでもやめられます
+func (g *Game) updateTutorial() {
+	t := g.tutorial
+	if t == nil || g.gameState != GameStatePlaying {
+		return
+	}
+	if g.input.JustPressed(ebiten.KeyEscape) {
+		g.endTutorial()
+		return
+	}
+	if g.stageIntroTimer > 0 {
+		return
+	}
+	if t.step >= len(tutorialSteps) {
+		t.endTimer++
+		if t.endTimer == tutorialEndFrames {
+			g.endTutorial()
+		}
+		return
+	}
+	if t.praise > 0 {
+		t.praise--
+		if t.praise == 0 {
+			t.step++
+			g.beginTutorialStep()
+		}
+		return
+	}
+
+	dx, dy := g.playerX-t.lastX, g.playerY-t.lastY
+	t.moved += math.Abs(dx) + math.Abs(dy)
+	t.lastX, t.lastY = g.playerX, g.playerY
+	if g.input.Pressed(ebiten.KeySpace) {
+		t.shotFrames++
+	}
+	if g.focused {
+		t.focusFrames++
+	}
+	step := tutorialSteps[t.step]
+	if step.done(g, t) {
+		t.praise = tutorialPraiseFrames
+		g.sound.Play("menuConfirm")
+		return
+	}
+	if len(step.spawn) > 0 && len(g.enemies) == 0 {
+		// 倒しきれずに画面外へ逃した敵を出し直す
+		for _, w := range step.spawn {
+			g.spawnWave(w)
+		}
+	}
+}
+
+// endTutorial はチュートリアルを終えたことを記録し、初回なら自機選択へ、そうでなければタイトルへ戻ります
+func (g *Game) endTutorial() {
+	firstRun := g.tutorial.firstRun
+	if !saveData.TutorialDone {
+		saveData.TutorialDone = true
+		saveStats()
+	}
+	slog.Info("チュートリアル終了", "step", g.tutorial.step)
+	g.startTransition(TransitionFade, func() {
+		// 作り直したゲームでも暗転から明けるまでの演出は続ける。ハイスコアは記録から読み直す
+		ship, input, transition := g.selectedShip, g.input, g.transition
+		*g = *NewGame()
+		g.selectedShip, g.input, g.transition = ship, input, transition
+		if firstRun {
+			g.gameState = GameStateShipSelect
+		}
+	}, nil)
+}
+
+// drawTutorial は今の手順の案内を画面の下寄りに帯に載せて描画します
+func (g *Game) drawTutorial(screen *ebiten.Image) {
+	t := g.tutorial
+	if t == nil || g.stageIntroTimer > 0 {
+		return
+	}
+	text, clr := i18n.T("tutorial.complete"), color.RGBA{255, 255, 0, 255}
+	if t.step < len(tutorialSteps) {
+		text, clr = i18n.T(tutorialSteps[t.step].prompt), color.RGBA{255, 255, 255, 255}
+		if t.praise > 0 {
+			text, clr = i18n.T("tutorial.good"), color.RGBA{120, 255, 120, 255}
+		}
+	}
+	y := resolution.Height * 3 / 4
+	ebitenutil.DrawRect(screen, 0, float64(y-ui(28)), float64(resolution.Width), float64(ui(52)), color.RGBA{0, 0, 60, 160})
+	hud.DrawTextOutline(screen, text, fonts.Face(fonts.Medium), resolution.Width/2, y, hud.AlignCenter, clr, hud.OutlineColor)
+	if t.step < len(tutorialSteps) {
+		progress := i18n.Tf("tutorial.step", t.step+1, len(tutorialSteps))
+		hud.DrawTextShadow(screen, progress, fonts.Face(fonts.Small), resolution.Width/2, y+ui(18), hud.AlignCenter, color.White)
+	}
+}
@@ -0,0 +1,103 @@
+// Package tween は決まったフレーム数をかけて値を動かすTweenと、動きの緩急を決めるイージング関数をまとめています。
+// メニューの移動・ステージ開始のバナー・ボスの登場・スコアの集計など、時間をかけて動くものはこれを使います
+package tween
+
+import "math"
+
+// Easing は経過の割合（0〜1）を動きの進み具合（0〜1）に変える関数です
+type Easing func(t float64) float64
+
+// Linear は一定の速さで動きます
+func Linear(t float64) float64 { return t }
+
+// InQuad はゆっくり動き始めて加速します
+func InQuad(t float64) float64 { return t * t }
+
+// OutQuad は速く動き始めてゆっくり止まります
+func OutQuad(t float64) float64 { return 1 - (1-t)*(1-t) }
+
+// InOutQuad はゆっくり動き始め、途中で速くなり、ゆっくり止まります
+func InOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return 1 - math.Pow(-2*t+2, 2)/2
+}
+
+// InCubic はInQuadよりも強く加速します
+func InCubic(t float64) float64 { return t * t * t }
+
+// OutCubic はOutQuadよりも強く減速して止まります
+func OutCubic(t float64) float64 { return 1 - math.Pow(1-t, 3) }
+
+// OutBack は目標を少し行き過ぎてから戻って止まります
+func OutBack(t float64) float64 {
+	const c1 = 1.70158
+	const c3 = c1 + 1
+	return 1 + c3*math.Pow(t-1, 3) + c1*math.Pow(t-1, 2)
+}
+
+// Tween はFromからToへ、Delayフレーム待ってからFramesフレームかけて値を動かします。
+// 動き終えたフレームにOnCompleteを1回だけ呼びます
+type Tween struct {
+	From, To   float64
+	Frames     int    // 動きにかけるフレーム数（0ならすぐにToになる）
+	Delay      int    // 動き始めるまでのフレーム数
+	Ease       Easing // 動きの緩急（nilならLinear）
+	OnComplete func() // 動き終えたときに呼ぶ関数（省略可）
+
+	elapsed  int
+	finished bool
+}
+
+// New はfromからtoへframesフレームで動くTweenを作ります
+func New(from, to float64, frames int, ease Easing) Tween {
+	return Tween{From: from, To: to, Frames: frames, Ease: ease}
+}
+
+// Update は1フレーム進めます。動き終えた直後のフレームでOnCompleteを呼びます
+func (t *Tween) Update() {
+	if t.finished {
+		return
+	}
+	if t.elapsed < t.Delay+t.Frames {
+		t.elapsed++
+	}
+	if t.elapsed >= t.Delay+t.Frames {
+		t.finished = true
+		if t.OnComplete != nil {
+			t.OnComplete()
+		}
+	}
+}
+
+// Progress は動きの経過の割合（0〜1、イージングをかける前）を返します
+func (t *Tween) Progress() float64 {
+	if t.Frames <= 0 {
+		if t.elapsed >= t.Delay {
+			return 1
+		}
+		return 0
+	}
+	return math.Min(math.Max(float64(t.elapsed-t.Delay)/float64(t.Frames), 0), 1)
+}
+
+// Value は今の値を返します
+func (t *Tween) Value() float64 {
+	p := t.Progress()
+	if t.Ease != nil {
+		p = t.Ease(p)
+	}
+	return t.From + (t.To-t.From)*p
+}
+
+// Done は動き終えたかを返します
+func (t *Tween) Done() bool {
+	return t.finished
+}
+
+// Finish は動きを飛ばして終わりの値にします。まだ呼んでいなければOnCompleteを呼びます
+func (t *Tween) Finish() {
+	t.elapsed = t.Delay + t.Frames
+	t.Update()
+}
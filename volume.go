@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"log/slog"
+	"math"
+	"os"
+	"strings"
+
+	"SimpleShootingStar/fonts"
+	"SimpleShootingStar/hud"
+	"SimpleShootingStar/i18n"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	volumeStep            = 0.1 // -/+キーで変える音量の幅
+	volumeIndicatorFrames = 90  // 音量の表示を出しておくフレーム数
+	volumeIndicatorBars   = 10  // 音量の表示のメモリの数
+)
+
+// updateVolumeKeys はMキーでミュート、-/+キーで全体の音量を変えます。どの画面でも効き、
+// 変えた値は設定ファイルにも書き込みます
+func (g *Game) updateVolumeKeys() {
+	volume, muted := settings.Volume, settings.Muted
+	switch {
+	case g.keyJustPressed(ebiten.KeyM):
+		muted = !muted
+	case g.keyJustPressed(ebiten.KeyMinus) || g.keyJustPressed(ebiten.KeyNumpadSubtract):
+		volume = math.Max(0, math.Round((volume-volumeStep)*10)/10)
+		muted = false
+	case g.keyJustPressed(ebiten.KeyEqual) || g.keyJustPressed(ebiten.KeyNumpadAdd):
+		volume = math.Min(1, math.Round((volume+volumeStep)*10)/10)
+		muted = false
+	default:
+		return
+	}
+	settings.Volume, settings.Muted = volume, muted
+	g.sound.SetMasterVolume(volume, muted)
+	g.volumeIndicatorTimer = volumeIndicatorFrames
+	if err := saveSettingsValues(map[string]any{"volume": volume, "muted": muted}); err != nil {
+		slog.Error("設定ファイルの書き込みに失敗", "file", settingsFile, "err", err)
+	}
+}
+
+// saveSettingsValues は設定ファイルの指定した項目だけを書き換えます。
+// 手で書いたほかの項目はそのまま残し、ファイルがなければ指定した項目だけのファイルを作ります
+func saveSettingsValues(values map[string]any) error {
+	fields := map[string]json.RawMessage{}
+	file, err := os.ReadFile(settingsFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("設定ファイルの読み込みに失敗: %v", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(file, &fields); err != nil {
+			return fmt.Errorf("JSONのパースに失敗: %v", err)
+		}
+	}
+	for key, v := range values {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		fields[key] = raw
+	}
+	out, err := json.MarshalIndent(fields, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(settingsFile, append(out, '\n'), 0644)
+}
+
+// drawVolumeIndicator は音量を変えた直後に、画面右上へ今の音量を表示します
+func (g *Game) drawVolumeIndicator(screen *ebiten.Image) {
+	if g.volumeIndicatorTimer <= 0 {
+		return
+	}
+	text := i18n.T("volume.muted")
+	if !settings.Muted {
+		filled := int(math.Round(settings.Volume * volumeIndicatorBars))
+		text = i18n.Tf("volume.level", strings.Repeat("■", filled)+strings.Repeat("□", volumeIndicatorBars-filled))
+	}
+	alpha := uint8(255 * min(1, float64(g.volumeIndicatorTimer)/20))
+	hud.DrawTextOutline(screen, text, fonts.Face(fonts.Small), resolution.Width-ui(12), ui(24), hud.AlignRight, color.RGBA{alpha, alpha, alpha, alpha}, hud.OutlineColor)
+}
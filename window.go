@@ -0,0 +1,99 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"SimpleShootingStar/i18n"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// windowTitleInterval はウィンドウのタイトルを作り直す間隔（フレーム数）です
+const windowTitleInterval = 30
+
+// windowIconSizes は用意するウィンドウのアイコンの大きさです。OSが合うものを選びます
+var windowIconSizes = []int{16, 32, 48}
+
+// windowTitle は今ウィンドウに表示しているタイトルです
+var windowTitle string
+
+// setupWindow はウィンドウのアイコンと最初のタイトルを設定します
+func setupWindow() {
+	icons := make([]image.Image, len(windowIconSizes))
+	for i, size := range windowIconSizes {
+		icons[i] = starIcon(size)
+	}
+	ebiten.SetWindowIcon(icons)
+	setWindowTitle(i18n.T("window.title"))
+}
+
+// setWindowTitle はタイトルが変わったときだけウィンドウのタイトルを書き換えます
+func setWindowTitle(title string) {
+	if title == windowTitle {
+		return
+	}
+	windowTitle = title
+	ebiten.SetWindowTitle(title)
+}
+
+// updateWindowTitle はプレイ中のステージとスコアをウィンドウのタイトルに表示します。
+// スコアは頻繁に変わるので、一定の間隔でだけ作り直します
+func (g *Game) updateWindowTitle() {
+	if headless {
+		return
+	}
+	g.windowTitleTimer--
+	if g.windowTitleTimer > 0 {
+		return
+	}
+	g.windowTitleTimer = windowTitleInterval
+	switch g.gameState {
+	case GameStatePlaying, GameStatePlayerExplosion, GameStateStageClear:
+		setWindowTitle(i18n.Tf("window.playing", i18n.T("window.title"), g.stage().Name, g.score))
+	default:
+		setWindowTitle(i18n.T("window.title"))
+	}
+}
+
+// starIcon はsize四方の、紺の丸に黄色い星を描いたアイコンを作ります
+func starIcon(size int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	c := float64(size) / 2
+	// 星の頂点（外側と内側を交互に10個）
+	var points [10][2]float64
+	for i := range points {
+		r := c * 0.9
+		if i%2 == 1 {
+			r = c * 0.38
+		}
+		a := -math.Pi/2 + float64(i)*math.Pi/5
+		points[i] = [2]float64{c + r*math.Cos(a), c + r*math.Sin(a)}
+	}
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+			switch {
+			case insidePolygon(points[:], px, py):
+				img.Set(x, y, color.RGBA{255, 220, 60, 255})
+			case math.Hypot(px-c, py-c) <= c:
+				img.Set(x, y, color.RGBA{20, 30, 80, 255})
+			}
+		}
+	}
+	return img
+}
+
+// insidePolygon は点(x, y)が多角形の内側にあるかを返します
+func insidePolygon(points [][2]float64, x, y float64) bool {
+	inside := false
+	for i, j := 0, len(points)-1; i < len(points); j, i = i, i+1 {
+		xi, yi := points[i][0], points[i][1]
+		xj, yj := points[j][0], points[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}